@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/daemon"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/offline"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/world"
+	clui "github.com/clicker-org/clicker/ui"
+	"github.com/clicker-org/clicker/ui/components/background"
+	"github.com/clicker-org/clicker/ui/screens"
+	"github.com/clicker-org/clicker/ui/theme"
+	"github.com/clicker-org/clicker/ui/theme/themes"
+)
+
+// runDaemon implements `clicker daemon`: it plays a profile without a
+// terminal, autosaving as the game does, until interrupted. `clicker attach`
+// (or plain `clicker`) shows the running game.
+func runDaemon(args []string) int {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile to play (default: last played; created if missing)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: clicker daemon [--profile name]")
+		fmt.Fprintln(fs.Output(), "Runs in the foreground until interrupted; start it with & or a service manager to keep it in the background.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	store := save.DefaultProfileStore()
+	name := resolveProfile(store, *profile)
+	if !store.Exists(name) {
+		if err := store.Create(name); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker daemon: %v\n", err)
+			return 1
+		}
+	}
+	savePath := store.SavePath(name)
+
+	lock, err := save.AcquireLock(savePath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker daemon: %v\n", err)
+		return 1
+	}
+	defer lock.Release()
+
+	syncAround(name, savePath)
+	sf, recovery, err := save.LoadWithRecovery(savePath)
+	if errors.Is(err, save.ErrNewerVersion) || errors.Is(err, save.ErrUnknownKey) {
+		// never start fresh over a save that could not be read.
+		fmt.Fprintf(os.Stderr, "clicker daemon: %v\n", err)
+		return 1
+	}
+	if err != nil {
+		log.Printf("warning: could not load save: %v", err)
+		sf = save.DefaultSaveFile()
+	}
+	if notice := recoveryNotice(recovery); notice != "" {
+		fmt.Fprintf(os.Stderr, "clicker daemon: %s\n", notice)
+	}
+
+	worldReg := world.DefaultRegistry
+	achievReg := achievement.NewAchievementRegistry()
+	achievement.RegisterDefaults(achievReg)
+	eng := engine.New(save.GameStateFromSave(sf, worldReg), worldReg, achievReg)
+	eng.Earned = sf.Achievements
+	if eng.Earned == nil {
+		eng.Earned = make(map[string]bool)
+	}
+	// time since the last save was spent offline; from here on the daemon
+	// plays in real time.
+	eng.ApplyOffline(sf.LastScreen, sf.LastWorldID, sf.SavedAt, offline.ParseClockPolicy(sf.Settings.ClockPolicy))
+	eng.StartChallenges(name, time.Now())
+	eng.StartMarket(name, time.Now())
+
+	socket := daemon.SocketPath(savePath)
+	l, err := daemon.Listen(socket)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker daemon: %v\n", err)
+		return 1
+	}
+	defer os.Remove(socket)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+	fmt.Fprintf(os.Stderr, "clicker daemon: playing profile %q (pid %d); attach with: clicker attach --profile %s\n", name, os.Getpid(), name)
+	err = daemon.NewServer(eng, savePath, sf.Settings, lock).Serve(ctx, l)
+	if errors.Is(err, daemon.ErrLockLost) {
+		fmt.Fprintf(os.Stderr, "clicker daemon: %v; stopping without saving\n", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker daemon: %v\n", err)
+		return 1
+	}
+	syncAround(name, savePath)
+	fmt.Fprintln(os.Stderr, "clicker daemon: saved and stopped")
+	return 0
+}
+
+// runAttach implements `clicker attach`: the game UI for a profile played by
+// `clicker daemon`.
+func runAttach(args []string) int {
+	fs := flag.NewFlagSet("attach", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile to attach to (default: last played)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	store := save.DefaultProfileStore()
+	name := resolveProfile(store, *profile)
+	c, err := daemon.Dial(daemon.SocketPath(store.SavePath(name)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker attach: no daemon is running for profile %q; start one with: clicker daemon --profile %s\n", name, name)
+		return 1
+	}
+	defer c.Close()
+	w, h, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || w <= 0 {
+		w, h = 80, 24
+	}
+	return attach(c, name, w, h)
+}
+
+// attach runs the game UI on the daemon behind c until the player quits or
+// the daemon stops.
+func attach(c *daemon.Client, profile string, w, h int) int {
+	worldReg := world.DefaultRegistry
+	achievReg := achievement.NewAchievementRegistry()
+	achievement.RegisterDefaults(achievReg)
+	eng := engine.New(save.GameStateFromSave(save.DefaultSaveFile(), worldReg), worldReg, achievReg)
+	m, err := daemon.NewMirror(c, eng)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker attach: %v\n", err)
+		return 1
+	}
+	settings := m.Settings()
+
+	themeReg := theme.NewThemeRegistry()
+	themeReg.Register(themes.SpaceTheme{})
+	themeReg.SetActive(settings.ActiveTheme)
+	activeTheme := themeReg.Active()
+	animReg := background.NewAnimationRegistry()
+	animReg.Register("stars", func() background.BackgroundAnimation {
+		return background.NewStarsAnimation()
+	})
+
+	// the daemon never stopped playing, so there is no offline report.
+	noReport := screens.NewOfflineReportModel(activeTheme, offline.Result{}, worldReg, achievReg)
+	app := clui.NewApp(eng, activeTheme, animReg, "", settings, noReport, w, h).
+		WithRemote(m).
+		WithStartupNotice(fmt.Sprintf("Attached to the daemon playing %q — quitting leaves it running", profile))
+	if _, err := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion()).Run(); err != nil {
+		log.Printf("error: %v", err)
+		fmt.Fprintf(os.Stderr, "clicker attach: %v\n", err)
+		return 1
+	}
+	if err := m.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker attach: lost the daemon: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -1,17 +1,23 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/term"
 	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/daemon"
 	"github.com/clicker-org/clicker/internal/engine"
 	"github.com/clicker-org/clicker/internal/offline"
+	"github.com/clicker-org/clicker/internal/replay"
 	"github.com/clicker-org/clicker/internal/save"
 	"github.com/clicker-org/clicker/internal/world"
 	_ "github.com/clicker-org/clicker/internal/world/worlds"
@@ -22,7 +28,58 @@ import (
 	"github.com/clicker-org/clicker/ui/theme/themes"
 )
 
+// subcommands maps `clicker <name>` to its implementation. Each receives the
+// arguments after the name and returns the process exit status.
+var subcommands = map[string]func(args []string) int{
+	"attach":      runAttach,
+	"daemon":      runDaemon,
+	"export":      runExport,
+	"import":      runImport,
+	"replay":      runReplay,
+	"save":        runSave,
+	"status":      runStatus,
+	"sync":        runSync,
+	"sync-server": runSyncServer,
+	"trust":       runTrust,
+}
+
+// readOnlySubcommands are the subcommands that only read saves. They leave a
+// legacy save where it is rather than move it into the default profile.
+var readOnlySubcommands = map[string]bool{
+	"attach": true,
+	"export": true,
+	"replay": true,
+	"status": true,
+}
+
+// readOnly reports whether `clicker <args>` only reads saves. `clicker save`
+// does, except for `clicker save repair`.
+func readOnly(args []string) bool {
+	if args[0] == "save" {
+		return len(args) < 2 || args[1] != "repair"
+	}
+	return readOnlySubcommands[args[0]]
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if !readOnly(os.Args[1:]) {
+				importLegacySave(save.DefaultProfileStore())
+			}
+			os.Exit(run(os.Args[2:]))
+		}
+	}
+
+	profileFlag := flag.String("profile", "", "save profile to play (skips the profile picker; created if missing)")
+	recordFlag := flag.String("record", "", "record this session's actions to `file`, for clicker replay")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: clicker [--profile name] [--record file]")
+		fmt.Fprintln(flag.CommandLine.Output(), "       clicker export|import|trust|sync|sync-server|replay|daemon|attach|status|save [flags]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
 	// set up file logging. All log.Printf calls (including those in internal
 	// packages) will write here. The file is created on first run and appended
 	// to on subsequent runs, so crash context is preserved across sessions.
@@ -44,9 +101,75 @@ func main() {
 		}
 	}()
 
+	// terminal size, used by the profile picker and the app.
+	w, h, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || w <= 0 {
+		w, h = 80, 24
+	}
+
+	// pick a save profile. A save from before profiles existed becomes the
+	// default profile.
+	profiles := save.DefaultProfileStore()
+	importLegacySave(profiles)
+	profile, ok := chooseProfile(profiles, *profileFlag, w, h)
+	if !ok {
+		return
+	}
+	if err := profiles.SetLastUsed(profile); err != nil {
+		log.Printf("warning: could not record last profile: %v", err)
+	}
+
 	// load save file.
-	savePath := save.SavePath()
-	sf, err := save.Load(savePath)
+	savePath := profiles.SavePath(profile)
+
+	// a daemon already playing this profile has no offline time to make up:
+	// show its game instead of loading the save.
+	if c, err := daemon.Dial(daemon.SocketPath(savePath)); err == nil {
+		defer c.Close()
+		if *recordFlag != "" {
+			fmt.Fprintln(os.Stderr, "clicker: --record is ignored while attached to a daemon")
+		}
+		if code := attach(c, profile, w, h); code != 0 {
+			os.Exit(code)
+		}
+		return
+	}
+
+	// take the profile's save lock so two instances never save over each other.
+	lock, readOnly, ok := lockProfile(profile, savePath, w, h)
+	if !ok {
+		return
+	}
+	if lock != nil {
+		defer lock.Release()
+	}
+	if !readOnly {
+		// pick up progress made on another machine before loading.
+		syncAround(profile, savePath)
+	}
+	var (
+		sf       save.SaveFile
+		recovery save.Recovery
+	)
+	if readOnly {
+		// the save belongs to the instance holding the lock: read it without
+		// keeping a pre-migration copy or moving it aside if it is rejected.
+		sf, err = save.Peek(savePath)
+	} else {
+		sf, recovery, err = save.LoadWithRecovery(savePath)
+	}
+	if errors.Is(err, save.ErrNewerVersion) {
+		// never start fresh over a save this build cannot read: the first
+		// autosave would destroy it.
+		fmt.Fprintf(os.Stderr, "clicker: %v — please update clicker\n", err)
+		os.Exit(1)
+	}
+	if errors.Is(err, save.ErrUnknownKey) {
+		// a save copied from another machine: never start fresh over it.
+		fmt.Fprintf(os.Stderr, "clicker: the save for profile %q was written by another install.\n", profile)
+		fmt.Fprintf(os.Stderr, "If you copied it here yourself, run: clicker trust --profile %s\n", profile)
+		os.Exit(1)
+	}
 	if err != nil {
 		log.Printf("warning: could not load save: %v", err)
 		sf = save.DefaultSaveFile()
@@ -73,9 +196,6 @@ func main() {
 	// reconstruct game state from save.
 	gs := save.GameStateFromSave(sf, worldReg)
 
-	// compute and apply offline income.
-	offlineResult := offline.Apply(sf.LastScreen, sf.LastWorldID, sf.SavedAt, &gs, worldReg)
-
 	// create engine.
 	eng := engine.New(gs, worldReg, achievReg)
 	eng.Earned = sf.Achievements
@@ -83,15 +203,26 @@ func main() {
 		eng.Earned = make(map[string]bool)
 	}
 
-	// build offline report.
-	offlineReport := screens.NewOfflineReportModel(activeTheme, offlineResult, worldReg)
+	// compute and apply offline income, evaluating any unlocks it causes.
+	offlineResult := eng.ApplyOffline(sf.LastScreen, sf.LastWorldID, sf.SavedAt,
+		offline.ParseClockPolicy(sf.Settings.ClockPolicy))
+	eng.StartChallenges(profile, time.Now())
+	eng.StartMarket(profile, time.Now())
 
-	// build and run the app.
-	w, h, err := term.GetSize(os.Stdout.Fd())
-	if err != nil || w <= 0 {
-		w, h = 80, 24
+	// record the session from here on: the snapshot includes offline income.
+	var recorder *replay.Recorder
+	if *recordFlag != "" {
+		recorder, err = replay.NewRecorder(*recordFlag, eng, sf.Settings)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "clicker: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
+	// build offline report.
+	offlineReport := screens.NewOfflineReportModel(activeTheme, offlineResult, worldReg, achievReg)
+
+	// build and run the app.
 	app := clui.NewApp(
 		eng,
 		activeTheme,
@@ -102,10 +233,145 @@ func main() {
 		w, h,
 	)
 
+	var notices []string
+	if notice := recoveryNotice(recovery); notice != "" {
+		notices = append(notices, notice)
+	}
+	if lock != nil {
+		app = app.WithLock(lock)
+	}
+	if readOnly {
+		app = app.WithReadOnly()
+		notices = append(notices, "Read-only: this profile is open in another clicker — progress will not be saved")
+	}
+	if len(notices) > 0 {
+		app = app.WithStartupNotice(strings.Join(notices, " · "))
+	}
+
 	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		log.Printf("error: %v", err)
 		fmt.Fprintf(os.Stderr, "clicker error — see %s for details\n", logPath)
 		os.Exit(1)
 	}
+	if recorder != nil {
+		recorder.End(eng)
+		if err := recorder.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker: recording incomplete: %v\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "clicker: session recorded to %s\n", *recordFlag)
+		}
+	}
+	if !readOnly && (lock == nil || lock.Held()) {
+		// push the quit save, unless another instance took the profile over.
+		syncAround(profile, savePath)
+	}
+}
+
+// importLegacySave moves a save from before profiles existed into the
+// default profile.
+func importLegacySave(store save.ProfileStore) {
+	if err := store.ImportLegacy(save.LegacySavePath()); err != nil {
+		log.Printf("warning: could not import legacy save: %v", err)
+	}
+}
+
+// chooseProfile returns the profile to play. A name given with --profile is
+// used directly (and created if missing); otherwise the profile picker is
+// shown. ok is false if the player quit the picker or the name is invalid.
+func chooseProfile(store save.ProfileStore, flagName string, w, h int) (string, bool) {
+	if flagName != "" {
+		if err := save.ValidateProfileName(flagName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if !store.Exists(flagName) {
+			if err := store.Create(flagName); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		}
+		return flagName, true
+	}
+
+	// a first run has nothing to pick from; start in the default profile.
+	names, err := store.Names()
+	if err != nil {
+		log.Printf("warning: %v", err)
+	}
+	if len(names) == 0 {
+		if err := store.Create(save.DefaultProfileName); err != nil {
+			log.Printf("warning: %v", err)
+		}
+		return save.DefaultProfileName, true
+	}
+
+	picker := clui.NewProfilePicker(startupTheme(), store, store.LastUsed(), w, h)
+	final, err := tea.NewProgram(picker, tea.WithAltScreen()).Run()
+	if err != nil {
+		log.Printf("error: profile picker: %v", err)
+		return "", false
+	}
+	chosen := final.(clui.ProfilePicker).Chosen()
+	return chosen, chosen != ""
+}
+
+// lockProfile takes the save lock for the chosen profile. If another clicker
+// holds it, the player is asked whether to open read-only, take over or quit.
+// ok is false if they chose to quit. A nil lock with readOnly false means the
+// lock could not be used at all and the game runs unguarded.
+func lockProfile(profile, savePath string, w, h int) (lock *save.Lock, readOnly, ok bool) {
+	lock, err := save.AcquireLock(savePath)
+	if err == nil {
+		return lock, false, true
+	}
+	var locked *save.LockedError
+	if !errors.As(err, &locked) {
+		log.Printf("warning: could not lock save: %v", err)
+		return nil, false, true
+	}
+
+	prompt := clui.NewLockPrompt(startupTheme(), profile, locked.Holder, w, h)
+	final, err := tea.NewProgram(prompt, tea.WithAltScreen()).Run()
+	if err != nil {
+		log.Printf("error: lock prompt: %v", err)
+		return nil, false, false
+	}
+	switch final.(clui.LockPrompt).Choice() {
+	case clui.LockChoiceReadOnly:
+		return nil, true, true
+	case clui.LockChoiceTakeOver:
+		lock, err := save.ForceLock(savePath)
+		if err != nil {
+			log.Printf("warning: could not take over lock: %v", err)
+			return nil, true, true
+		}
+		log.Printf("took over save lock from %s", locked.Holder)
+		return lock, false, true
+	default:
+		return nil, false, false
+	}
+}
+
+// startupTheme returns the theme for screens shown before a save is loaded.
+func startupTheme() theme.Theme {
+	themeReg := theme.NewThemeRegistry()
+	themeReg.Register(themes.SpaceTheme{})
+	return themeReg.Active()
+}
+
+// recoveryNotice returns the message shown to the player when their save file
+// had to be replaced on load, or "" if it loaded normally.
+func recoveryNotice(rec save.Recovery) string {
+	if !rec.Recovered() {
+		return ""
+	}
+	kept := ""
+	if rec.CorruptPath != "" {
+		kept = " (damaged file kept as " + filepath.Base(rec.CorruptPath) + ")"
+	}
+	if rec.RestoredFrom != "" {
+		return "Save file was damaged — restored from backup " + filepath.Base(rec.RestoredFrom) + kept
+	}
+	return "Save file was damaged and no backup was usable — starting fresh" + kept
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/term"
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/replay"
+	"github.com/clicker-org/clicker/internal/world"
+	clui "github.com/clicker-org/clicker/ui"
+)
+
+// runReplay implements `clicker replay`: it re-runs an action log recorded
+// with `clicker --record` through the engine and checks that it reaches the
+// recorded final state. With --watch it plays the log back in the TUI first.
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	watch := fs.Bool("watch", false, "play the log back visually")
+	speed := fs.Float64("speed", 10, "playback speed for --watch, as a multiple of real time")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: clicker replay [--watch] [--speed n] session.jsonl")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	path := fs.Arg(0)
+	l, err := replay.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker replay: %v\n", err)
+		return 1
+	}
+
+	worldReg := world.DefaultRegistry
+	achievReg := achievement.NewAchievementRegistry()
+	achievement.RegisterDefaults(achievReg)
+
+	var eng *engine.Engine
+	if *watch {
+		w, h, err := term.GetSize(os.Stdout.Fd())
+		if err != nil || w <= 0 {
+			w, h = 80, 24
+		}
+		p := replay.NewPlayer(l, worldReg, achievReg)
+		viewer := clui.NewReplayViewer(startupTheme(), filepath.Base(path), p, *speed, w, h)
+		final, err := tea.NewProgram(viewer, tea.WithAltScreen()).Run()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "clicker replay: %v\n", err)
+			return 1
+		}
+		if err := final.(clui.ReplayViewer).Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker replay: %v\n", err)
+			return 1
+		}
+		if !p.Done() {
+			fmt.Println("Playback stopped before the end; final state not checked.")
+			return 0
+		}
+		eng = p.Engine()
+	} else {
+		eng, err = replay.Run(l, worldReg, achievReg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "clicker replay: %v\n", err)
+			return 1
+		}
+	}
+
+	ticks := l.TotalTicks()
+	fmt.Printf("Replayed %d actions over %d ticks (%.0fs of play).\n",
+		len(l.Actions), ticks, eng.State.Player.TotalPlaySeconds-l.Initial.Player.TotalPlaySeconds)
+	if l.Final == nil {
+		fmt.Println("The recording has no final state (the session did not quit cleanly); nothing to check.")
+		return 0
+	}
+	diffs := replay.Check(*l.Final, eng)
+	if len(diffs) == 0 {
+		fmt.Println("Final state matches the recording.")
+		return 0
+	}
+	fmt.Printf("Final state differs from the recording in %d places:\n", len(diffs))
+	for _, d := range diffs {
+		fmt.Println("  " + d)
+	}
+	return 1
+}
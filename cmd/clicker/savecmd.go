@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/world"
+)
+
+// saveCommands are the subcommands of `clicker save`.
+var saveCommands = map[string]func(args []string) int{
+	"inspect": runSaveInspect,
+	"verify":  runSaveVerify,
+	"diff":    runSaveDiff,
+	"repair":  runSaveRepair,
+}
+
+// runSave implements `clicker save`: tools for looking into and fixing save
+// files without decoding the envelope by hand.
+func runSave(args []string) int {
+	if len(args) > 0 {
+		if run, ok := saveCommands[args[0]]; ok {
+			return run(args[1:])
+		}
+	}
+	fmt.Fprintln(os.Stderr, "usage: clicker save inspect [--profile name] [file]")
+	fmt.Fprintln(os.Stderr, "       clicker save verify [--profile name] [file...]")
+	fmt.Fprintln(os.Stderr, "       clicker save diff [--gameplay] a b   (files or profile names)")
+	fmt.Fprintln(os.Stderr, "       clicker save repair [--profile name] [--yes] [file]")
+	return 2
+}
+
+// saveTarget returns the file a `clicker save` subcommand acts on: the file
+// argument if given, else the profile's save.
+func saveTarget(fs *flag.FlagSet, profile string) string {
+	if fs.NArg() > 0 {
+		return fs.Arg(0)
+	}
+	store := save.DefaultProfileStore()
+	return store.SavePath(resolveProfile(store, profile))
+}
+
+// saveOrProfile resolves an argument of `clicker save diff`: a file if one
+// exists at arg, else the save of the profile named arg.
+func saveOrProfile(arg string) string {
+	if _, err := os.Stat(arg); err == nil {
+		return arg
+	}
+	store := save.DefaultProfileStore()
+	if save.ValidateProfileName(arg) == nil && store.Exists(arg) {
+		return store.SavePath(arg)
+	}
+	return arg
+}
+
+// signatureLine describes sig in one line.
+func signatureLine(sig save.SignatureInfo) string {
+	switch {
+	case sig.Err == nil && sig.Legacy:
+		return "ok (built-in legacy key; re-signed on the next save)"
+	case sig.Err == nil:
+		return "ok (this install's key)"
+	case errors.Is(sig.Err, save.ErrUnknownKey):
+		return "signed by another install — accept it with clicker trust"
+	default:
+		return "INVALID: " + sig.Err.Error()
+	}
+}
+
+func runSaveInspect(args []string) int {
+	fs := flag.NewFlagSet("save inspect", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile whose save to inspect (default: last played)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	path := saveTarget(fs, *profile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker save: %v\n", err)
+		return 1
+	}
+	ins := save.Inspect(data)
+	sig := ins.Signature
+
+	fmt.Printf("File:       %s (%d bytes)\n", path, len(data))
+	fmt.Printf("Envelope:   format %d, key %s, %s\n", sig.Format, orNone(sig.KeyID), orNone(sig.Alg))
+	fmt.Printf("Signature:  %s\n", signatureLine(sig))
+	if ins.Err != nil {
+		fmt.Printf("Payload:    UNREADABLE: %v\n", ins.Err)
+		return 1
+	}
+	if ins.Version < save.CurrentVersion {
+		fmt.Printf("Schema:     version %d (shown migrated to %d)\n", ins.Version, save.CurrentVersion)
+	} else {
+		fmt.Printf("Schema:     version %d\n", ins.Version)
+	}
+	out, err := json.MarshalIndent(ins.Save, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker save: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Payload:\n%s\n", out)
+	return 0
+}
+
+// runSaveVerify checks signatures and payloads. Without file arguments it
+// checks the profile's save and its backups. It exits 1 if any file fails.
+func runSaveVerify(args []string) int {
+	fs := flag.NewFlagSet("save verify", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile whose save and backups to verify (default: last played)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		path := saveTarget(fs, *profile)
+		paths = []string{path}
+		for n := 1; n <= save.BackupCount; n++ {
+			if _, err := os.Stat(save.BackupPath(path, n)); err == nil {
+				paths = append(paths, save.BackupPath(path, n))
+			}
+		}
+	}
+
+	failed := false
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("FAIL  %s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		ins := save.Inspect(data)
+		switch {
+		case ins.Err != nil:
+			fmt.Printf("FAIL  %s: payload unreadable: %v\n", path, ins.Err)
+			failed = true
+		case ins.Signature.Err != nil:
+			fmt.Printf("FAIL  %s: %s\n", path, signatureLine(ins.Signature))
+			failed = true
+		default:
+			fmt.Printf("ok    %s (key %s, schema version %d)\n", path, ins.Signature.KeyID, ins.Version)
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// runSaveDiff prints the fields that differ between two saves. Like diff(1)
+// it exits 0 if they are the same and 1 if they differ.
+func runSaveDiff(args []string) int {
+	fs := flag.NewFlagSet("save diff", flag.ContinueOnError)
+	gameplay := fs.Bool("gameplay", false, "leave out when and where the saves were made, clock bookkeeping and settings")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: clicker save diff [--gameplay] a b")
+		fmt.Fprintln(fs.Output(), "a and b are save files or profile names.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	var saves [2]save.SaveFile
+	var paths [2]string
+	for i := range saves {
+		paths[i] = saveOrProfile(fs.Arg(i))
+		data, err := os.ReadFile(paths[i])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "clicker save: %v\n", err)
+			return 2
+		}
+		ins := save.Inspect(data)
+		if ins.Err != nil {
+			fmt.Fprintf(os.Stderr, "clicker save: %s: payload unreadable: %v\n", paths[i], ins.Err)
+			return 2
+		}
+		if ins.Signature.Err != nil {
+			fmt.Fprintf(os.Stderr, "note: %s: %s\n", paths[i], signatureLine(ins.Signature))
+		}
+		saves[i] = ins.Save
+	}
+
+	var opts save.DiffOptions
+	if *gameplay {
+		opts.Ignore = save.BookkeepingFields
+	}
+	diffs := save.Diff(saves[0], saves[1], opts)
+	if len(diffs) == 0 {
+		fmt.Println("The saves are the same.")
+		return 0
+	}
+	fmt.Printf("--- %s\n+++ %s\n", paths[0], paths[1])
+	printDiffs(diffs)
+	return 1
+}
+
+func printDiffs(diffs []save.FieldDiff) {
+	width := 0
+	for _, d := range diffs {
+		width = max(width, len(d.Path))
+	}
+	for _, d := range diffs {
+		fmt.Printf("  %-*s  %s → %s\n", width, d.Path, orNone(d.A), orNone(d.B))
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// runSaveRepair recomputes what the save derives from the world configs (see
+// engine.Repair) and, once confirmed, writes it back signed with this
+// install's key.
+func runSaveRepair(args []string) int {
+	fs := flag.NewFlagSet("save repair", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile whose save to repair (default: last played)")
+	yes := fs.Bool("yes", false, "write the repaired save without asking")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	path := saveTarget(fs, *profile)
+
+	lock, err := save.AcquireLock(path)
+	if errors.Is(err, save.ErrLocked) {
+		fmt.Fprintf(os.Stderr, "clicker save: %v — quit it first\n", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker save: %v\n", err)
+		return 1
+	}
+	defer lock.Release()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker save: %v\n", err)
+		return 1
+	}
+	ins := save.Inspect(data)
+	if ins.Err != nil {
+		fmt.Fprintf(os.Stderr, "clicker save: the payload is unreadable and cannot be repaired: %v\n", ins.Err)
+		fmt.Fprintf(os.Stderr, "Try a backup instead: clicker save verify shows which ones are intact.\n")
+		return 1
+	}
+	sf := ins.Save
+
+	worldReg := world.DefaultRegistry
+	eng := engine.New(save.GameStateFromSave(sf, worldReg), worldReg, achievement.NewAchievementRegistry())
+	fixes := eng.Repair()
+	repaired := save.SaveFileFromGameState(eng.State, sf.Achievements, sf.Settings)
+	// keep the offline time owed since the save was made.
+	repaired.SavedAt = sf.SavedAt
+	diffs := save.Diff(sf, repaired, save.DiffOptions{})
+	resign := ins.Signature.Err != nil || ins.Signature.Legacy
+
+	if len(diffs) == 0 && !resign && ins.Version == save.CurrentVersion {
+		fmt.Printf("Nothing to repair in %s.\n", path)
+		return 0
+	}
+	fmt.Printf("Repairing %s:\n", path)
+	for _, f := range fixes {
+		fmt.Printf("  - %s\n", f)
+	}
+	if len(diffs) > 0 {
+		fmt.Println("Fields that change:")
+		printDiffs(diffs)
+	}
+	if ins.Version < save.CurrentVersion {
+		fmt.Printf("  - schema version %d → %d\n", ins.Version, save.CurrentVersion)
+	}
+	if resign {
+		fmt.Printf("  - re-sign with this install's key (signature: %s)\n", signatureLine(ins.Signature))
+	}
+
+	if !*yes {
+		fmt.Print("Write the repaired save? The current one is kept as a backup. [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("Nothing was changed.")
+			return 1
+		}
+	}
+	if err := save.WriteSaveFile(repaired, path); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker save: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Repaired. The previous save is %s\n", save.BackupPath(path, 1))
+	return 0
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/daemon"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/player"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/world"
+)
+
+// defaultStatusFormat is the line `clicker status` prints without --format.
+const defaultStatusFormat = `{{.Symbol}} {{coins .Projected}} +{{cps .CPS}}/s · {{coins .GC}} GC · L{{.Level}}{{if .PrestigeReady}} · prestige ready{{end}}`
+
+// status is what `clicker status` reports, as template fields and as JSON.
+type status struct {
+	Profile   string `json:"profile"`
+	World     string `json:"world"`
+	WorldName string `json:"world_name"`
+	Symbol    string `json:"symbol"`
+	// Coins is the world balance as saved; Projected adds the CPS earned
+	// since then. They are equal when read from a running daemon.
+	Coins     float64 `json:"coins"`
+	Projected float64 `json:"projected"`
+	CPS       float64 `json:"cps"`
+	GC        float64 `json:"gc"`
+	Level     int     `json:"level"`
+	XP        int     `json:"xp"`
+	XPToNext  int     `json:"xp_to_next"`
+	// PrestigeReady and PrestigeProgress (0 to 1) use the projected state.
+	PrestigeReady    bool      `json:"prestige_ready"`
+	PrestigeProgress float64   `json:"prestige_progress"`
+	Live             bool      `json:"live"`
+	SavedAt          time.Time `json:"saved_at"`
+}
+
+var statusFuncs = template.FuncMap{
+	"coins": economy.FormatCoinsBare,
+	"cps":   economy.FormatCPS,
+	"pct":   func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+}
+
+// runStatus implements `clicker status`: a one-line summary of a profile for
+// shell prompts and status bars. It reads a running daemon if there is one
+// and the save file otherwise, and never takes the save lock or writes.
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile to report on (default: last played)")
+	worldID := fs.String("world", "", "world to report on (default: the last one played)")
+	format := fs.String("format", defaultStatusFormat, "Go template for the line")
+	asJSON := fs.Bool("json", false, "print the fields as JSON instead")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: clicker status [--profile name] [--world id] [--format template | --json]")
+		fmt.Fprintln(fs.Output(), "Template fields: .Profile .World .WorldName .Symbol .Coins .Projected .CPS .GC")
+		fmt.Fprintln(fs.Output(), "  .Level .XP .XPToNext .PrestigeReady .PrestigeProgress .Live .SavedAt")
+		fmt.Fprintln(fs.Output(), "Functions: coins, cps (SI suffixes), pct (0.42 → 42%)")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	tmpl, err := template.New("status").Funcs(statusFuncs).Parse(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker status: --format: %v\n", err)
+		return 2
+	}
+
+	store := save.DefaultProfileStore()
+	name := resolveProfile(store, *profile)
+	path := store.SavePath(name)
+
+	var sf save.SaveFile
+	live := false
+	if c, err := daemon.Dial(daemon.SocketPath(path)); err == nil {
+		snap, err := c.Snapshot(math.MaxUint64)
+		c.Close()
+		if err == nil {
+			sf, live = snap.Save, true
+		}
+	}
+	if !live {
+		if sf, err = save.Peek(path); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker status: %v\n", err)
+			return 1
+		}
+	}
+
+	st, err := buildStatus(name, sf, *worldID, live, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker status: %v\n", err)
+		return 2
+	}
+	if *asJSON {
+		if err := json.NewEncoder(os.Stdout).Encode(st); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker status: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, st); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker status: %v\n", err)
+		return 1
+	}
+	fmt.Println(sb.String())
+	return 0
+}
+
+// buildStatus summarizes sf for worldID, or the world last played. Unless
+// the save is live, income since it was saved is projected at the saved CPS.
+func buildStatus(profile string, sf save.SaveFile, worldID string, live bool, now time.Time) (status, error) {
+	worldReg := world.DefaultRegistry
+	chosen := worldID != ""
+	if !chosen {
+		worldID = sf.LastWorldID
+	}
+	w, ok := worldReg.Get(worldID)
+	if !ok && !chosen {
+		// nothing played yet: report the first world.
+		if ids := worldReg.IDs(); len(ids) > 0 {
+			worldID = ids[0]
+			w, ok = worldReg.Get(worldID)
+		}
+	}
+	if !ok {
+		return status{}, fmt.Errorf("unknown world %q (have %s)", worldID, strings.Join(worldReg.IDs(), ", "))
+	}
+
+	eng := engine.New(save.GameStateFromSave(sf, worldReg), worldReg, achievement.NewAchievementRegistry())
+	ws := eng.State.Worlds[worldID]
+	p := eng.State.Player
+	st := status{
+		Profile:   profile,
+		World:     worldID,
+		WorldName: w.Name(),
+		Symbol:    w.CoinSymbol(),
+		Coins:     ws.Coins,
+		Projected: ws.Coins,
+		CPS:       ws.CPS,
+		GC:        p.GeneralCoins,
+		Level:     p.Level,
+		XP:        p.XP,
+		XPToNext:  player.XPForLevel(p.Level+1) - p.XP,
+		Live:      live,
+		SavedAt:   sf.SavedAt,
+	}
+	if elapsed := now.Sub(sf.SavedAt).Seconds(); !live && elapsed > 0 && !sf.SavedAt.IsZero() {
+		earned := ws.CPS * elapsed
+		ws.Coins += earned
+		ws.TotalCoinsEarned += earned
+		st.Projected = ws.Coins
+	}
+	st.PrestigeReady = eng.CanPrestige(worldID)
+	st.PrestigeProgress = eng.PrestigeProgress(worldID).Fraction()
+	return st, nil
+}
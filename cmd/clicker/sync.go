@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/save"
+)
+
+// syncTimeout bounds one sync with a remote, so an unreachable server delays
+// launch by seconds rather than minutes.
+const syncTimeout = 20 * time.Second
+
+// runSync implements `clicker sync`: it configures a profile's remote and
+// brings the local save and the remote in step, asking which to keep if both
+// changed.
+func runSync(args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile to sync (default: last played)")
+	remote := fs.String("remote", "", "set the profile's remote: a directory or an http(s):// sync-server URL")
+	disable := fs.Bool("disable", false, "stop syncing this profile")
+	keep := fs.String("keep", "", "resolve a conflict without asking: local or remote")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *keep != "" && *keep != "local" && *keep != "remote" {
+		fmt.Fprintln(os.Stderr, "clicker sync: --keep must be local or remote")
+		return 2
+	}
+
+	store := save.DefaultProfileStore()
+	name := resolveProfile(store, *profile)
+	path := store.SavePath(name)
+	cfg, err := save.LoadSyncConfig(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+		return 1
+	}
+	switch {
+	case *disable:
+		if err := os.Remove(save.SyncConfigPath(path)); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Sync disabled for profile %q.\n", name)
+		return 0
+	case *remote != "":
+		if _, err := save.OpenBackend(*remote); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+			return 1
+		}
+		if !store.Exists(name) {
+			if err := store.Create(name); err != nil {
+				fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+				return 1
+			}
+		}
+		if *remote != cfg.Remote {
+			// a new remote shares no history with the old one.
+			cfg = save.SyncConfig{Remote: *remote}
+			if err := save.WriteSyncConfig(path, cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+				return 1
+			}
+		}
+	case cfg.Remote == "":
+		fmt.Fprintf(os.Stderr, "clicker sync: profile %q has no remote; set one with --remote\n", name)
+		return 1
+	}
+
+	lock, err := save.AcquireLock(path)
+	if errors.Is(err, save.ErrLocked) {
+		fmt.Fprintf(os.Stderr, "clicker sync: %v — it syncs when it quits\n", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+		return 1
+	}
+	defer lock.Release()
+
+	syncer, err := save.ProfileSyncer(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+		return 1
+	}
+	if err := syncProfile(syncer, name, *keep, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker sync: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// syncAround runs a sync for the game's launch or quit, if the profile has a
+// remote. Failures are reported but never stop the game: the local save is
+// always playable, and the next sync catches up.
+func syncAround(profile, savePath string) {
+	syncer, err := save.ProfileSyncer(savePath)
+	if err != nil {
+		log.Printf("warning: sync: %v", err)
+		fmt.Fprintf(os.Stderr, "clicker: sync skipped: %v\n", err)
+		return
+	}
+	if syncer == nil {
+		return
+	}
+	if err := syncProfile(syncer, profile, "", os.Stderr); err != nil {
+		log.Printf("warning: sync: %v", err)
+		fmt.Fprintf(os.Stderr, "clicker: sync with %s failed: %v\n", syncer.Backend, err)
+	}
+}
+
+// syncProfile syncs once and, on a conflict, resolves it as keep says or
+// asks on stdin. A remote save from another install is only pulled once the
+// player trusts that install on stdin.
+func syncProfile(syncer *save.Syncer, profile, keep string, out io.Writer) error {
+	in := bufio.NewReader(os.Stdin)
+	syncer.Trust = func(keyID string, remote save.SaveFile) bool {
+		return askTrust(in, out, profile, syncer.Backend, keyID, remote)
+	}
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+		res, err := syncer.Sync(ctx)
+		if err == nil && res.Outcome == save.SyncConflict {
+			choice := keep
+			if choice == "" {
+				choice = askConflict(in, out, profile, syncer.Backend, res.Conflict)
+			}
+			switch choice {
+			case "local", "remote":
+				res, err = syncer.Resolve(ctx, res.Conflict, choice == "local")
+			default:
+				cancel()
+				fmt.Fprintln(out, "Sync skipped; nothing was changed.")
+				return nil
+			}
+		}
+		cancel()
+		if err != nil {
+			return err
+		}
+		if res.Outcome == save.SyncConflict {
+			// the remote changed again while we asked.
+			continue
+		}
+		reportSync(out, profile, syncer.Backend, res)
+		return nil
+	}
+}
+
+func reportSync(out io.Writer, profile string, backend save.Backend, res save.SyncResult) {
+	switch res.Outcome {
+	case save.SyncPushed:
+		fmt.Fprintf(out, "Synced %q to %s (revision %d).\n", profile, backend, res.Rev.Number)
+	case save.SyncPulled:
+		fmt.Fprintf(out, "Synced %q from %s: revision %d, saved by %s %s.\n",
+			profile, backend, res.Rev.Number, res.Rev.Writer, when(res.Rev.Time))
+	default:
+		fmt.Fprintf(out, "Profile %q is up to date with %s.\n", profile, backend)
+	}
+}
+
+// askConflict shows both saves and reads the player's choice: "local",
+// "remote", or "" to skip.
+func askConflict(in *bufio.Reader, out io.Writer, profile string, backend save.Backend, c *save.Conflict) string {
+	fmt.Fprintf(out, "Sync conflict for profile %q: this machine and %s both changed since the last sync.\n", profile, backend)
+	fmt.Fprintf(out, "  [l] local   %s\n", conflictLine(c.Local))
+	fmt.Fprintf(out, "  [r] remote  %s  (revision %d by %s)\n", conflictLine(c.Remote), c.RemoteRev.Number, c.RemoteRev.Writer)
+	fmt.Fprintln(out, "The save you do not keep stays in the local backups.")
+	for {
+		fmt.Fprint(out, "Keep [l]ocal, [r]emote, or [s]kip for now? ")
+		answer, err := in.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "l", "local":
+			return "local"
+		case "r", "remote":
+			return "remote"
+		case "s", "skip":
+			return ""
+		}
+		if err != nil {
+			return ""
+		}
+	}
+}
+
+// askTrust shows a remote save signed by another install and asks, as
+// `clicker trust` does, whether to trust that install's saves.
+func askTrust(in *bufio.Reader, out io.Writer, profile string, backend save.Backend, keyID string, remote save.SaveFile) bool {
+	fmt.Fprintf(out, "The save for profile %q on %s was signed by another install (key %s).\n", profile, backend, keyID)
+	fmt.Fprintf(out, "  remote  %s\n", conflictLine(save.ProfileSummary{
+		Level:        remote.Player.Level,
+		GeneralCoins: remote.Player.GeneralCoins,
+		LastPlayed:   remote.SavedAt,
+	}))
+	fmt.Fprintf(out, "Only trust installs you play on yourself. Trust saves signed with key %s? [y/N] ", keyID)
+	answer, _ := in.ReadString('\n')
+	a := strings.ToLower(strings.TrimSpace(answer))
+	return a == "y" || a == "yes"
+}
+
+func conflictLine(s save.ProfileSummary) string {
+	if s.Err != nil {
+		return "unreadable: " + s.Err.Error()
+	}
+	return fmt.Sprintf("LVL %-3d %-10s saved %s", s.Level, economy.FormatCoins(s.GeneralCoins, "GC"), when(s.LastPlayed))
+}
+
+func when(t time.Time) string {
+	if t.IsZero() {
+		return "at an unknown time"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// runSyncServer implements `clicker sync-server`: a small HTTP server that
+// stores one save per profile under a directory, for `clicker sync --remote
+// http://host:port/<profile>`.
+func runSyncServer(args []string) int {
+	fs := flag.NewFlagSet("sync-server", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:8754", "address to listen on")
+	dir := fs.String("dir", "", "directory to store saves in (required)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: clicker sync-server --dir path [--addr host:port]")
+		fmt.Fprintf(fs.Output(), "Requires the bearer token in $%s if it is set.\n", save.SyncTokenEnv)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *dir == "" {
+		fs.Usage()
+		return 2
+	}
+
+	srv := &profileServer{dir: *dir, token: strings.TrimSpace(os.Getenv(save.SyncTokenEnv))}
+	mux := http.NewServeMux()
+	mux.Handle("/{profile}", srv)
+	fmt.Fprintf(os.Stderr, "clicker sync-server: serving %s on http://%s/<profile>\n", *dir, *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker sync-server: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// profileServer routes /<profile> to a sync handler for that profile's
+// directory, reusing one handler per profile so its writes stay serialized.
+type profileServer struct {
+	dir   string
+	token string
+
+	mu       sync.Mutex
+	handlers map[string]http.Handler
+}
+
+func (s *profileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("profile")
+	if err := save.ValidateProfileName(name); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	s.mu.Lock()
+	h, ok := s.handlers[name]
+	if !ok {
+		if s.handlers == nil {
+			s.handlers = make(map[string]http.Handler)
+		}
+		h = save.NewSyncHandler(save.NewDirBackend(filepath.Join(s.dir, name)), s.token)
+		s.handlers[name] = h
+	}
+	s.mu.Unlock()
+	h.ServeHTTP(w, r)
+}
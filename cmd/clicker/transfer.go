@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/ui/components"
+)
+
+// runExport implements `clicker export`: it prints a profile's save as an
+// export code that `clicker import` (or the in-game settings screen) accepts.
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile to export (default: last played)")
+	copyCode := fs.Bool("copy", false, "also copy the code to the clipboard (OSC 52)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	store := save.DefaultProfileStore()
+	name := resolveProfile(store, *profile)
+	path := store.SavePath(name)
+	if _, err := os.Stat(path); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker export: profile %q has no save\n", name)
+		return 1
+	}
+	sf, err := save.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker export: %v\n", err)
+		return 1
+	}
+	code, err := save.Export(sf)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker export: %v\n", err)
+		return 1
+	}
+	fmt.Println(code)
+	if *copyCode {
+		if err := components.WriteClipboard(code); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker export: copy to clipboard: %v\n", err)
+		}
+	}
+	return 0
+}
+
+// runImport implements `clicker import`: it reads an export code from the
+// command line or stdin, shows what it would overwrite and asks before
+// replacing the profile's save.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile to import into (default: last played; created if missing)")
+	yes := fs.Bool("yes", false, "overwrite without asking")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: clicker import [--profile name] [--yes] [code]")
+		fmt.Fprintln(fs.Output(), "Reads the code from stdin if it is not given as an argument.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	code := strings.Join(fs.Args(), "")
+	if code == "" {
+		fmt.Fprint(os.Stderr, "Paste export code: ")
+		line, err := in.ReadString('\n')
+		if err != nil && err != io.EOF {
+			fmt.Fprintf(os.Stderr, "clicker import: %v\n", err)
+			return 1
+		}
+		code = line
+	}
+	incoming, err := save.ParseExport(code)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker import: %v\n", err)
+		return 1
+	}
+
+	store := save.DefaultProfileStore()
+	name := resolveProfile(store, *profile)
+	if !store.Exists(name) {
+		if err := store.Create(name); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker import: %v\n", err)
+			return 1
+		}
+	}
+	path := store.SavePath(name)
+	lock, err := save.AcquireLock(path)
+	if errors.Is(err, save.ErrLocked) {
+		fmt.Fprintf(os.Stderr, "clicker import: %v — quit it first\n", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker import: %v\n", err)
+		return 1
+	}
+	defer lock.Release()
+	_, statErr := os.Stat(path)
+	hadSave := statErr == nil
+	current, err := save.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker import: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Import into profile %q (current → imported):\n", name)
+	for _, c := range save.PreviewImport(current, incoming) {
+		mark := " "
+		if c.Changed() {
+			mark = "*"
+		}
+		fmt.Printf(" %s %-22s %12s → %s\n", mark, c.Field, c.From, c.To)
+	}
+
+	if !*yes {
+		fmt.Print("Overwrite this save? [y/N] ")
+		answer, _ := in.ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("Import cancelled.")
+			return 1
+		}
+	}
+	if _, err := save.Import(incoming, current.Settings, path); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker import: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Imported into profile %q.\n", name)
+	if hadSave {
+		fmt.Printf("The previous save was kept as %s\n", save.BackupPath(path, 1))
+	}
+	return 0
+}
+
+// runTrust implements `clicker trust`: it accepts a save file this install
+// cannot verify — usually one copied from another machine — and re-signs it
+// with this install's key. Without a file argument it trusts the profile's
+// own save in place.
+func runTrust(args []string) int {
+	fs := flag.NewFlagSet("trust", flag.ContinueOnError)
+	profile := fs.String("profile", "", "profile to trust the save into (default: last played; created if missing)")
+	yes := fs.Bool("yes", false, "trust without asking")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: clicker trust [--profile name] [--yes] [save.json]")
+		fmt.Fprintln(fs.Output(), "Trusts the profile's own save if no file is given.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	store := save.DefaultProfileStore()
+	name := resolveProfile(store, *profile)
+	path := store.SavePath(name)
+	src := path
+	if fs.NArg() > 0 {
+		src = fs.Arg(0)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker trust: %v\n", err)
+		return 1
+	}
+	sig := save.InspectSignature(data)
+	if sig.Err == nil && src == path {
+		fmt.Printf("The save for profile %q is already trusted by this install.\n", name)
+		return 0
+	}
+	incoming, err := save.DecodeTrusted(data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker trust: %s is not a readable save: %v\n", src, err)
+		return 1
+	}
+
+	if !store.Exists(name) {
+		if err := store.Create(name); err != nil {
+			fmt.Fprintf(os.Stderr, "clicker trust: %v\n", err)
+			return 1
+		}
+	}
+	lock, err := save.AcquireLock(path)
+	if errors.Is(err, save.ErrLocked) {
+		fmt.Fprintf(os.Stderr, "clicker trust: %v — quit it first\n", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "clicker trust: %v\n", err)
+		return 1
+	}
+	defer lock.Release()
+
+	switch {
+	case sig.Err == nil:
+		fmt.Printf("%s is signed with key %s, which this install trusts.\n", src, sig.KeyID)
+	case errors.Is(sig.Err, save.ErrUnknownKey):
+		fmt.Printf("%s was signed by another install (key %s).\n", src, sig.KeyID)
+	default:
+		fmt.Printf("%s does not verify: %v\n", src, sig.Err)
+	}
+	settings := incoming.Settings
+	if src != path {
+		if current, err := save.Load(path); err == nil {
+			settings = current.Settings
+			fmt.Printf("Trust into profile %q (current → trusted):\n", name)
+			for _, c := range save.PreviewImport(current, incoming) {
+				mark := " "
+				if c.Changed() {
+					mark = "*"
+				}
+				fmt.Printf(" %s %-22s %12s → %s\n", mark, c.Field, c.From, c.To)
+			}
+		}
+	}
+	if !*yes {
+		fmt.Print("Only trust saves you moved here yourself. Trust this save? [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+			fmt.Println("Nothing was changed.")
+			return 1
+		}
+	}
+	if _, err := save.Import(incoming, settings, path); err != nil {
+		fmt.Fprintf(os.Stderr, "clicker trust: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Save trusted and re-signed for profile %q.\n", name)
+	return 0
+}
+
+// resolveProfile returns the profile a subcommand should act on: the one
+// named with --profile, else the last played profile, else the default.
+// Exits with status 2 if the name is invalid.
+func resolveProfile(store save.ProfileStore, name string) string {
+	if name == "" {
+		name = store.LastUsed()
+	}
+	if name == "" {
+		name = save.DefaultProfileName
+	}
+	if err := save.ValidateProfileName(name); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return name
+}
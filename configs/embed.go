@@ -1,6 +1,9 @@
 // Package configs provides embedded world configuration data for use by world
-// implementations. Keeping the embed here avoids the go:embed restriction on
-// paths containing "..".
+// implementations, the account perk tree for internal/perk, the XP source
+// rates for internal/xp, the level curve and rewards for internal/level and
+// the exchange market parameters for internal/market.
+// Keeping the embed here avoids the go:embed restriction on paths containing
+// "..".
 package configs
 
 import _ "embed"
@@ -12,3 +15,19 @@ var TerraToml []byte
 // AquaToml is the embedded configs/worlds/aqua.toml configuration.
 //go:embed worlds/aqua.toml
 var AquaToml []byte
+
+// PerksToml is the embedded configs/perks.toml account perk tree.
+//go:embed perks.toml
+var PerksToml []byte
+
+// XPToml is the embedded configs/xp.toml XP source rates.
+//go:embed xp.toml
+var XPToml []byte
+
+// LevelsToml is the embedded configs/levels.toml level curve and rewards.
+//go:embed levels.toml
+var LevelsToml []byte
+
+// MarketToml is the embedded configs/market.toml exchange market parameters.
+//go:embed market.toml
+var MarketToml []byte
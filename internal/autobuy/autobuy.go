@@ -0,0 +1,189 @@
+// Package autobuy decides what the auto-buyer purchases. It only picks: the
+// engine applies the purchases and owns the settings (see
+// engine.Engine.UnlockAutoBuyer). It has no Bubble Tea imports.
+package autobuy
+
+import (
+	"math"
+	"slices"
+
+	"github.com/clicker-org/clicker/internal/upgrade"
+	"github.com/clicker-org/clicker/internal/world"
+)
+
+// Policy chooses which affordable item the auto-buyer buys next.
+type Policy string
+
+const (
+	// PolicyCheapest buys the cheapest item that adds CPS.
+	PolicyCheapest Policy = "cheapest"
+	// PolicyPayback buys the item that pays for itself soonest: the lowest
+	// cost per CPS gained.
+	PolicyPayback Policy = "payback"
+	// PolicyPriority buys the items of the world's priority list in rounds,
+	// one of each per round, first listed first, and waits for the next one
+	// when it cannot afford it yet.
+	PolicyPriority Policy = "priority"
+)
+
+// Policies lists every policy in the order the UI cycles through them.
+var Policies = []Policy{PolicyCheapest, PolicyPayback, PolicyPriority}
+
+// Unlock conditions: the auto-buyer unlocks by itself at UnlockLevel, or
+// earlier for UnlockCost general coins.
+const (
+	UnlockLevel = 8
+	UnlockCost  = 100.0
+)
+
+// DefaultReserve is the share of a world's balance the auto-buyer keeps
+// unless told otherwise.
+const DefaultReserve = 0.2
+
+// MaxReserve bounds Settings.Reserve; a reserve of 100% would never buy.
+const MaxReserve = 0.9
+
+// WorldSettings configure the auto-buyer in one world.
+type WorldSettings struct {
+	Enabled bool   `json:"enabled"`
+	Policy  Policy `json:"policy,omitempty"`
+	// Priority lists buy-on and upgrade IDs, most wanted first. Only
+	// PolicyPriority uses it.
+	Priority []string `json:"priority,omitempty"`
+	// Next is the position in Priority the current round continues from.
+	Next int `json:"next,omitempty"`
+}
+
+// Bought records a purchase of id, moving a priority round on past it.
+func (ws *WorldSettings) Bought(id string) {
+	if i := slices.Index(ws.Priority, id); i >= 0 {
+		ws.Next = (i + 1) % len(ws.Priority)
+	}
+}
+
+// Settings are the auto-buyer's persisted settings.
+type Settings struct {
+	// Unlocked is set once the auto-buyer is bought with general coins.
+	// Reaching UnlockLevel unlocks it without setting it.
+	Unlocked bool `json:"unlocked"`
+	// Reserve is the share of a world's balance, 0 to MaxReserve, that the
+	// auto-buyer never spends.
+	Reserve float64 `json:"reserve"`
+	// Worlds holds per-world settings by world ID; a world without an entry
+	// is switched off.
+	Worlds map[string]WorldSettings `json:"worlds,omitempty"`
+}
+
+// DefaultSettings returns the settings of a new game.
+func DefaultSettings() Settings {
+	return Settings{Reserve: DefaultReserve}
+}
+
+// Clone returns a deep copy of s.
+func (s Settings) Clone() Settings {
+	out := s
+	out.Worlds = make(map[string]WorldSettings, len(s.Worlds))
+	for id, ws := range s.Worlds {
+		ws.Priority = append([]string(nil), ws.Priority...)
+		out.Worlds[id] = ws
+	}
+	return out
+}
+
+// World returns the settings for worldID, with the policy defaulted.
+func (s Settings) World(worldID string) WorldSettings {
+	ws := s.Worlds[worldID]
+	if ws.Policy == "" {
+		ws.Policy = PolicyPayback
+	}
+	return ws
+}
+
+// Item is something the auto-buyer can buy in a world: one more of a buy-on,
+// or an upgrade.
+type Item struct {
+	ID      string
+	Name    string
+	Upgrade bool
+	Cost    float64
+	// Gain is the CPS the purchase adds.
+	Gain float64
+}
+
+// Candidates returns the items the player could buy next in ws at level,
+// affordable or not: every buy-on and every upgrade not yet owned whose
+// level requirement is met, buy-ons first, in registry order. Their gains
+// are under the world's CPS multiplier mult, prestige included.
+func Candidates(reg *upgrade.WorldUpgradeRegistry, ws *world.WorldState, level int, mult float64) []Item {
+	upgrades := reg.ListUpgrades()
+	var items []Item
+	for _, b := range reg.ListBuyOns() {
+		if b.LevelRequirement() > level {
+			continue
+		}
+		items = append(items, Item{
+			ID:   b.ID(),
+			Name: b.Name(),
+			Cost: upgrade.CostForNext(b, ws.BuyOnCounts[b.ID()]),
+			Gain: upgrade.MarginalCPS(b, upgrades, ws.PurchasedUpgrades, mult),
+		})
+	}
+	for _, u := range upgrades {
+		if ws.PurchasedUpgrades[u.ID] || u.LevelRequirement > level {
+			continue
+		}
+		gain := 0.0
+		if b, ok := reg.GetBuyOn(u.TargetBuyOnID); ok {
+			current := upgrade.MarginalCPS(b, upgrades, ws.PurchasedUpgrades, mult) * float64(ws.BuyOnCounts[b.ID()])
+			gain = current * (u.Multiplier - 1)
+		}
+		items = append(items, Item{ID: u.ID, Name: u.Name, Upgrade: true, Cost: u.Cost, Gain: gain})
+	}
+	return items
+}
+
+// Pick returns the item to buy next out of candidates with budget to spend,
+// following settings. It returns false if the policy buys nothing now.
+func Pick(settings WorldSettings, candidates []Item, budget float64) (Item, bool) {
+	if settings.Policy == PolicyPriority {
+		// start where the round left off, so that a buy-on, which can
+		// always be bought again, does not hold up the entries after it.
+		n := len(settings.Priority)
+		for k := range n {
+			id := settings.Priority[(settings.Next+k)%n]
+			for _, it := range candidates {
+				if it.ID == id {
+					// the list is an order: wait for this one.
+					return it, it.Cost <= budget
+				}
+			}
+		}
+		return Item{}, false
+	}
+
+	best, found := Item{}, false
+	bestScore := math.Inf(1)
+	for _, it := range candidates {
+		if it.Gain <= 0 || it.Cost > budget {
+			continue
+		}
+		score := it.Cost
+		if settings.Policy == PolicyPayback {
+			score = it.Cost / it.Gain
+		}
+		if score < bestScore {
+			best, bestScore, found = it, score, true
+		}
+	}
+	return best, found
+}
+
+// Purchase is one line of an auto-buyer report: Count purchases of the item
+// ID in one run, for Cost coins in total.
+type Purchase struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Upgrade bool    `json:"upgrade,omitempty"`
+	Count   int     `json:"count"`
+	Cost    float64 `json:"cost"`
+}
@@ -0,0 +1,112 @@
+package autobuy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/upgrade"
+	"github.com/clicker-org/clicker/internal/world"
+)
+
+func testRegistry() *upgrade.WorldUpgradeRegistry {
+	reg := upgrade.NewWorldUpgradeRegistry()
+	reg.RegisterBuyOn(upgrade.NewConfigBuyOn(config.BuyOnConfig{ID: "miner", Name: "Miner", BaseCost: 10, CostScaling: 2, BaseCPS: 1}))
+	reg.RegisterBuyOn(upgrade.NewConfigBuyOn(config.BuyOnConfig{ID: "drill", Name: "Drill", BaseCost: 50, CostScaling: 2, BaseCPS: 10}))
+	reg.RegisterBuyOn(upgrade.NewConfigBuyOn(config.BuyOnConfig{ID: "laser", Name: "Laser", BaseCost: 100, CostScaling: 2, BaseCPS: 50, LevelRequirement: 5}))
+	reg.RegisterUpgrade(config.UpgradeConfig{ID: "turbo", Name: "Turbo", TargetBuyOnID: "miner", Multiplier: 3, Cost: 30})
+	reg.RegisterUpgrade(config.UpgradeConfig{ID: "sharp", Name: "Sharp", TargetBuyOnID: "drill", Multiplier: 2, Cost: 40})
+	return reg
+}
+
+func TestCandidates(t *testing.T) {
+	ws := world.NewWorldState("test", 0)
+	ws.BuyOnCounts["miner"] = 2
+	ws.PurchasedUpgrades["sharp"] = true
+
+	items := Candidates(testRegistry(), ws, 1, 1)
+
+	require.Len(t, items, 3, "laser is level-gated and sharp is owned")
+	assert.Equal(t, Item{ID: "miner", Name: "Miner", Cost: 40, Gain: 1}, items[0])
+	assert.Equal(t, Item{ID: "drill", Name: "Drill", Cost: 50, Gain: 20}, items[1], "owned upgrades raise the gain")
+	assert.Equal(t, Item{ID: "turbo", Name: "Turbo", Upgrade: true, Cost: 30, Gain: 4}, items[2], "two miners at 1 CPS, tripled")
+
+	items = Candidates(testRegistry(), ws, 1, 1.5)
+	assert.Equal(t, 30.0, items[1].Gain, "the CPS multiplier scales the gain")
+	assert.Equal(t, 6.0, items[2].Gain)
+}
+
+func TestPick_Policies(t *testing.T) {
+	items := []Item{
+		{ID: "miner", Cost: 40, Gain: 1},
+		{ID: "drill", Cost: 50, Gain: 20},
+		{ID: "turbo", Upgrade: true, Cost: 30, Gain: 4},
+		{ID: "idle", Upgrade: true, Cost: 5},
+	}
+
+	got, ok := Pick(WorldSettings{Policy: PolicyCheapest}, items, 100)
+	require.True(t, ok)
+	assert.Equal(t, "turbo", got.ID, "upgrades that add nothing are skipped")
+
+	got, ok = Pick(WorldSettings{Policy: PolicyPayback}, items, 100)
+	require.True(t, ok)
+	assert.Equal(t, "drill", got.ID)
+
+	got, ok = Pick(WorldSettings{Policy: PolicyPayback}, items, 45)
+	require.True(t, ok)
+	assert.Equal(t, "turbo", got.ID, "the best payback within budget")
+
+	_, ok = Pick(WorldSettings{Policy: PolicyCheapest}, items, 20)
+	assert.False(t, ok)
+}
+
+func TestPick_PriorityWaitsInOrder(t *testing.T) {
+	items := []Item{
+		{ID: "miner", Cost: 40, Gain: 1},
+		{ID: "drill", Cost: 50, Gain: 20},
+	}
+	settings := WorldSettings{Policy: PolicyPriority, Priority: []string{"owned_upgrade", "drill", "miner"}}
+
+	got, ok := Pick(settings, items, 60)
+	require.True(t, ok)
+	assert.Equal(t, "drill", got.ID, "entries that cannot be bought are passed over")
+
+	_, ok = Pick(settings, items, 45)
+	assert.False(t, ok, "saves up for drill instead of buying miner")
+
+	_, ok = Pick(WorldSettings{Policy: PolicyPriority}, items, 1000)
+	assert.False(t, ok, "an empty list buys nothing")
+}
+
+func TestPick_PriorityBuysInRounds(t *testing.T) {
+	items := []Item{
+		{ID: "miner", Cost: 40, Gain: 1},
+		{ID: "drill", Cost: 50, Gain: 20},
+	}
+	settings := WorldSettings{Policy: PolicyPriority, Priority: []string{"drill", "miner"}}
+
+	var bought []string
+	for range 4 {
+		got, ok := Pick(settings, items, 1000)
+		require.True(t, ok)
+		bought = append(bought, got.ID)
+		settings.Bought(got.ID)
+	}
+	assert.Equal(t, []string{"drill", "miner", "drill", "miner"}, bought, "a buy-on does not hold up the next one")
+
+	got, ok := Pick(settings, items, 45)
+	assert.False(t, ok, "saves up for drill's turn instead of buying miner")
+	assert.Equal(t, "drill", got.ID)
+}
+
+func TestSettings_CloneAndWorldDefaults(t *testing.T) {
+	s := Settings{Worlds: map[string]WorldSettings{"terra": {Enabled: true, Priority: []string{"a"}}}}
+	c := s.Clone()
+	c.Worlds["terra"].Priority[0] = "b"
+	assert.Equal(t, "a", s.Worlds["terra"].Priority[0])
+
+	assert.Equal(t, PolicyPayback, s.World("terra").Policy)
+	assert.False(t, s.World("aqua").Enabled)
+}
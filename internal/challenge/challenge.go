@@ -0,0 +1,199 @@
+// Package challenge generates short-term goals: a set of daily challenges
+// that rotates every calendar day and one challenge per play session. They
+// are generated deterministically from templates, so the same profile sees
+// the same challenges on the same day. The engine tracks their progress and
+// grants their rewards (see engine.Engine.StartChallenges). It has no Bubble
+// Tea imports.
+package challenge
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/clicker-org/clicker/internal/economy"
+)
+
+// Kind is what a challenge counts.
+type Kind string
+
+const (
+	// KindClicks counts manual clicks in any world.
+	KindClicks Kind = "clicks"
+	// KindEarn counts coins earned in one world while playing.
+	KindEarn Kind = "earn"
+	// KindBuy counts buy-ons bought in any world, by hand or by the
+	// auto-buyer.
+	KindBuy Kind = "buy"
+	// KindPrestige counts prestiges of any world.
+	KindPrestige Kind = "prestige"
+)
+
+// DailyCount is how many daily challenges each day has.
+const DailyCount = 3
+
+// RerollsPerDay is how many daily challenges the player may swap for
+// another each day.
+const RerollsPerDay = 1
+
+// Template describes a family of challenges. A challenge takes one of the
+// template's targets; the rewards grow with the target's tier.
+type Template struct {
+	ID   string
+	Kind Kind
+	// PerWorld templates pick a world to count in.
+	PerWorld bool
+	// Targets are the possible targets, easiest first.
+	Targets []float64
+	// Text is the description, with %[1]s the target and %[2]s the world
+	// name.
+	Text string
+	// XP and GC are the rewards for the easiest target.
+	XP int
+	GC float64
+}
+
+// Templates lists every challenge template.
+var Templates = []Template{
+	{ID: "click", Kind: KindClicks, Targets: []float64{200, 500, 1000}, Text: "Click %[1]s times", XP: 40},
+	{ID: "earn", Kind: KindEarn, PerWorld: true, Targets: []float64{1e4, 1e5, 1e6}, Text: "Earn %[1]s in %[2]s", XP: 60, GC: 2},
+	{ID: "buy", Kind: KindBuy, Targets: []float64{10, 25, 50}, Text: "Buy %[1]s buy-ons", XP: 50, GC: 1},
+	{ID: "prestige", Kind: KindPrestige, Targets: []float64{1}, Text: "Prestige any world", XP: 150, GC: 10},
+}
+
+// Challenge is one generated challenge and its progress.
+type Challenge struct {
+	// ID is unique among the challenges of a profile.
+	ID       string  `json:"id"`
+	Template string  `json:"template"`
+	Kind     Kind    `json:"kind"`
+	World    string  `json:"world,omitempty"`
+	Target   float64 `json:"target"`
+	Progress float64 `json:"progress"`
+	RewardXP int     `json:"reward_xp"`
+	RewardGC float64 `json:"reward_gc,omitempty"`
+	// Completed is set once Progress reaches Target and the rewards are
+	// granted.
+	Completed bool `json:"completed,omitempty"`
+}
+
+// Describe returns the challenge's description; worldName is the display
+// name of its world, if it has one.
+func (c Challenge) Describe(worldName string) string {
+	text := c.Template
+	for _, t := range Templates {
+		if t.ID == c.Template {
+			text = t.Text
+			break
+		}
+	}
+	return fmt.Sprintf(text, economy.FormatCoinsBare(c.Target), worldName)
+}
+
+// Done reports whether the challenge's target has been reached.
+func (c Challenge) Done() bool { return c.Progress >= c.Target }
+
+// State is the persisted challenge state of a profile.
+type State struct {
+	// Seed identifies the profile in challenge generation.
+	Seed string `json:"seed,omitempty"`
+	// Day is the local date, as "2006-01-02", the daily challenges are for.
+	// It never moves back: it is the latest day challenges were generated for.
+	Day   string      `json:"day,omitempty"`
+	Daily []Challenge `json:"daily,omitempty"`
+	// Rerolls is how many daily challenges were rerolled on Day.
+	Rerolls int `json:"rerolls,omitempty"`
+	// Session is the challenge of the current play session.
+	Session *Challenge `json:"session,omitempty"`
+	// CompletedTotal counts every challenge ever completed.
+	CompletedTotal int `json:"completed_total,omitempty"`
+}
+
+// Clone returns a deep copy of s.
+func (s State) Clone() State {
+	out := s
+	out.Daily = slices.Clone(s.Daily)
+	if s.Session != nil {
+		c := *s.Session
+		out.Session = &c
+	}
+	return out
+}
+
+// Active returns pointers to every challenge in s, daily first, so callers
+// can update their progress.
+func (s *State) Active() []*Challenge {
+	out := make([]*Challenge, 0, len(s.Daily)+1)
+	for i := range s.Daily {
+		out = append(out, &s.Daily[i])
+	}
+	if s.Session != nil {
+		out = append(out, s.Session)
+	}
+	return out
+}
+
+// Daily returns the daily challenges of day for the profile seed, each from
+// a different template. worlds are the world IDs per-world challenges may
+// pick from.
+func Daily(seed, day string, worlds []string) []Challenge {
+	out := make([]Challenge, 0, DailyCount)
+	for slot := range DailyCount {
+		out = append(out, Reroll(seed, day, slot, 0, worlds, out))
+	}
+	return out
+}
+
+// Reroll returns the challenge for slot of day after n rerolls that day,
+// from a template not used by any of taken. Daily uses n = 0.
+func Reroll(seed, day string, slot, n int, worlds []string, taken []Challenge) Challenge {
+	c := generate(fmt.Sprintf("%s|%s|%d|%d", seed, day, slot, n), worlds, taken)
+	c.ID = fmt.Sprintf("%s#%d", day, slot)
+	if n > 0 {
+		c.ID += fmt.Sprintf("r%d", n)
+	}
+	return c
+}
+
+// Session returns the challenge for a session identified by key, e.g. its
+// start time, for the profile seed.
+func Session(seed, key string, worlds []string) Challenge {
+	c := generate(seed+"|session|"+key, worlds, nil)
+	c.ID = "session-" + key
+	return c
+}
+
+// generate picks a template not used by taken, a target and a world from
+// a random source seeded with key.
+func generate(key string, worlds []string, taken []Challenge) Challenge {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	rng := rand.New(rand.NewPCG(h.Sum64(), 0))
+
+	var pool []Template
+	for _, t := range Templates {
+		if t.PerWorld && len(worlds) == 0 {
+			continue
+		}
+		if !slices.ContainsFunc(taken, func(c Challenge) bool { return c.Template == t.ID }) {
+			pool = append(pool, t)
+		}
+	}
+	if len(pool) == 0 {
+		pool = Templates[:1]
+	}
+	t := pool[rng.IntN(len(pool))]
+	tier := rng.IntN(len(t.Targets))
+	c := Challenge{
+		Template: t.ID,
+		Kind:     t.Kind,
+		Target:   t.Targets[tier],
+		RewardXP: t.XP * (tier + 1),
+		RewardGC: t.GC * float64(tier+1),
+	}
+	if t.PerWorld {
+		c.World = worlds[rng.IntN(len(worlds))]
+	}
+	return c
+}
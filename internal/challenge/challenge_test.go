@@ -0,0 +1,56 @@
+package challenge
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var worlds = []string{"terra", "aqua"}
+
+func TestDaily_Deterministic(t *testing.T) {
+	a := Daily("alice", "2026-10-18", worlds)
+	require.Len(t, a, DailyCount)
+	assert.Equal(t, a, Daily("alice", "2026-10-18", worlds))
+
+	templates := map[string]bool{}
+	for i, c := range a {
+		assert.Equal(t, fmt.Sprintf("2026-10-18#%d", i), c.ID)
+		assert.False(t, templates[c.Template], "each template once a day")
+		templates[c.Template] = true
+		assert.Positive(t, c.Target)
+		assert.Positive(t, c.RewardXP)
+		assert.Equal(t, c.Template == "earn", c.World != "")
+	}
+
+	differs := false
+	for day := 19; day < 26 && !differs; day++ {
+		differs = fmt.Sprint(Daily("alice", fmt.Sprintf("2026-10-%d", day), worlds)) != fmt.Sprint(a)
+	}
+	assert.True(t, differs, "challenges rotate from day to day")
+}
+
+func TestReroll_AvoidsTakenTemplates(t *testing.T) {
+	daily := Daily("bob", "2026-10-18", worlds)
+	c := Reroll("bob", "2026-10-18", 1, 1, worlds, daily)
+	assert.Equal(t, "2026-10-18#1r1", c.ID)
+	for _, d := range daily {
+		assert.NotEqual(t, d.Template, c.Template)
+	}
+}
+
+func TestGenerate_NoWorlds(t *testing.T) {
+	for i := range 20 {
+		c := Session("carol", fmt.Sprint(i), nil)
+		assert.NotEqual(t, KindEarn, c.Kind, "per-world challenges need a world")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	c := Challenge{Template: "earn", Target: 1e6}
+	assert.Equal(t, "Earn 1.00M in Aqua", c.Describe("Aqua"))
+	c = Challenge{Template: "click", Target: 500}
+	assert.Equal(t, "Click 500 times", c.Describe(""))
+}
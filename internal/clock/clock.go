@@ -0,0 +1,89 @@
+// Package clock abstracts wall-clock and monotonic time so that
+// time-dependent game logic (offline income, clock-tamper detection) can be
+// tested deterministically. It has no Bubble Tea imports.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current wall-clock time and a monotonic reading.
+//
+// Wall time can jump in either direction when the user changes the system
+// clock; Monotonic never goes backward and is unaffected by such changes, so
+// comparing the two reveals clock jumps while the game is running.
+type Clock interface {
+	// Now returns the current wall-clock time.
+	Now() time.Time
+	// Monotonic returns the time elapsed since an arbitrary fixed origin.
+	Monotonic() time.Duration
+}
+
+// Record is the persisted clock bookkeeping used to detect tampering across
+// sessions. It is stored in the save file.
+type Record struct {
+	// LastWall is the latest trustworthy wall-clock time ever observed. It is
+	// a high-water mark: it never moves backward, and forward jumps detected
+	// while the game is running do not raise it.
+	LastWall time.Time `json:"last_wall"`
+	// PlaySeconds is total play time measured with the monotonic clock. Unlike
+	// wall time it cannot be inflated by changing the system clock.
+	PlaySeconds float64 `json:"play_seconds"`
+}
+
+type systemClock struct {
+	origin time.Time
+}
+
+// System returns a Clock backed by the operating system clock.
+func System() Clock {
+	return systemClock{origin: time.Now()}
+}
+
+// Now strips the monotonic reading so that comparisons use wall time only.
+func (c systemClock) Now() time.Time           { return time.Now().Round(0) }
+func (c systemClock) Monotonic() time.Duration { return time.Since(c.origin) }
+
+// Fake is a manually driven Clock for tests. Its wall time and monotonic time
+// advance together via Advance, and the wall time can be moved independently
+// via SetWall to simulate the user changing the system clock.
+type Fake struct {
+	mu   sync.Mutex
+	wall time.Time
+	mono time.Duration
+}
+
+// NewFake returns a Fake whose wall clock starts at wall.
+func NewFake(wall time.Time) *Fake {
+	return &Fake{wall: wall}
+}
+
+// Now returns the fake wall-clock time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.wall
+}
+
+// Monotonic returns the fake monotonic reading.
+func (f *Fake) Monotonic() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mono
+}
+
+// Advance moves both wall and monotonic time forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wall = f.wall.Add(d)
+	f.mono += d
+}
+
+// SetWall changes the wall-clock time without affecting monotonic time.
+func (f *Fake) SetWall(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.wall = t
+}
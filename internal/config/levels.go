@@ -0,0 +1,66 @@
+package config
+
+import "fmt"
+
+// LevelCurveConfig is the XP the player needs for each level. The step from
+// level n to n+1 costs Steps[n-1] where the table lists it, and
+// Base * Growth^(n-1) otherwise, so a table can override the formula for the
+// first levels.
+type LevelCurveConfig struct {
+	// Base is the XP from level 1 to level 2.
+	Base float64 `toml:"base"`
+	// Growth is how many times the step before each later step costs.
+	Growth float64 `toml:"growth"`
+	Steps  []int   `toml:"steps"`
+}
+
+// LevelRewardConfig is what the player is given on reaching Level.
+type LevelRewardConfig struct {
+	Level        int     `toml:"level"`
+	GeneralCoins float64 `toml:"gc"`
+	// Cosmetic is the name of a cosmetic unlocked at Level, if any.
+	Cosmetic string `toml:"cosmetic"`
+	// PerkPoints are perk points on top of the one every level earns.
+	PerkPoints int `toml:"perk_points"`
+}
+
+// LevelsConfig is the level curve and the level rewards table.
+type LevelsConfig struct {
+	Curve LevelCurveConfig `toml:"curve"`
+	// Rewards are in ascending level order.
+	Rewards []LevelRewardConfig `toml:"rewards"`
+}
+
+// ValidateLevels checks a LevelsConfig for consistency errors and returns a
+// list of human-readable error strings. An empty slice means the config is
+// valid.
+func ValidateLevels(cfg LevelsConfig) []string {
+	var errs []string
+	c := cfg.Curve
+	if c.Base < 1 {
+		errs = append(errs, fmt.Sprintf("curve base %.2f must be >= 1", c.Base))
+	}
+	if c.Growth < 1 {
+		errs = append(errs, fmt.Sprintf("curve growth %.2f must be >= 1", c.Growth))
+	}
+	for i, s := range c.Steps {
+		if s < 1 {
+			errs = append(errs, fmt.Sprintf("curve step %d (level %d → %d) costs %d, must be >= 1", i+1, i+1, i+2, s))
+		}
+	}
+
+	prev := 1
+	for _, r := range cfg.Rewards {
+		if r.Level <= prev {
+			errs = append(errs, fmt.Sprintf("reward for level %d must be above level %d", r.Level, prev))
+		}
+		if r.GeneralCoins < 0 || r.PerkPoints < 0 {
+			errs = append(errs, fmt.Sprintf("reward for level %d must not be negative", r.Level))
+		}
+		if r.GeneralCoins == 0 && r.PerkPoints == 0 && r.Cosmetic == "" {
+			errs = append(errs, fmt.Sprintf("reward for level %d grants nothing", r.Level))
+		}
+		prev = max(prev, r.Level)
+	}
+	return errs
+}
@@ -35,12 +35,93 @@ type UpgradeConfig struct {
 	LevelRequirement int     `toml:"level_requirement"`
 }
 
-// PrestigeThresholdConfig defines when a world prestige becomes available.
-type PrestigeThresholdConfig struct {
+// Metrics a prestige condition can measure.
+const (
+	// PrestigeMetricCoinsEarned is the coins earned in the world, ever.
+	PrestigeMetricCoinsEarned = "coins_earned"
+	// PrestigeMetricBuyOnsOwned is the buy-ons owned in the world.
+	PrestigeMetricBuyOnsOwned = "buy_ons_owned"
+	// PrestigeMetricCompletionPercent is the world's completion, 0 to 100.
+	PrestigeMetricCompletionPercent = "completion_percent"
+)
+
+// PrestigeConditionConfig is met once the metric Type (a PrestigeMetric*
+// constant) reaches Value.
+type PrestigeConditionConfig struct {
 	Type  string  `toml:"type"`
 	Value float64 `toml:"value"`
 }
 
+// PrestigeThresholdConfig defines when a world prestige becomes available:
+// either a single condition, given by Type and Value, or a list of
+// conditions of which AllOf must all be met, or one of AnyOf.
+type PrestigeThresholdConfig struct {
+	Type  string                    `toml:"type"`
+	Value float64                   `toml:"value"`
+	AllOf []PrestigeConditionConfig `toml:"all_of"`
+	AnyOf []PrestigeConditionConfig `toml:"any_of"`
+}
+
+// Conditions returns the conditions of the threshold, and whether meeting
+// any one of them is enough.
+func (t PrestigeThresholdConfig) Conditions() (conds []PrestigeConditionConfig, anyOf bool) {
+	switch {
+	case len(t.AllOf) > 0:
+		return t.AllOf, false
+	case len(t.AnyOf) > 0:
+		return t.AnyOf, true
+	case t.Type != "":
+		return []PrestigeConditionConfig{{Type: t.Type, Value: t.Value}}, false
+	}
+	return nil, false
+}
+
+// PrestigeRewardConfig holds the parameters of a world's prestige reward
+// formula (see economy.PrestigeFormula). A world that leaves the section out
+// gets economy.DefaultPrestigeFormula.
+type PrestigeRewardConfig struct {
+	// The general coins are GCScale × coins earned^GCExponent.
+	GCScale    float64 `toml:"gc_scale"`
+	GCExponent float64 `toml:"gc_exponent"`
+	// The n-th prestige, counting from 0, multiplies the world's prestige
+	// multiplier by 1 + MultiplierGain / (n+1)^MultiplierDecay.
+	MultiplierGain  float64 `toml:"multiplier_gain"`
+	MultiplierDecay float64 `toml:"multiplier_decay"`
+	// The n-th prestige, counting from 0, grants XPPerPrestige × (n+1) XP.
+	XPPerPrestige int `toml:"xp_per_prestige"`
+}
+
+// Effects of prestige tree nodes.
+const (
+	// PrestigeEffectStartBuyOns starts each reset of the world with Value
+	// units of the buy-on Target.
+	PrestigeEffectStartBuyOns = "start_buy_ons"
+	// PrestigeEffectClickBonus adds Value (e.g. 0.25 for +25%) to the
+	// world's click multiplier.
+	PrestigeEffectClickBonus = "click_bonus"
+	// PrestigeEffectCPSBonus adds Value to the world's CPS multiplier.
+	PrestigeEffectCPSBonus = "cps_bonus"
+	// PrestigeEffectCostScaling removes the fraction Value of every buy-on's
+	// cost growth per unit owned.
+	PrestigeEffectCostScaling = "cost_scaling"
+)
+
+// PrestigeNodeConfig is a node of a world's prestige upgrade tree, bought
+// with the world's prestige points.
+type PrestigeNodeConfig struct {
+	ID          string `toml:"id"`
+	Name        string `toml:"name"`
+	Description string `toml:"description"`
+	Cost        int    `toml:"cost"`
+	// Requires lists the nodes that must be owned first. They must be
+	// declared before this node.
+	Requires []string `toml:"requires"`
+	Effect   string   `toml:"effect"`
+	// Target is the buy-on ID for PrestigeEffectStartBuyOns.
+	Target string  `toml:"target"`
+	Value  float64 `toml:"value"`
+}
+
 // CompletionMilestone is a single milestone that contributes to world completion %.
 type CompletionMilestone struct {
 	ID          string  `toml:"id"`
@@ -64,7 +145,9 @@ type WorldConfig struct {
 	BuyOns               []BuyOnConfig           `toml:"buy_ons"`
 	BuyOnUpgrades        []UpgradeConfig         `toml:"buy_on_upgrades"`
 	PrestigeThreshold    PrestigeThresholdConfig `toml:"prestige_threshold"`
+	PrestigeReward       *PrestigeRewardConfig   `toml:"prestige_reward"`
 	CompletionMilestones []CompletionMilestone   `toml:"completion_milestones"`
+	PrestigeTree         []PrestigeNodeConfig    `toml:"prestige_tree"`
 }
 
 // LoadWorld loads a single WorldConfig from the given TOML file path.
@@ -125,6 +208,75 @@ func Validate(cfg WorldConfig) []string {
 		}
 	}
 
+	nodeIDs := map[string]bool{}
+	for _, n := range cfg.PrestigeTree {
+		if !validIDRe.MatchString(n.ID) || nodeIDs[n.ID] {
+			errs = append(errs, fmt.Sprintf("prestige_tree node ID %q must match [a-z_]+ and be unique", n.ID))
+		}
+		if n.Cost < 1 {
+			errs = append(errs, fmt.Sprintf("prestige_tree node %q cost %d must be >= 1", n.ID, n.Cost))
+		}
+		for _, r := range n.Requires {
+			if !nodeIDs[r] {
+				errs = append(errs, fmt.Sprintf("prestige_tree node %q requires %q, which is not declared before it", n.ID, r))
+			}
+		}
+		switch n.Effect {
+		case PrestigeEffectStartBuyOns:
+			if !buyOnIDs[n.Target] {
+				errs = append(errs, fmt.Sprintf("prestige_tree node %q references unknown buy_on %q", n.ID, n.Target))
+			}
+			if n.Value < 1 || n.Value != math.Trunc(n.Value) {
+				errs = append(errs, fmt.Sprintf("prestige_tree node %q value %.2f must be a whole number >= 1", n.ID, n.Value))
+			}
+		case PrestigeEffectClickBonus, PrestigeEffectCPSBonus:
+			if n.Value <= 0 {
+				errs = append(errs, fmt.Sprintf("prestige_tree node %q value %.2f must be > 0", n.ID, n.Value))
+			}
+		case PrestigeEffectCostScaling:
+			if n.Value <= 0 || n.Value >= 1 {
+				errs = append(errs, fmt.Sprintf("prestige_tree node %q value %.2f must be in (0, 1)", n.ID, n.Value))
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("prestige_tree node %q has unknown effect %q", n.ID, n.Effect))
+		}
+		nodeIDs[n.ID] = true
+	}
+
+	t := cfg.PrestigeThreshold
+	forms := 0
+	for _, set := range []bool{t.Type != "" || t.Value != 0, len(t.AllOf) > 0, len(t.AnyOf) > 0} {
+		if set {
+			forms++
+		}
+	}
+	if forms > 1 {
+		errs = append(errs, "prestige_threshold must be one of type/value, all_of or any_of")
+	}
+	conds, _ := t.Conditions()
+	for _, c := range conds {
+		switch c.Type {
+		case PrestigeMetricCoinsEarned, PrestigeMetricBuyOnsOwned, PrestigeMetricCompletionPercent:
+		default:
+			errs = append(errs, fmt.Sprintf("prestige_threshold has unknown metric %q", c.Type))
+		}
+		if c.Value <= 0 {
+			errs = append(errs, fmt.Sprintf("prestige_threshold %s value %.2f must be > 0", c.Type, c.Value))
+		}
+	}
+
+	if r := cfg.PrestigeReward; r != nil {
+		if r.GCScale <= 0 || r.GCExponent <= 0 {
+			errs = append(errs, fmt.Sprintf("prestige_reward gc_scale %.2f and gc_exponent %.2f must be > 0", r.GCScale, r.GCExponent))
+		}
+		if r.MultiplierGain < 0 || r.MultiplierDecay < 0 {
+			errs = append(errs, fmt.Sprintf("prestige_reward multiplier_gain %.2f and multiplier_decay %.2f must be >= 0", r.MultiplierGain, r.MultiplierDecay))
+		}
+		if r.XPPerPrestige < 0 {
+			errs = append(errs, fmt.Sprintf("prestige_reward xp_per_prestige %d must be >= 0", r.XPPerPrestige))
+		}
+	}
+
 	if len(cfg.CompletionMilestones) > 0 {
 		total := 0.0
 		for _, m := range cfg.CompletionMilestones {
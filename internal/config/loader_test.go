@@ -78,6 +78,30 @@ func TestValidate_CostScalingBelowOne(t *testing.T) {
 	assert.NotEmpty(t, errs)
 }
 
+func TestValidate_PrestigeTree(t *testing.T) {
+	cfg := WorldConfig{
+		ID: "test_world",
+		BuyOns: []BuyOnConfig{
+			{ID: "miner", CostScaling: 1.15, BaseCPS: 0.1},
+		},
+		PrestigeTree: []PrestigeNodeConfig{
+			{ID: "head_start", Cost: 1, Effect: PrestigeEffectStartBuyOns, Target: "miner", Value: 5},
+			{ID: "grip", Cost: 2, Requires: []string{"head_start"}, Effect: PrestigeEffectClickBonus, Value: 0.25},
+		},
+	}
+	assert.Empty(t, Validate(cfg))
+
+	cfg.PrestigeTree = append(cfg.PrestigeTree,
+		PrestigeNodeConfig{ID: "early", Cost: 1, Requires: []string{"late"}, Effect: PrestigeEffectCPSBonus, Value: 0.1},
+		PrestigeNodeConfig{ID: "late", Cost: 1, Effect: PrestigeEffectCostScaling, Value: 1.5},
+		PrestigeNodeConfig{ID: "ghost", Cost: 1, Effect: PrestigeEffectStartBuyOns, Target: "nonexistent", Value: 1},
+		PrestigeNodeConfig{ID: "odd", Cost: 0, Effect: "teleport"},
+	)
+	// a forward reference, an out-of-range cut, an unknown buy-on, a zero
+	// cost and an unknown effect.
+	assert.Len(t, Validate(cfg), 5)
+}
+
 // findTerraToml walks up from the test directory to find configs/worlds/terra.toml.
 func findTerraToml(t *testing.T) string {
 	t.Helper()
@@ -95,3 +119,93 @@ func findTerraToml(t *testing.T) string {
 		dir = parent
 	}
 }
+
+func TestValidatePerks(t *testing.T) {
+	cfg := PerksConfig{Perks: []PerkConfig{
+		{ID: "grip", Cost: 1, MaxRank: 3, Effect: PerkEffectClickBonus, Value: 0.1},
+		{ID: "trade", Cost: 2, MaxRank: 1, Requires: []string{"grip"}, Effect: PerkEffectExchangeBonus, Value: 0.05},
+	}}
+	assert.Empty(t, ValidatePerks(cfg))
+
+	cfg.Perks = append(cfg.Perks,
+		PerkConfig{ID: "early", Cost: 1, MaxRank: 1, Requires: []string{"late"}, Effect: PerkEffectXPBonus, Value: 0.1},
+		PerkConfig{ID: "late", Cost: 1, MaxRank: 0, Effect: PerkEffectCPSBonus, Value: -1},
+		PerkConfig{ID: "grip", Cost: 0, MaxRank: 1, Effect: "teleport"},
+	)
+	// a forward reference, a zero max rank, a negative value, a duplicate ID,
+	// a zero cost and an unknown effect.
+	assert.Len(t, ValidatePerks(cfg), 6)
+}
+
+func TestValidateXP(t *testing.T) {
+	cfg := XPConfig{Sources: map[string]XPSourceConfig{
+		XPSourceBuyOns:     {Rate: 2, Decay: 0.01},
+		XPSourceMilestones: {Rate: 20},
+	}}
+	assert.Empty(t, ValidateXP(cfg))
+
+	cfg.Sources[XPSourceCoinsEarned] = XPSourceConfig{Rate: -1, Decay: -1}
+	cfg.Sources["dreaming"] = XPSourceConfig{Rate: 1}
+	// a negative rate, a negative decay and an unknown source.
+	assert.Len(t, ValidateXP(cfg), 3)
+}
+
+func TestValidateLevels(t *testing.T) {
+	cfg := LevelsConfig{
+		Curve: LevelCurveConfig{Base: 100, Growth: 1.5, Steps: []int{50}},
+		Rewards: []LevelRewardConfig{
+			{Level: 2, GeneralCoins: 10},
+			{Level: 5, Cosmetic: "Badge"},
+		},
+	}
+	assert.Empty(t, ValidateLevels(cfg))
+
+	cfg.Curve = LevelCurveConfig{Base: 0, Growth: 0.5, Steps: []int{0}}
+	cfg.Rewards = append(cfg.Rewards,
+		LevelRewardConfig{Level: 4, GeneralCoins: -1},
+		LevelRewardConfig{Level: 6},
+	)
+	// a zero base, a shrinking growth, a free step, a reward out of order, a
+	// negative reward and an empty one.
+	assert.Len(t, ValidateLevels(cfg), 6)
+}
+
+func TestValidate_PrestigeThresholdAndReward(t *testing.T) {
+	cfg := WorldConfig{
+		ID:     "test_world",
+		BuyOns: []BuyOnConfig{{ID: "miner", CostScaling: 1.15, BaseCPS: 0.1}},
+		PrestigeThreshold: PrestigeThresholdConfig{AnyOf: []PrestigeConditionConfig{
+			{Type: PrestigeMetricCoinsEarned, Value: 1e6},
+			{Type: PrestigeMetricCompletionPercent, Value: 50},
+		}},
+		PrestigeReward: &PrestigeRewardConfig{GCScale: 0.1, GCExponent: 0.5, MultiplierGain: 0.5, XPPerPrestige: 100},
+	}
+	assert.Empty(t, Validate(cfg))
+	conds, anyOf := cfg.PrestigeThreshold.Conditions()
+	assert.Len(t, conds, 2)
+	assert.True(t, anyOf)
+
+	cfg.PrestigeThreshold.Type = PrestigeMetricBuyOnsOwned
+	cfg.PrestigeThreshold.AnyOf = append(cfg.PrestigeThreshold.AnyOf, PrestigeConditionConfig{Type: "luck", Value: 0})
+	cfg.PrestigeReward = &PrestigeRewardConfig{GCExponent: 0.5, MultiplierDecay: -1, XPPerPrestige: -5}
+	// two forms at once, an unknown metric, a zero value, a zero gc_scale, a
+	// negative decay and negative XP.
+	assert.Len(t, Validate(cfg), 6)
+}
+
+func TestValidateMarket(t *testing.T) {
+	cfg := MarketConfig{
+		StepSeconds: 10, Volatility: 0.04, Reversion: 0.05, Spread: 0.06, History: 100,
+		ShockChance: 0.01, Shocks: []MarketShockConfig{{Name: "Crash", Impact: 0.5}},
+	}
+	assert.Empty(t, ValidateMarket(cfg))
+
+	cfg = MarketConfig{StepSeconds: 0, Volatility: -1, Reversion: 2, Spread: 2, History: 1, ShockChance: 0.5}
+	// no step time, a negative volatility, a reversion above 1, a spread of
+	// 2, too short a history and a shock chance without shocks.
+	assert.Len(t, ValidateMarket(cfg), 6)
+
+	cfg = MarketConfig{StepSeconds: 1, History: 2, Shocks: []MarketShockConfig{{Impact: 1}}}
+	// a shock without a name and one that moves nothing.
+	assert.Len(t, ValidateMarket(cfg), 2)
+}
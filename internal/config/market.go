@@ -0,0 +1,72 @@
+package config
+
+import "fmt"
+
+// MarketShockConfig is a market event that suddenly moves one world coin's
+// price.
+type MarketShockConfig struct {
+	Name string `toml:"name"`
+	// Impact multiplies the price when the event hits, e.g. 1.5 for +50%.
+	Impact float64 `toml:"impact"`
+}
+
+// MarketConfig drives the exchange market, where each world coin's price in
+// general coins moves over time. Every step, a price's gap to the world's
+// exchange rate (its fair price) shrinks by Reversion and moves at random
+// by Volatility, both on a log scale, and may be hit by one of Shocks.
+type MarketConfig struct {
+	// StepSeconds is the play time between price moves.
+	StepSeconds float64 `toml:"step_seconds"`
+	// Volatility is the standard deviation of a step's random move of the
+	// log price.
+	Volatility float64 `toml:"volatility"`
+	// Reversion is the share of the log gap to the fair price closed each
+	// step.
+	Reversion float64 `toml:"reversion"`
+	// Spread is the gap between the buy and sell prices, as a share of the
+	// price between them.
+	Spread float64 `toml:"spread"`
+	// History is how many past prices of each world coin are kept.
+	History int `toml:"history"`
+	// ShockChance is the chance each step that a world coin is hit by one of
+	// Shocks, picked at random.
+	ShockChance float64             `toml:"shock_chance"`
+	Shocks      []MarketShockConfig `toml:"shocks"`
+}
+
+// ValidateMarket checks a MarketConfig for consistency errors and returns a
+// list of human-readable error strings. An empty slice means the config is
+// valid.
+func ValidateMarket(cfg MarketConfig) []string {
+	var errs []string
+	if cfg.StepSeconds <= 0 {
+		errs = append(errs, fmt.Sprintf("step_seconds %.2f must be > 0", cfg.StepSeconds))
+	}
+	if cfg.Volatility < 0 {
+		errs = append(errs, fmt.Sprintf("volatility %.2f must be >= 0", cfg.Volatility))
+	}
+	if cfg.Reversion < 0 || cfg.Reversion > 1 {
+		errs = append(errs, fmt.Sprintf("reversion %.2f must be between 0 and 1", cfg.Reversion))
+	}
+	if cfg.Spread < 0 || cfg.Spread >= 2 {
+		errs = append(errs, fmt.Sprintf("spread %.2f must be >= 0 and < 2", cfg.Spread))
+	}
+	if cfg.History < 2 {
+		errs = append(errs, fmt.Sprintf("history %d must be >= 2", cfg.History))
+	}
+	if cfg.ShockChance < 0 || cfg.ShockChance > 1 {
+		errs = append(errs, fmt.Sprintf("shock_chance %.2f must be between 0 and 1", cfg.ShockChance))
+	}
+	if cfg.ShockChance > 0 && len(cfg.Shocks) == 0 {
+		errs = append(errs, "shock_chance is set but no shocks are defined")
+	}
+	for _, s := range cfg.Shocks {
+		if s.Name == "" {
+			errs = append(errs, "shock has no name")
+		}
+		if s.Impact <= 0 || s.Impact == 1 {
+			errs = append(errs, fmt.Sprintf("shock %q impact %.2f must be > 0 and not 1", s.Name, s.Impact))
+		}
+	}
+	return errs
+}
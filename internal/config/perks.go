@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Effects of account perks. Value is the effect of one rank.
+const (
+	// PerkEffectOfflineCap adds Value hours to every world's offline cap.
+	PerkEffectOfflineCap = "offline_cap_hours"
+	// PerkEffectCPSBonus adds Value (e.g. 0.05 for +5%) to every world's CPS
+	// multiplier.
+	PerkEffectCPSBonus = "cps_bonus"
+	// PerkEffectClickBonus adds Value to every world's click multiplier.
+	PerkEffectClickBonus = "click_bonus"
+	// PerkEffectExchangeBonus adds Value to the general coins earned by
+	// exchange boosts.
+	PerkEffectExchangeBonus = "exchange_bonus"
+	// PerkEffectXPBonus adds Value to the XP earned from every source.
+	PerkEffectXPBonus = "xp_bonus"
+)
+
+// PerkConfig is a node of the account perk tree, bought with the perk
+// points the player earns by leveling up.
+type PerkConfig struct {
+	ID          string `toml:"id"`
+	Name        string `toml:"name"`
+	Description string `toml:"description"`
+	// Cost is the points each rank costs.
+	Cost    int `toml:"cost"`
+	MaxRank int `toml:"max_rank"`
+	// Requires lists the perks that must have a rank first. They must be
+	// declared before this perk.
+	Requires []string `toml:"requires"`
+	Effect   string   `toml:"effect"`
+	Value    float64  `toml:"value"`
+}
+
+// PerksConfig is the account perk tree.
+type PerksConfig struct {
+	Perks []PerkConfig `toml:"perks"`
+}
+
+// LoadPerks loads a PerksConfig from the given TOML file path.
+func LoadPerks(path string) (PerksConfig, error) {
+	var cfg PerksConfig
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return PerksConfig{}, fmt.Errorf("config: loading %q: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ValidatePerks checks a PerksConfig for consistency errors and returns a
+// list of human-readable error strings. An empty slice means the config is
+// valid.
+func ValidatePerks(cfg PerksConfig) []string {
+	var errs []string
+	ids := map[string]bool{}
+	for _, p := range cfg.Perks {
+		if !validIDRe.MatchString(p.ID) || ids[p.ID] {
+			errs = append(errs, fmt.Sprintf("perk ID %q must match [a-z_]+ and be unique", p.ID))
+		}
+		if p.Cost < 1 {
+			errs = append(errs, fmt.Sprintf("perk %q cost %d must be >= 1", p.ID, p.Cost))
+		}
+		if p.MaxRank < 1 {
+			errs = append(errs, fmt.Sprintf("perk %q max_rank %d must be >= 1", p.ID, p.MaxRank))
+		}
+		for _, r := range p.Requires {
+			if !ids[r] {
+				errs = append(errs, fmt.Sprintf("perk %q requires %q, which is not declared before it", p.ID, r))
+			}
+		}
+		switch p.Effect {
+		case PerkEffectOfflineCap, PerkEffectCPSBonus, PerkEffectClickBonus, PerkEffectExchangeBonus, PerkEffectXPBonus:
+			if p.Value <= 0 {
+				errs = append(errs, fmt.Sprintf("perk %q value %.2f must be > 0", p.ID, p.Value))
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("perk %q has unknown effect %q", p.ID, p.Effect))
+		}
+		ids[p.ID] = true
+	}
+	return errs
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Sources of gameplay XP.
+const (
+	// XPSourceCoinsEarned grants XP each time the coins earned over all
+	// worlds reach the next power of ten.
+	XPSourceCoinsEarned = "coins_earned"
+	// XPSourceBuyOns grants XP for every buy-on bought, by hand or by the
+	// auto-buyer.
+	XPSourceBuyOns = "buy_ons"
+	// XPSourceMilestones grants XP for every world completion milestone
+	// reached.
+	XPSourceMilestones = "milestones"
+	// XPSourceExchangeBoosts grants XP for every exchange boost.
+	XPSourceExchangeBoosts = "exchange_boosts"
+)
+
+// XPSources lists the gameplay XP sources, in display order.
+var XPSources = []string{XPSourceCoinsEarned, XPSourceBuyOns, XPSourceMilestones, XPSourceExchangeBoosts}
+
+// XPSourceConfig is the XP rate of a gameplay source. The n-th grant from
+// the source, counting from 0, is worth Rate / (1 + Decay*n), so returns
+// diminish as the source is farmed.
+type XPSourceConfig struct {
+	Rate  float64 `toml:"rate"`
+	Decay float64 `toml:"decay"`
+}
+
+// XPConfig holds the rates of the gameplay XP sources, keyed by source. A
+// source left out grants no XP.
+type XPConfig struct {
+	Sources map[string]XPSourceConfig `toml:"sources"`
+}
+
+// ValidateXP checks an XPConfig for consistency errors and returns a list of
+// human-readable error strings. An empty slice means the config is valid.
+func ValidateXP(cfg XPConfig) []string {
+	var errs []string
+	for _, name := range slices.Sorted(maps.Keys(cfg.Sources)) {
+		src := cfg.Sources[name]
+		if !slices.Contains(XPSources, name) {
+			errs = append(errs, fmt.Sprintf("unknown XP source %q", name))
+		}
+		if src.Rate < 0 {
+			errs = append(errs, fmt.Sprintf("XP source %q rate %.2f must be >= 0", name, src.Rate))
+		}
+		if src.Decay < 0 {
+			errs = append(errs, fmt.Sprintf("XP source %q decay %.2f must be >= 0", name, src.Decay))
+		}
+	}
+	return errs
+}
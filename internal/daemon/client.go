@@ -0,0 +1,294 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+)
+
+// dialTimeout bounds connecting to the socket, so that checking for a daemon
+// never holds up launch.
+const dialTimeout = time.Second
+
+// Client calls a running daemon.
+type Client struct {
+	rpc *rpc.Client
+}
+
+// Dial connects to the daemon listening on the socket at path. It fails
+// quickly if no daemon is running.
+func Dial(path string) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("daemon: %w", err)
+	}
+	return &Client{rpc: jsonrpc.NewClient(conn)}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error { return c.rpc.Close() }
+
+func (c *Client) call(method string, args, reply any) error {
+	if err := c.rpc.Call(ServiceName+"."+method, args, reply); err != nil {
+		return fmt.Errorf("daemon: %s: %w", method, err)
+	}
+	return nil
+}
+
+// Snapshot returns the daemon's state and the events after since.
+func (c *Client) Snapshot(since uint64) (Snapshot, error) {
+	var reply Snapshot
+	err := c.call("Snapshot", &SnapshotArgs{Since: since}, &reply)
+	return reply, err
+}
+
+// Click clicks once in worldID and returns the coins earned.
+func (c *Client) Click(worldID string) (float64, error) {
+	var reply ClickReply
+	err := c.call("Click", &WorldArgs{World: worldID}, &reply)
+	return reply.Earned, err
+}
+
+// Purchase buys one buyOnID in worldID.
+func (c *Client) Purchase(worldID, buyOnID string) (PurchaseReply, error) {
+	var reply PurchaseReply
+	err := c.call("Purchase", &PurchaseArgs{World: worldID, BuyOn: buyOnID}, &reply)
+	return reply, err
+}
+
+// Prestige prestiges worldID.
+func (c *Client) Prestige(worldID string) (PrestigeReply, error) {
+	var reply PrestigeReply
+	err := c.call("Prestige", &WorldArgs{World: worldID}, &reply)
+	return reply, err
+}
+
+// Exchange performs an exchange boost in worldID.
+func (c *Client) Exchange(worldID string) (ExchangeReply, error) {
+	var reply ExchangeReply
+	err := c.call("Exchange", &WorldArgs{World: worldID}, &reply)
+	return reply, err
+}
+
+// UnlockAutoBuyer buys the auto-buyer with general coins.
+func (c *Client) UnlockAutoBuyer() (bool, error) {
+	var reply UnlockAutoBuyerReply
+	err := c.call("UnlockAutoBuyer", &struct{}{}, &reply)
+	return reply.OK, err
+}
+
+// ConfigureAutoBuyer replaces the auto-buyer's settings.
+func (c *Client) ConfigureAutoBuyer(s autobuy.Settings) error {
+	return c.call("ConfigureAutoBuyer", &s, &ConfigureAutoBuyerReply{})
+}
+
+// RerollChallenge rerolls the daily challenge id.
+func (c *Client) RerollChallenge(id string) (bool, error) {
+	var reply RerollChallengeReply
+	err := c.call("RerollChallenge", &RerollChallengeArgs{ID: id}, &reply)
+	return reply.OK, err
+}
+
+// ClaimLoginReward claims today's login streak reward.
+func (c *Client) ClaimLoginReward() (ClaimLoginRewardReply, error) {
+	var reply ClaimLoginRewardReply
+	err := c.call("ClaimLoginReward", &struct{}{}, &reply)
+	return reply, err
+}
+
+// StartRun starts the challenge run runID in worldID.
+func (c *Client) StartRun(worldID, runID string) (bool, error) {
+	var reply RunReply
+	err := c.call("StartRun", &StartRunArgs{World: worldID, Run: runID}, &reply)
+	return reply.OK, err
+}
+
+// AbandonRun abandons the challenge run in progress in worldID.
+func (c *Client) AbandonRun(worldID string) (bool, error) {
+	var reply RunReply
+	err := c.call("AbandonRun", &WorldArgs{World: worldID}, &reply)
+	return reply.OK, err
+}
+
+// BuyPrestigeNode buys the node nodeID of worldID's prestige tree.
+func (c *Client) BuyPrestigeNode(worldID, nodeID string) (bool, error) {
+	var reply BuyPrestigeNodeReply
+	err := c.call("BuyPrestigeNode", &BuyPrestigeNodeArgs{World: worldID, Node: nodeID}, &reply)
+	return reply.OK, err
+}
+
+// BuyPerk buys a rank of the account perk perkID.
+func (c *Client) BuyPerk(perkID string) (bool, error) {
+	var reply PerkReply
+	err := c.call("BuyPerk", &BuyPerkArgs{Perk: perkID}, &reply)
+	return reply.OK, err
+}
+
+// RespecPerks refunds every account perk rank for general coins.
+func (c *Client) RespecPerks() (bool, error) {
+	var reply PerkReply
+	err := c.call("RespecPerks", &struct{}{}, &reply)
+	return reply.OK, err
+}
+
+// SellWorldCoins sells coins worldID coins on the market and returns the
+// general coins paid.
+func (c *Client) SellWorldCoins(worldID string, coins float64) (MarketReply, error) {
+	var reply MarketReply
+	err := c.call("SellWorldCoins", &MarketArgs{World: worldID, Amount: coins}, &reply)
+	return reply, err
+}
+
+// BuyWorldCoins spends gc general coins on worldID coins on the market and
+// returns the coins bought.
+func (c *Client) BuyWorldCoins(worldID string, gc float64) (MarketReply, error) {
+	var reply MarketReply
+	err := c.call("BuyWorldCoins", &MarketArgs{World: worldID, Amount: gc}, &reply)
+	return reply, err
+}
+
+// Mirror keeps a local engine in step with a daemon, so that the game UI can
+// drive it as if it owned the engine. Actions the player takes on the local
+// engine are applied there at once and passed on to the daemon (Mirror is
+// the local engine's Recorder); Pull replaces the local state with the
+// daemon's. The local engine must not be ticked.
+type Mirror struct {
+	c        *Client
+	eng      *engine.Engine
+	settings save.Settings
+	seq      uint64
+	err      error
+}
+
+// NewMirror attaches a mirror of the daemon behind c to eng and pulls the
+// daemon's state into it.
+func NewMirror(c *Client, eng *engine.Engine) (*Mirror, error) {
+	m := &Mirror{c: c, eng: eng}
+	eng.Recorder = m
+	if _, err := m.Pull(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Settings returns the settings of the daemon's save as of the last pull.
+func (m *Mirror) Settings() save.Settings { return m.settings }
+
+// Err returns the first error talking to the daemon, if any. Once it is set
+// the daemon is presumed gone and actions are no longer passed on.
+func (m *Mirror) Err() error { return m.err }
+
+// Pull replaces the local state with the daemon's and returns the engine
+// events since the last pull. Which screen and world the player is looking
+// at stays local.
+func (m *Mirror) Pull() ([]engine.EngineEvent, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	snap, err := m.c.Snapshot(m.seq)
+	if err != nil {
+		m.err = err
+		return nil, err
+	}
+	gs := save.GameStateFromSave(snap.Save, m.eng.WorldReg)
+	gs.LastScreen = m.eng.State.LastScreen
+	gs.LastWorldID = m.eng.State.LastWorldID
+	gs.ActiveWorldID = m.eng.State.ActiveWorldID
+	// Restore detaches the recorder; the mirror stays attached.
+	m.eng.Restore(gs, snap.Save.Achievements)
+	m.eng.Recorder = m
+	m.settings = snap.Save.Settings
+
+	events := make([]engine.EngineEvent, 0, len(snap.Events))
+	for _, ev := range snap.Events {
+		events = append(events, ev.EngineEvent())
+	}
+	m.seq = snap.Seq
+	return events, nil
+}
+
+// forward passes an action on to the daemon, remembering the first failure.
+func (m *Mirror) forward(call func() error) {
+	if m.err != nil {
+		return
+	}
+	if err := call(); err != nil {
+		m.err = err
+	}
+}
+
+// RecordTick does nothing: only the daemon's engine runs.
+func (m *Mirror) RecordTick(float64) {}
+
+func (m *Mirror) RecordClick(worldID string) {
+	m.forward(func() error { _, err := m.c.Click(worldID); return err })
+}
+
+func (m *Mirror) RecordPurchase(worldID, buyOnID string) {
+	m.forward(func() error { _, err := m.c.Purchase(worldID, buyOnID); return err })
+}
+
+func (m *Mirror) RecordPrestige(worldID string) {
+	m.forward(func() error { _, err := m.c.Prestige(worldID); return err })
+}
+
+func (m *Mirror) RecordExchangeBoost(worldID string) {
+	m.forward(func() error { _, err := m.c.Exchange(worldID); return err })
+}
+
+func (m *Mirror) RecordAutoBuyerUnlock() {
+	m.forward(func() error { _, err := m.c.UnlockAutoBuyer(); return err })
+}
+
+func (m *Mirror) RecordAutoBuyer(s autobuy.Settings) {
+	m.forward(func() error { return m.c.ConfigureAutoBuyer(s) })
+}
+
+// RecordChallengeDay does nothing: a new day begins in the daemon's ticks.
+func (m *Mirror) RecordChallengeDay(string) {}
+
+func (m *Mirror) RecordChallengeReroll(id string) {
+	m.forward(func() error { _, err := m.c.RerollChallenge(id); return err })
+}
+
+// RecordLoginClaim passes the claim on; the daemon claims at its own time.
+func (m *Mirror) RecordLoginClaim(time.Time) {
+	m.forward(func() error { _, err := m.c.ClaimLoginReward(); return err })
+}
+
+func (m *Mirror) RecordRunStart(worldID, runID string) {
+	m.forward(func() error { _, err := m.c.StartRun(worldID, runID); return err })
+}
+
+func (m *Mirror) RecordRunAbandon(worldID string) {
+	m.forward(func() error { _, err := m.c.AbandonRun(worldID); return err })
+}
+
+func (m *Mirror) RecordPrestigeNode(worldID, nodeID string) {
+	m.forward(func() error { _, err := m.c.BuyPrestigeNode(worldID, nodeID); return err })
+}
+
+func (m *Mirror) RecordPerk(perkID string) {
+	m.forward(func() error { _, err := m.c.BuyPerk(perkID); return err })
+}
+
+func (m *Mirror) RecordPerkRespec() {
+	m.forward(func() error { _, err := m.c.RespecPerks(); return err })
+}
+
+func (m *Mirror) RecordMarketSell(worldID string, coins float64) {
+	m.forward(func() error { _, err := m.c.SellWorldCoins(worldID, coins); return err })
+}
+
+func (m *Mirror) RecordMarketBuy(worldID string, gc float64) {
+	m.forward(func() error { _, err := m.c.BuyWorldCoins(worldID, gc); return err })
+}
+
+// End does nothing: the mirror outlives the state replacements it makes.
+func (m *Mirror) End(*engine.Engine) {}
@@ -0,0 +1,517 @@
+// Package daemon runs the game engine without a terminal and serves it to
+// local clients over a Unix socket.
+//
+// The API is JSON-RPC 1.0 as implemented by net/rpc/jsonrpc: one JSON object
+// per request on the socket, e.g.
+//
+//	{"method":"Clicker.Click","params":[{"world":"terra"}],"id":1}
+//
+// The methods are Clicker.Snapshot, Clicker.Click, Clicker.Purchase,
+// Clicker.Prestige, Clicker.Exchange, Clicker.UnlockAutoBuyer,
+// Clicker.ConfigureAutoBuyer, Clicker.RerollChallenge,
+// Clicker.ClaimLoginReward, Clicker.StartRun, Clicker.AbandonRun,
+// Clicker.BuyPrestigeNode, Clicker.BuyPerk, Clicker.RespecPerks,
+// Clicker.SellWorldCoins and Clicker.BuyWorldCoins; their
+// parameters and results are the Args and Reply types below. Client wraps them for Go callers.
+//
+// This package has no Bubble Tea imports.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/streak"
+)
+
+// ServiceName is the JSON-RPC service the methods are registered under.
+const ServiceName = "Clicker"
+
+// maxEvents is how many engine events the server keeps for clients that poll
+// Snapshot. A client that falls further behind misses the oldest ones.
+const maxEvents = 100
+
+// ErrLockLost is returned by Serve when another clicker takes over the
+// profile's save lock while the daemon is running.
+var ErrLockLost = errors.New("daemon: another clicker took over the profile")
+
+// SocketPath returns the control socket for the profile whose save is at
+// savePath. It lives in a directory of its own, which Listen keeps private.
+func SocketPath(savePath string) string {
+	return filepath.Join(filepath.Dir(savePath), "daemon", "daemon.sock")
+}
+
+// Event is an engine event reported to clients, numbered so that a client
+// can ask for the ones it has not seen.
+type Event struct {
+	Seq           uint64                 `json:"seq"`
+	Type          engine.EngineEventType `json:"type"`
+	AchievementID string                 `json:"achievement_id,omitempty"`
+	NewLevel      int                    `json:"new_level,omitempty"`
+	PrevLevel     int                    `json:"prev_level,omitempty"`
+	WorldID       string                 `json:"world_id,omitempty"`
+	MilestoneID   string                 `json:"milestone_id,omitempty"`
+	Purchases     []autobuy.Purchase     `json:"purchases,omitempty"`
+	ChallengeID   string                 `json:"challenge_id,omitempty"`
+	RunID         string                 `json:"run_id,omitempty"`
+	XPSource      string                 `json:"xp_source,omitempty"`
+	XP            int                    `json:"xp,omitempty"`
+	Shock         string                 `json:"shock,omitempty"`
+	Impact        float64                `json:"impact,omitempty"`
+}
+
+// EngineEvent converts ev back to the engine's form.
+func (ev Event) EngineEvent() engine.EngineEvent {
+	return engine.EngineEvent{
+		Type:          ev.Type,
+		AchievementID: ev.AchievementID,
+		NewLevel:      ev.NewLevel,
+		PrevLevel:     ev.PrevLevel,
+		WorldID:       ev.WorldID,
+		MilestoneID:   ev.MilestoneID,
+		Purchases:     ev.Purchases,
+		ChallengeID:   ev.ChallengeID,
+		RunID:         ev.RunID,
+		XPSource:      ev.XPSource,
+		XP:            ev.XP,
+		Shock:         ev.Shock,
+		Impact:        ev.Impact,
+	}
+}
+
+// SnapshotArgs are the parameters of Clicker.Snapshot.
+type SnapshotArgs struct {
+	// Since is the Seq of the last event the client has seen; 0 for none.
+	Since uint64 `json:"since"`
+}
+
+// Snapshot is the result of Clicker.Snapshot: the full game state in save
+// file form, and the events after SnapshotArgs.Since.
+type Snapshot struct {
+	Save   save.SaveFile `json:"save"`
+	Events []Event       `json:"events"`
+	// Seq is the number of the latest event, to pass as Since next time.
+	Seq uint64 `json:"seq"`
+}
+
+// WorldArgs are the parameters of Clicker.Click, Clicker.Prestige and
+// Clicker.Exchange.
+type WorldArgs struct {
+	World string `json:"world"`
+}
+
+// PurchaseArgs are the parameters of Clicker.Purchase.
+type PurchaseArgs struct {
+	World string `json:"world"`
+	BuyOn string `json:"buy_on"`
+}
+
+// ClickReply is the result of Clicker.Click.
+type ClickReply struct {
+	Earned float64 `json:"earned"`
+}
+
+// PurchaseReply is the result of Clicker.Purchase. OK is false if the engine
+// refused the purchase.
+type PurchaseReply struct {
+	OK   bool    `json:"ok"`
+	Cost float64 `json:"cost"`
+}
+
+// PrestigeReply is the result of Clicker.Prestige.
+type PrestigeReply struct {
+	OK     bool                   `json:"ok"`
+	Reward economy.PrestigeReward `json:"reward"`
+}
+
+// ExchangeReply is the result of Clicker.Exchange.
+type ExchangeReply struct {
+	OK     bool                        `json:"ok"`
+	Result economy.ExchangeBoostResult `json:"result"`
+}
+
+// UnlockAutoBuyerReply is the result of Clicker.UnlockAutoBuyer.
+type UnlockAutoBuyerReply struct {
+	OK bool `json:"ok"`
+}
+
+// ConfigureAutoBuyerReply is the result of Clicker.ConfigureAutoBuyer, whose
+// parameters are an autobuy.Settings.
+type ConfigureAutoBuyerReply struct{}
+
+// RerollChallengeArgs are the parameters of Clicker.RerollChallenge.
+type RerollChallengeArgs struct {
+	ID string `json:"id"`
+}
+
+// RerollChallengeReply is the result of Clicker.RerollChallenge. OK is false
+// if the engine refused the reroll.
+type RerollChallengeReply struct {
+	OK bool `json:"ok"`
+}
+
+// ClaimLoginRewardReply is the result of Clicker.ClaimLoginReward, which
+// takes no parameters: the daemon claims at its own time. OK is false if
+// today's reward was already claimed.
+type ClaimLoginRewardReply struct {
+	Offer streak.Offer `json:"offer"`
+	OK    bool         `json:"ok"`
+}
+
+// StartRunArgs are the parameters of Clicker.StartRun.
+type StartRunArgs struct {
+	World string `json:"world"`
+	Run   string `json:"run"`
+}
+
+// RunReply is the result of Clicker.StartRun and of Clicker.AbandonRun,
+// whose parameters are a WorldArgs. OK is false if the engine refused.
+type RunReply struct {
+	OK bool `json:"ok"`
+}
+
+// BuyPrestigeNodeArgs are the parameters of Clicker.BuyPrestigeNode.
+type BuyPrestigeNodeArgs struct {
+	World string `json:"world"`
+	Node  string `json:"node"`
+}
+
+// BuyPrestigeNodeReply is the result of Clicker.BuyPrestigeNode. OK is false
+// if the engine refused.
+type BuyPrestigeNodeReply struct {
+	OK bool `json:"ok"`
+}
+
+// BuyPerkArgs are the parameters of Clicker.BuyPerk.
+type BuyPerkArgs struct {
+	Perk string `json:"perk"`
+}
+
+// PerkReply is the result of Clicker.BuyPerk and of Clicker.RespecPerks,
+// which takes no parameters. OK is false if the engine refused.
+type PerkReply struct {
+	OK bool `json:"ok"`
+}
+
+// MarketArgs are the parameters of Clicker.SellWorldCoins, where Amount is
+// the world coins to sell, and of Clicker.BuyWorldCoins, where it is the
+// general coins to spend.
+type MarketArgs struct {
+	World  string  `json:"world"`
+	Amount float64 `json:"amount"`
+}
+
+// MarketReply is the result of Clicker.SellWorldCoins, where Received is
+// the general coins paid, and of Clicker.BuyWorldCoins, where it is the
+// world coins bought. OK is false if the engine refused.
+type MarketReply struct {
+	OK       bool    `json:"ok"`
+	Received float64 `json:"received"`
+}
+
+// Server ticks an engine in real time, autosaves it, and serves it over a
+// Unix socket. All access to the engine goes through the server's mutex.
+type Server struct {
+	mu       sync.Mutex
+	eng      *engine.Engine
+	savePath string
+	settings save.Settings
+	lock     *save.Lock
+
+	events []Event
+	seq    uint64
+}
+
+// NewServer returns a server for eng, saving to savePath. lock is the
+// profile's save lock, checked before every save; it may be nil.
+func NewServer(eng *engine.Engine, savePath string, settings save.Settings, lock *save.Lock) *Server {
+	return &Server{eng: eng, savePath: savePath, settings: settings, lock: lock}
+}
+
+// Listen creates the control socket at path. A socket left behind by a
+// daemon that crashed is replaced; the caller must hold the profile lock, so
+// no live daemon can own it. The socket's directory is made private to the
+// user before the socket is created, so no one else can ever connect to it.
+func Listen(path string) (net.Listener, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("daemon: %w", err)
+	}
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("daemon: %w", err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("daemon: %w", err)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("daemon: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("daemon: %w", err)
+	}
+	return l, nil
+}
+
+// Serve runs the game and answers clients on l until ctx is done, then
+// saves and closes l. It returns ErrLockLost if another clicker takes the
+// profile over, in which case the final save is skipped.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName(ServiceName, &service{s}); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var connMu sync.Mutex
+	conns := make(map[net.Conn]bool)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			connMu.Lock()
+			conns[conn] = true
+			connMu.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				rpcServer.ServeCodec(jsonrpc.NewServerCodec(conn))
+				connMu.Lock()
+				delete(conns, conn)
+				connMu.Unlock()
+			}()
+		}
+	}()
+
+	err := s.run(ctx)
+	l.Close()
+	connMu.Lock()
+	for conn := range conns {
+		conn.Close()
+	}
+	connMu.Unlock()
+	wg.Wait()
+
+	if err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// run ticks the engine until ctx is done.
+func (s *Server) run(ctx context.Context) error {
+	interval := time.Duration(engine.TickIntervalMs) * time.Millisecond
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.tick(interval.Seconds()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) tick(dt float64) error {
+	s.mu.Lock()
+	events := s.eng.Tick(dt)
+	autosave := false
+	for _, ev := range events {
+		if ev.Type == engine.EventAutoSave {
+			autosave = true
+			continue
+		}
+		s.addEvent(ev)
+	}
+	s.mu.Unlock()
+	if autosave {
+		return s.save()
+	}
+	return nil
+}
+
+func (s *Server) addEvent(ev engine.EngineEvent) {
+	s.seq++
+	s.events = append(s.events, Event{
+		Seq:           s.seq,
+		Type:          ev.Type,
+		AchievementID: ev.AchievementID,
+		NewLevel:      ev.NewLevel,
+		PrevLevel:     ev.PrevLevel,
+		WorldID:       ev.WorldID,
+		MilestoneID:   ev.MilestoneID,
+		Purchases:     ev.Purchases,
+		ChallengeID:   ev.ChallengeID,
+		RunID:         ev.RunID,
+		XPSource:      ev.XPSource,
+		XP:            ev.XP,
+		Shock:         ev.Shock,
+		Impact:        ev.Impact,
+	})
+	if len(s.events) > maxEvents {
+		s.events = s.events[len(s.events)-maxEvents:]
+	}
+}
+
+// save writes the game to the save file, unless the lock has been lost.
+func (s *Server) save() error {
+	if s.lock != nil && !s.lock.Held() {
+		return ErrLockLost
+	}
+	s.mu.Lock()
+	err := save.Save(s.eng.State, s.eng.Earned, s.settings, s.savePath)
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("daemon: save: %v", err)
+		return nil
+	}
+	if s.lock != nil {
+		_ = s.lock.Refresh()
+	}
+	return nil
+}
+
+// service holds the RPC methods, kept apart from Server so that net/rpc
+// only sees these.
+type service struct{ s *Server }
+
+func (v *service) Snapshot(args *SnapshotArgs, reply *Snapshot) error {
+	s := v.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reply.Save = save.SaveFileFromGameState(s.eng.State, s.eng.Earned, s.settings)
+	reply.Seq = s.seq
+	reply.Events = []Event{}
+	for _, ev := range s.events {
+		if ev.Seq > args.Since {
+			reply.Events = append(reply.Events, ev)
+		}
+	}
+	return nil
+}
+
+func (v *service) Click(args *WorldArgs, reply *ClickReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.Earned = v.s.eng.HandleClick(args.World)
+	return nil
+}
+
+func (v *service) Purchase(args *PurchaseArgs, reply *PurchaseReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.Cost, reply.OK = v.s.eng.PurchaseBuyOn(args.World, args.BuyOn)
+	return nil
+}
+
+func (v *service) Prestige(args *WorldArgs, reply *PrestigeReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.Reward, reply.OK = v.s.eng.ExecutePrestige(args.World)
+	return nil
+}
+
+func (v *service) Exchange(args *WorldArgs, reply *ExchangeReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.Result, reply.OK = v.s.eng.ExecuteExchangeBoost(args.World)
+	return nil
+}
+
+func (v *service) UnlockAutoBuyer(_ *struct{}, reply *UnlockAutoBuyerReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.OK = v.s.eng.UnlockAutoBuyer()
+	return nil
+}
+
+func (v *service) ConfigureAutoBuyer(args *autobuy.Settings, _ *ConfigureAutoBuyerReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	v.s.eng.ConfigureAutoBuyer(*args)
+	return nil
+}
+
+func (v *service) RerollChallenge(args *RerollChallengeArgs, reply *RerollChallengeReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.OK = v.s.eng.RerollChallenge(args.ID)
+	return nil
+}
+
+func (v *service) ClaimLoginReward(_ *struct{}, reply *ClaimLoginRewardReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.Offer, reply.OK = v.s.eng.ClaimLoginReward(v.s.eng.Now())
+	return nil
+}
+
+func (v *service) StartRun(args *StartRunArgs, reply *RunReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.OK = v.s.eng.StartRun(args.World, args.Run)
+	return nil
+}
+
+func (v *service) AbandonRun(args *WorldArgs, reply *RunReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.OK = v.s.eng.AbandonRun(args.World)
+	return nil
+}
+
+func (v *service) BuyPrestigeNode(args *BuyPrestigeNodeArgs, reply *BuyPrestigeNodeReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.OK = v.s.eng.BuyPrestigeNode(args.World, args.Node)
+	return nil
+}
+
+func (v *service) BuyPerk(args *BuyPerkArgs, reply *PerkReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.OK = v.s.eng.BuyPerk(args.Perk)
+	return nil
+}
+
+func (v *service) RespecPerks(_ *struct{}, reply *PerkReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.OK = v.s.eng.RespecPerks()
+	return nil
+}
+
+func (v *service) SellWorldCoins(args *MarketArgs, reply *MarketReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.Received, reply.OK = v.s.eng.SellWorldCoins(args.World, args.Amount)
+	return nil
+}
+
+func (v *service) BuyWorldCoins(args *MarketArgs, reply *MarketReply) error {
+	v.s.mu.Lock()
+	defer v.s.mu.Unlock()
+	reply.Received, reply.OK = v.s.eng.BuyWorldCoins(args.World, args.Amount)
+	return nil
+}
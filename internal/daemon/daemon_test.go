@@ -0,0 +1,190 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/world"
+	_ "github.com/clicker-org/clicker/internal/world/worlds"
+)
+
+// TestMain points the config directory at a temporary one, so saving in
+// tests never creates an install key in the real one.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "clicker-daemon-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func newEngine() *engine.Engine {
+	achReg := achievement.NewAchievementRegistry()
+	achievement.RegisterDefaults(achReg)
+	return engine.New(save.GameStateFromSave(save.DefaultSaveFile(), world.DefaultRegistry), world.DefaultRegistry, achReg)
+}
+
+// startDaemon serves a fresh game and returns a connected client and the
+// save path. Stopping the returned func waits for the final save.
+func startDaemon(t *testing.T) (*Client, string, func() error) {
+	t.Helper()
+	dir := t.TempDir()
+	savePath := filepath.Join(dir, "save.json")
+	l, err := Listen(SocketPath(savePath))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	eng := newEngine()
+	eng.StartChallenges("daemon-test", time.Now())
+	go func() { done <- NewServer(eng, savePath, save.Settings{}, nil).Serve(ctx, l) }()
+	var once sync.Once
+	var serveErr error
+	stop := func() error {
+		once.Do(func() {
+			cancel()
+			serveErr = <-done
+		})
+		return serveErr
+	}
+	t.Cleanup(func() { _ = stop() })
+
+	c, err := Dial(SocketPath(savePath))
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	return c, savePath, stop
+}
+
+func TestListen_SocketDirIsPrivate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Chmod(dir, 0o755))
+	path := SocketPath(filepath.Join(dir, "save.json"))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+
+	l, err := Listen(path)
+	require.NoError(t, err)
+	defer l.Close()
+	fi, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), fi.Mode().Perm(), "tightened before the socket exists")
+}
+
+func TestServer_ActionsAndSnapshot(t *testing.T) {
+	c, savePath, stop := startDaemon(t)
+
+	for i := 0; i < 3; i++ {
+		earned, err := c.Click("terra")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, earned)
+	}
+	buy, err := c.Purchase("terra", "auto_miner")
+	require.NoError(t, err)
+	assert.False(t, buy.OK, "three clicks cannot pay for a buy-on")
+	boost, err := c.Exchange("terra")
+	require.NoError(t, err)
+	assert.True(t, boost.OK)
+	prestige, err := c.Prestige("terra")
+	require.NoError(t, err)
+	assert.False(t, prestige.OK)
+	bought, err := c.BuyPrestigeNode("terra", "head_start")
+	require.NoError(t, err)
+	assert.False(t, bought, "no prestige points yet")
+	bought, err = c.BuyPerk("strong_arm")
+	require.NoError(t, err)
+	assert.False(t, bought, "no perk points at level 1")
+	sale, err := c.SellWorldCoins("terra", 1)
+	require.NoError(t, err)
+	assert.True(t, sale.OK)
+	assert.Positive(t, sale.Received)
+	purchase, err := c.BuyWorldCoins("terra", 1e9)
+	require.NoError(t, err)
+	assert.False(t, purchase.OK, "not that many general coins")
+
+	snap, err := c.Snapshot(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), snap.Save.Worlds["terra"].TotalClicks)
+	assert.Positive(t, snap.Save.Player.GeneralCoins)
+
+	require.NoError(t, stop())
+	sf, err := save.Load(savePath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), sf.Worlds["terra"].TotalClicks, "the daemon saves when it stops")
+}
+
+func TestMirror_ForwardsActionsAndPulls(t *testing.T) {
+	c, _, _ := startDaemon(t)
+	local := newEngine()
+	m, err := NewMirror(c, local)
+	require.NoError(t, err)
+
+	local.State.ActiveWorldID = "terra"
+	local.HandleClick("terra")
+	local.HandleClick("terra")
+	local.ConfigureAutoBuyer(autobuy.Settings{Reserve: 0.3})
+	require.Len(t, local.State.Challenges.Daily, challenge.DailyCount, "challenges come from the daemon")
+	require.True(t, local.RerollChallenge(local.State.Challenges.Daily[0].ID))
+	_, ok := local.ClaimLoginReward(time.Now())
+	require.True(t, ok)
+	require.True(t, local.StartRun("aqua", "no_clicks"))
+	require.NoError(t, m.Err())
+
+	snap, err := c.Snapshot(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), snap.Save.Worlds["terra"].TotalClicks, "local clicks reach the daemon")
+	assert.Equal(t, 0.3, snap.Save.AutoBuyer.Reserve, "so do auto-buyer settings")
+	assert.Equal(t, 1, snap.Save.Challenges.Rerolls, "and rerolls")
+	assert.Equal(t, 1, snap.Save.Streak.Days, "and login claims")
+	assert.Equal(t, "no_clicks", snap.Save.Runs.Active["aqua"].ID, "and challenge runs")
+
+	_, err = c.Click("terra")
+	require.NoError(t, err)
+	_, err = m.Pull()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), local.State.Worlds["terra"].TotalClicks)
+	assert.Equal(t, "terra", local.State.ActiveWorldID, "navigation stays local")
+	assert.Same(t, m, local.Recorder, "the mirror stays attached across pulls")
+}
+
+func TestMirror_DaemonGone(t *testing.T) {
+	c, _, stop := startDaemon(t)
+	m, err := NewMirror(c, newEngine())
+	require.NoError(t, err)
+
+	require.NoError(t, stop())
+	_, err = m.Pull()
+	assert.Error(t, err)
+	assert.Equal(t, err, m.Err())
+}
+
+func TestSnapshot_EventsSince(t *testing.T) {
+	s := NewServer(newEngine(), "", save.Settings{}, nil)
+	s.addEvent(engine.EngineEvent{Type: engine.EventLevelUp, NewLevel: 2})
+	s.addEvent(engine.EngineEvent{Type: engine.EventAchievementUnlocked, AchievementID: "first_click"})
+
+	var snap Snapshot
+	require.NoError(t, (&service{s}).Snapshot(&SnapshotArgs{Since: 1}, &snap))
+	require.Len(t, snap.Events, 1)
+	assert.Equal(t, "first_click", snap.Events[0].AchievementID)
+	assert.Equal(t, uint64(2), snap.Seq)
+
+	for i := 0; i < maxEvents+10; i++ {
+		s.addEvent(engine.EngineEvent{Type: engine.EventLevelUp})
+	}
+	require.NoError(t, (&service{s}).Snapshot(&SnapshotArgs{}, &snap))
+	assert.Len(t, snap.Events, maxEvents)
+}
@@ -1,6 +1,9 @@
 package economy
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 type siTier struct {
 	threshold float64
@@ -44,3 +47,26 @@ func FormatCPS(amount float64) string {
 	}
 	return fmt.Sprintf("%.2f", amount)
 }
+
+// FormatSeconds formats a wait in seconds compactly, keeping the two largest
+// units: "45s", "3m 20s", "2h 5m", "4d 3h". Waits of a year or more, and
+// infinite ones, are "never".
+func FormatSeconds(seconds float64) string {
+	switch {
+	case math.IsInf(seconds, 1) || math.IsNaN(seconds) || seconds >= 365*86400:
+		return "never"
+	case seconds < 1:
+		return "now"
+	}
+	s := int64(math.Ceil(seconds))
+	switch {
+	case s < 60:
+		return fmt.Sprintf("%ds", s)
+	case s < 3600:
+		return fmt.Sprintf("%dm %ds", s/60, s%60)
+	case s < 86400:
+		return fmt.Sprintf("%dh %dm", s/3600, s%3600/60)
+	default:
+		return fmt.Sprintf("%dd %dh", s/86400, s%86400/3600)
+	}
+}
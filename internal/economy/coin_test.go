@@ -1,6 +1,7 @@
 package economy
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,3 +28,24 @@ func TestFormatCoinsBare(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatSeconds(t *testing.T) {
+	tests := []struct {
+		seconds  float64
+		expected string
+	}{
+		{0, "now"},
+		{0.4, "now"},
+		{44.2, "45s"},
+		{200, "3m 20s"},
+		{7500, "2h 5m"},
+		{4*86400 + 3*3600 + 59, "4d 3h"},
+		{400 * 86400, "never"},
+		{math.Inf(1), "never"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FormatSeconds(tc.seconds))
+		})
+	}
+}
@@ -13,6 +13,10 @@ const ExchangeBoostCostPercent = 0.20
 // ExchangeBoostRateGain is the multiplicative factor applied to the exchange rate per boost.
 const ExchangeBoostRateGain = 1.01
 
+// ExchangeBoostCooldown is the play time, in seconds, between two exchange
+// boosts in a world, so the exchange rate cannot be raised at will.
+const ExchangeBoostCooldown = 60.0
+
 // CalculateExchangeBoost computes the result of an exchange boost.
 // currentBalance: current world coin balance.
 // exchangeRate: current world coin → GC rate.
@@ -7,33 +7,74 @@ type PrestigeReward struct {
 	GeneralCoinsEarned float64
 	PrestigeMultiplier float64 // the NEW multiplier after this prestige
 	XPGrant            int
+	PrestigePoints     int // points for the world's prestige tree
+}
+
+// PrestigeFormula holds the parameters of a world's prestige reward.
+type PrestigeFormula struct {
+	// General coins: GCScale × totalCoinsEarned^GCExponent.
+	GCScale    float64
+	GCExponent float64
+	// The n-th prestige (0-indexed) multiplies the prestige multiplier by
+	// 1 + MultiplierGain / (n+1)^MultiplierDecay.
+	MultiplierGain  float64
+	MultiplierDecay float64
+	// The n-th prestige (0-indexed) grants XPPerPrestige × (n+1) XP.
+	XPPerPrestige int
+}
+
+// DefaultPrestigeFormula is the reward of worlds that do not configure their
+// own: GC proportional to the sqrt of total coins earned (1 M TC → ~100 GC;
+// 100 M TC → ~1 000 GC; 1 B TC → ~3 162 GC), a multiplier gain starting at
+// ×1.5 and diminishing with each prestige, and 500 XP more per prestige.
+var DefaultPrestigeFormula = PrestigeFormula{
+	GCScale:         0.1,
+	GCExponent:      0.5,
+	MultiplierGain:  0.5,
+	MultiplierDecay: 0.5,
+	XPPerPrestige:   500,
 }
 
 // PrestigeMultiplierGain returns the factor by which the prestige multiplier grows
-// when performing the n-th prestige (0-indexed: first prestige is n=0).
-// Formula: 1 + 0.5/sqrt(n+1) — starts at ×1.5, diminishes with each prestige.
+// when performing the n-th prestige (0-indexed: first prestige is n=0) under
+// DefaultPrestigeFormula: 1 + 0.5/sqrt(n+1), starting at ×1.5.
 func PrestigeMultiplierGain(n int) float64 {
-	return 1.0 + 0.5/math.Sqrt(float64(n+1))
+	return DefaultPrestigeFormula.Gain(n)
 }
 
-// CalculatePrestigeReward computes the reward for prestiging a world.
+// CalculatePrestigeReward computes the reward for prestiging a world under
+// DefaultPrestigeFormula; see PrestigeFormula.Reward.
+func CalculatePrestigeReward(totalCoinsEarned float64, prestigeCount int, currentMultiplier float64) PrestigeReward {
+	return DefaultPrestigeFormula.Reward(totalCoinsEarned, prestigeCount, currentMultiplier)
+}
+
+// Gain returns the factor by which the prestige multiplier grows when
+// performing the n-th prestige (0-indexed: first prestige is n=0).
+func (f PrestigeFormula) Gain(n int) float64 {
+	return 1.0 + f.MultiplierGain/math.Pow(float64(n+1), f.MultiplierDecay)
+}
+
+// Reward computes the reward for prestiging a world.
 // totalCoinsEarned: lifetime coins earned in this world (used as progress proxy).
 // prestigeCount: number of times the world has already been prestiged (0 = first prestige).
 // currentMultiplier: the world's current prestige multiplier (starts at 1.0).
-func CalculatePrestigeReward(totalCoinsEarned float64, prestigeCount int, currentMultiplier float64) PrestigeReward {
-	// General coins: proportional to sqrt of total coins earned.
-	// 1 M TC → ~100 GC; 100 M TC → ~1 000 GC; 1 B TC → ~31 623 GC.
-	gc := math.Sqrt(totalCoinsEarned) * 0.1
+func (f PrestigeFormula) Reward(totalCoinsEarned float64, prestigeCount int, currentMultiplier float64) PrestigeReward {
+	gc := f.GCScale * math.Pow(totalCoinsEarned, f.GCExponent)
 
 	// New multiplier stacks multiplicatively with diminishing returns.
-	newMult := currentMultiplier * PrestigeMultiplierGain(prestigeCount)
+	newMult := currentMultiplier * f.Gain(prestigeCount)
+
+	// XP scales with the prestige count.
+	xp := f.XPPerPrestige * (prestigeCount + 1)
 
-	// XP scales with the prestige count: first prestige = 500 XP, second = 1 000 XP, etc.
-	xp := 500 * (prestigeCount + 1)
+	// Prestige points: sqrt of lifetime coins in millions, at least one.
+	// 1 M TC → 1; 4 M TC → 2; 100 M TC → 10.
+	points := max(1, int(math.Sqrt(totalCoinsEarned/1e6)))
 
 	return PrestigeReward{
 		GeneralCoinsEarned: gc,
 		PrestigeMultiplier: newMult,
 		XPGrant:            xp,
+		PrestigePoints:     points,
 	}
 }
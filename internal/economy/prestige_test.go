@@ -30,6 +30,7 @@ func TestCalculatePrestigeReward(t *testing.T) {
 		currentMultiplier float64
 		wantGC            float64
 		wantXP            int
+		wantPoints        int
 		wantMultGT        float64 // new multiplier must be > this
 	}{
 		{
@@ -39,6 +40,7 @@ func TestCalculatePrestigeReward(t *testing.T) {
 			currentMultiplier: 1.0,
 			wantGC:            100.0,
 			wantXP:            500,
+			wantPoints:        1,
 			wantMultGT:        1.0,
 		},
 		{
@@ -48,6 +50,7 @@ func TestCalculatePrestigeReward(t *testing.T) {
 			currentMultiplier: 1.5,
 			wantGC:            math.Sqrt(10_000_000) * 0.1,
 			wantXP:            1000,
+			wantPoints:        3, // floor(sqrt(10))
 			wantMultGT:        1.5,
 		},
 	}
@@ -56,11 +59,27 @@ func TestCalculatePrestigeReward(t *testing.T) {
 			r := CalculatePrestigeReward(tc.totalCoins, tc.prestigeCount, tc.currentMultiplier)
 			assert.InDelta(t, tc.wantGC, r.GeneralCoinsEarned, 0.01)
 			assert.Equal(t, tc.wantXP, r.XPGrant)
+			assert.Equal(t, tc.wantPoints, r.PrestigePoints)
 			assert.Greater(t, r.PrestigeMultiplier, tc.wantMultGT)
 		})
 	}
 }
 
+func TestDefaultPrestigeFormula_MatchesBaseline(t *testing.T) {
+	for n := range 4 {
+		assert.Equal(t, PrestigeMultiplierGain(n), DefaultPrestigeFormula.Gain(n))
+		assert.Equal(t, CalculatePrestigeReward(4e6, n, 1.5), DefaultPrestigeFormula.Reward(4e6, n, 1.5))
+	}
+}
+
+func TestPrestigeFormula_Custom(t *testing.T) {
+	f := PrestigeFormula{GCScale: 2, GCExponent: 1.0 / 3, MultiplierGain: 0.2, MultiplierDecay: 0, XPPerPrestige: 50}
+	r := f.Reward(1e6, 2, 2.0)
+	assert.InDelta(t, 200.0, r.GeneralCoinsEarned, 1e-6) // 2 × cbrt(1M)
+	assert.InDelta(t, 2.4, r.PrestigeMultiplier, 1e-9, "no decay: a flat +20%")
+	assert.Equal(t, 150, r.XPGrant)
+}
+
 func TestCalculateExchangeBoost(t *testing.T) {
 	t.Run("standard boost", func(t *testing.T) {
 		r := CalculateExchangeBoost(1000, 0.001)
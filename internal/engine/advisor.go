@@ -0,0 +1,168 @@
+package engine
+
+import (
+	"maps"
+	"math"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/upgrade"
+)
+
+// Recommendations of the prestige advisor.
+const (
+	// AdviceNotReady: the world's prestige threshold is not met yet.
+	AdviceNotReady = "not_ready"
+	// AdvicePrestige: prestiging now earns the most general coins per hour.
+	AdvicePrestige = "prestige"
+	// AdviceWait: playing on for the advice's Wait earns more general coins
+	// per hour than prestiging now.
+	AdviceWait = "wait"
+)
+
+// AdvisorClickRate is the clicks per second the advisor assumes a player
+// makes while a reset world has no CPS yet.
+const AdvisorClickRate = 5.0
+
+// maxRegainPurchases bounds the purchases simulated to estimate how long a
+// world takes to regain its CPS.
+const maxRegainPurchases = 10000
+
+// PrestigeAdvice weighs prestiging a world now against playing on first.
+type PrestigeAdvice struct {
+	// Now is the reward for prestiging now, and Later for prestiging after
+	// Wait more play at the current CPS.
+	Now   economy.PrestigeReward
+	Later economy.PrestigeReward
+	Wait  time.Duration
+	// NowGCPerHour and LaterGCPerHour are those rewards' general coins per
+	// hour of play since the world was last reset, or 0 if none is recorded.
+	NowGCPerHour   float64
+	LaterGCPerHour float64
+	// RegainSeconds is the estimated play time after prestiging now to get
+	// back to the current CPS, buying buy-ons back by best value under the
+	// new multiplier: 0 if the prestige tree's start buy-ons already do, +Inf
+	// if buy-ons alone cannot.
+	RegainSeconds float64
+	// Recommendation is an Advice* constant.
+	Recommendation string
+
+	// What prestiging now loses: the balance, the buy-ons and upgrades
+	// owned and the CPS they make.
+	LostCoins    float64
+	LostBuyOns   int
+	LostUpgrades int
+	LostCPS      float64
+	// StartBuyOns are the buy-ons the prestige tree gives straight back.
+	StartBuyOns int
+}
+
+// PrestigeAdvice returns the advice on prestiging worldID now rather than
+// after wait more play, or the zero advice for an unknown world.
+func (e *Engine) PrestigeAdvice(worldID string, wait time.Duration) PrestigeAdvice {
+	ws, ok := e.State.Worlds[worldID]
+	if !ok {
+		return PrestigeAdvice{}
+	}
+	f := e.prestigeFormula(worldID)
+	later := ws.TotalCoinsEarned + ws.CPS*wait.Seconds()
+	a := PrestigeAdvice{
+		Now:          f.Reward(ws.TotalCoinsEarned, ws.PrestigeCount, ws.PrestigeMultiplier),
+		Later:        f.Reward(later, ws.PrestigeCount, ws.PrestigeMultiplier),
+		Wait:         wait,
+		LostCoins:    ws.Coins,
+		LostUpgrades: len(ws.PurchasedUpgrades),
+		LostCPS:      ws.CPS,
+	}
+	for _, n := range ws.BuyOnCounts {
+		a.LostBuyOns += n
+	}
+	if played := ws.SinceResetSeconds; played > 0 {
+		a.NowGCPerHour = a.Now.GeneralCoinsEarned / played * 3600
+		a.LaterGCPerHour = a.Later.GeneralCoinsEarned / (played + wait.Seconds()) * 3600
+	}
+	starts := e.startBuyOns(worldID)
+	for _, n := range starts {
+		a.StartBuyOns += n
+	}
+	a.RegainSeconds = e.regainSeconds(worldID, starts, a.Now.PrestigeMultiplier)
+
+	switch {
+	case !e.CanPrestige(worldID):
+		a.Recommendation = AdviceNotReady
+	case a.LaterGCPerHour > a.NowGCPerHour:
+		a.Recommendation = AdviceWait
+	default:
+		a.Recommendation = AdvicePrestige
+	}
+	return a
+}
+
+// startBuyOns returns the buy-ons worldID's prestige tree starts resets
+// with, as grantStartBuyOns gives them outside challenge runs.
+func (e *Engine) startBuyOns(worldID string) map[string]int {
+	counts := make(map[string]int)
+	reg, ok := e.UpgradeReg[worldID]
+	if !ok {
+		return counts
+	}
+	for id, n := range e.treeEffects(worldID).StartBuyOns {
+		if _, ok := reg.GetBuyOn(id); ok {
+			counts[id] = n
+		}
+	}
+	return counts
+}
+
+// regainSeconds estimates how long worldID takes to get back to its current
+// CPS after a reset to the buy-ons counts with the prestige multiplier
+// newMult. It buys the buy-on that pays for itself soonest counting the wait
+// to afford it, as ShopHints marks it, one at a time; upgrades are left out.
+func (e *Engine) regainSeconds(worldID string, counts map[string]int, newMult float64) float64 {
+	ws := e.State.Worlds[worldID]
+	reg, ok := e.UpgradeReg[worldID]
+	if !ok || ws.CPS <= 0 {
+		return 0
+	}
+	// the CPS multiplier with the new prestige multiplier in place of the
+	// current one.
+	mult := e.cpsMultiplier(worldID) * newMult
+	clicks := e.ClickPower(worldID) * newMult * AdvisorClickRate
+	if ws.PrestigeMultiplier > 0 {
+		mult /= ws.PrestigeMultiplier
+		clicks /= ws.PrestigeMultiplier
+	}
+	counts = maps.Clone(counts)
+	upgrades := reg.ListUpgrades()
+	coins, elapsed := 0.0, 0.0
+	for range maxRegainPurchases {
+		cps := upgrade.CalculateWorldCPS(reg, counts, nil, mult, 1.0)
+		if cps >= ws.CPS {
+			return elapsed
+		}
+		income := cps
+		if income <= 0 {
+			income = clicks
+		}
+		var next upgrade.BuyOn
+		cost, best := 0.0, math.Inf(1)
+		for _, b := range reg.ListBuyOns() {
+			if b.LevelRequirement() > e.State.Player.Level {
+				continue
+			}
+			c := e.buyOnCost(worldID, b, counts[b.ID()])
+			t := secondsToEarn(c-coins, income) + secondsToEarn(c, upgrade.MarginalCPS(b, upgrades, nil, mult))
+			if t < best {
+				next, cost, best = b, c, t
+			}
+		}
+		if next == nil {
+			break
+		}
+		wait := secondsToEarn(cost-coins, income)
+		elapsed += wait
+		coins += wait*income - cost
+		counts[next.ID()]++
+	}
+	return math.Inf(1)
+}
@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrestigeAdvice_Recommendation(t *testing.T) {
+	eng := newTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	ws.TotalCoinsEarned = 500_000
+	ws.SinceResetSeconds = 3600
+	ws.CPS = 10
+
+	a := eng.PrestigeAdvice("terra", 30*time.Minute)
+	assert.Equal(t, AdviceNotReady, a.Recommendation)
+
+	ws.TotalCoinsEarned = 1e6
+	a = eng.PrestigeAdvice("terra", 30*time.Minute)
+	assert.Equal(t, AdvicePrestige, a.Recommendation, "10/s adds little in half an hour")
+	assert.InDelta(t, 100, a.NowGCPerHour, 1e-6, "100 GC over an hour")
+	assert.Less(t, a.LaterGCPerHour, a.NowGCPerHour)
+	assert.Greater(t, a.Later.GeneralCoinsEarned, a.Now.GeneralCoinsEarned)
+	assert.Equal(t, a.Now.PrestigeMultiplier, a.Later.PrestigeMultiplier)
+
+	ws.SinceResetSeconds = 600
+	ws.CPS = 1e8
+	a = eng.PrestigeAdvice("terra", 30*time.Minute)
+	assert.Equal(t, AdviceWait, a.Recommendation)
+	assert.Greater(t, a.LaterGCPerHour, a.NowGCPerHour)
+
+	ws.SinceResetSeconds = 0
+	a = eng.PrestigeAdvice("terra", 30*time.Minute)
+	assert.Zero(t, a.NowGCPerHour, "no play time recorded")
+	assert.Equal(t, AdvicePrestige, a.Recommendation)
+}
+
+func TestPrestigeAdvice_BreakdownAndRegain(t *testing.T) {
+	eng := newTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	ws.TotalCoinsEarned = 1e6
+	ws.Coins = 1800
+	ws.BuyOnCounts["auto_miner"] = 30
+	eng.refreshCPS("terra")
+	require.Positive(t, ws.CPS)
+
+	a := eng.PrestigeAdvice("terra", time.Minute)
+	assert.Equal(t, 1800.0, a.LostCoins)
+	assert.Equal(t, 30, a.LostBuyOns)
+	assert.Equal(t, ws.CPS, a.LostCPS)
+	assert.Zero(t, a.StartBuyOns)
+	assert.Positive(t, a.RegainSeconds)
+	assert.False(t, math.IsInf(a.RegainSeconds, 1))
+
+	ws.PrestigeTree["head_start"] = true
+	b := eng.PrestigeAdvice("terra", time.Minute)
+	assert.Equal(t, 10, b.StartBuyOns)
+	assert.Less(t, b.RegainSeconds, a.RegainSeconds, "a head start regains sooner")
+	assert.Equal(t, 30, ws.BuyOnCounts["auto_miner"], "advice changes nothing")
+}
+
+func TestSinceResetSeconds_CountsUpAndResets(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	eng.Tick(2)
+	ws := eng.State.Worlds["terra"]
+	assert.InDelta(t, 2, ws.SinceResetSeconds, 1e-9)
+
+	ws.TotalCoinsEarned = 1e6
+	_, ok := eng.ExecutePrestige("terra")
+	require.True(t, ok)
+	assert.Zero(t, ws.SinceResetSeconds)
+	assert.InDelta(t, 2, eng.State.Worlds["aqua"].SinceResetSeconds, 1e-9)
+}
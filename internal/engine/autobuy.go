@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/upgrade"
+)
+
+// maxAutoBuysPerRun bounds how many purchases the auto-buyer makes in one
+// world per run, so a large balance is spent over a few runs rather than in
+// one tick.
+const maxAutoBuysPerRun = 50
+
+// AutoBuyerUnlocked reports whether the player may use the auto-buyer: it
+// was bought with general coins, or the player reached autobuy.UnlockLevel.
+func (e *Engine) AutoBuyerUnlocked() bool {
+	return e.State.AutoBuyer.Unlocked || e.State.Player.Level >= autobuy.UnlockLevel
+}
+
+// UnlockAutoBuyer buys the auto-buyer for autobuy.UnlockCost general coins.
+// It returns false if it is already unlocked or the player cannot afford it.
+func (e *Engine) UnlockAutoBuyer() bool {
+	if e.AutoBuyerUnlocked() || e.State.Player.GeneralCoins < autobuy.UnlockCost {
+		return false
+	}
+	e.State.Player.GeneralCoins -= autobuy.UnlockCost
+	e.State.AutoBuyer.Unlocked = true
+	if e.Recorder != nil {
+		e.Recorder.RecordAutoBuyerUnlock()
+	}
+	return true
+}
+
+// ConfigureAutoBuyer replaces the auto-buyer's settings with s. Whether it
+// is unlocked is kept, and the reserve is clamped to [0, autobuy.MaxReserve].
+func (e *Engine) ConfigureAutoBuyer(s autobuy.Settings) {
+	s = s.Clone()
+	s.Unlocked = e.State.AutoBuyer.Unlocked
+	s.Reserve = min(max(s.Reserve, 0), autobuy.MaxReserve)
+	e.State.AutoBuyer = s
+	if e.Recorder != nil {
+		e.Recorder.RecordAutoBuyer(s.Clone())
+	}
+}
+
+// runAutoBuyer spends each enabled world's balance above the reserve
+// according to the world's policy and returns one EventAutoBuy per world
+// that bought something. Its purchases are not reported to the Recorder:
+// replaying the tick repeats them.
+func (e *Engine) runAutoBuyer() []EngineEvent {
+	if !e.AutoBuyerUnlocked() {
+		return nil
+	}
+	var events []EngineEvent
+	for _, w := range e.WorldReg.List() {
+		settings := e.State.AutoBuyer.World(w.ID())
+		ws, ok := e.State.Worlds[w.ID()]
+		reg, hasReg := e.UpgradeReg[w.ID()]
+		if !settings.Enabled || !ok || !hasReg {
+			continue
+		}
+		floor := ws.Coins * e.State.AutoBuyer.Reserve
+		var purchases []autobuy.Purchase
+		for n := 0; n < maxAutoBuysPerRun; n++ {
+			candidates := e.autoBuyCandidates(w.ID(), reg)
+			item, ok := autobuy.Pick(settings, candidates, ws.Coins-floor)
+			if !ok {
+				break
+			}
+			ws.Spend(item.Cost)
+			if item.Upgrade {
+				ws.PurchasedUpgrades[item.ID] = true
+			} else {
+				ws.BuyOnCounts[item.ID]++
+				e.advanceChallenges(challenge.KindBuy, w.ID(), 1)
+				e.gainXP(config.XPSourceBuyOns, 1)
+			}
+			ws.CPS = upgrade.CalculateWorldCPS(reg, ws.BuyOnCounts, ws.PurchasedUpgrades, e.cpsMultiplier(w.ID()), 1.0)
+			purchases = addPurchase(purchases, item)
+			settings.Bought(item.ID)
+		}
+		if len(purchases) > 0 {
+			if settings.Policy == autobuy.PolicyPriority {
+				e.State.AutoBuyer.Worlds[w.ID()] = settings
+			}
+			events = append(events, EngineEvent{Type: EventAutoBuy, WorldID: w.ID(), Purchases: purchases})
+		}
+	}
+	return events
+}
+
+// autoBuyCandidates returns the auto-buyer's candidates in worldID. Their
+// gains are under the same CPS multiplier as ShopHints, and buy-ons are
+// priced as PurchaseBuyOn does, with the prestige tree and the challenge run
+// in progress; those the run bars are dropped.
+func (e *Engine) autoBuyCandidates(worldID string, reg *upgrade.WorldUpgradeRegistry) []autobuy.Item {
+	ws := e.State.Worlds[worldID]
+	candidates := autobuy.Candidates(reg, ws, e.State.Player.Level, e.cpsMultiplier(worldID))
+	out := candidates[:0]
+	for _, item := range candidates {
+		if !item.Upgrade {
+			b, ok := reg.GetBuyOn(item.ID)
+			if !ok || !e.buyOnAllowed(worldID, reg, b) {
+				continue
+			}
+			item.Cost = e.buyOnCost(worldID, b, ws.BuyOnCounts[item.ID])
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// addPurchase adds item to the run's report, folding repeats of an item
+// into one line.
+func addPurchase(purchases []autobuy.Purchase, item autobuy.Item) []autobuy.Purchase {
+	for i := range purchases {
+		if purchases[i].ID == item.ID {
+			purchases[i].Count++
+			purchases[i].Cost += item.Cost
+			return purchases
+		}
+	}
+	return append(purchases, autobuy.Purchase{ID: item.ID, Name: item.Name, Upgrade: item.Upgrade, Count: 1, Cost: item.Cost})
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+)
+
+func TestUnlockAutoBuyer(t *testing.T) {
+	eng := newTestEngine(t)
+	assert.False(t, eng.AutoBuyerUnlocked())
+	assert.False(t, eng.UnlockAutoBuyer(), "no general coins yet")
+
+	eng.State.Player.GeneralCoins = autobuy.UnlockCost + 5
+	require.True(t, eng.UnlockAutoBuyer())
+	assert.True(t, eng.AutoBuyerUnlocked())
+	assert.Equal(t, 5.0, eng.State.Player.GeneralCoins)
+	assert.False(t, eng.UnlockAutoBuyer(), "only bought once")
+
+	leveled := newTestEngine(t)
+	leveled.State.Player.Level = autobuy.UnlockLevel
+	assert.True(t, leveled.AutoBuyerUnlocked())
+}
+
+func TestTick_AutoBuyerKeepsReserve(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.AutoBuyer.Unlocked = true
+	eng.ConfigureAutoBuyer(autobuy.Settings{
+		Reserve: 0.5,
+		Worlds:  map[string]autobuy.WorldSettings{"terra": {Enabled: true, Policy: autobuy.PolicyCheapest}},
+	})
+	terra := eng.State.Worlds["terra"]
+	terra.Coins = 200
+	eng.State.Worlds["aqua"].Coins = 200
+
+	events := eng.Tick(AutoBuyInterval)
+
+	require.Equal(t, 1, countEvents(events, EventAutoBuy))
+	var ev EngineEvent
+	for _, e := range events {
+		if e.Type == EventAutoBuy {
+			ev = e
+		}
+	}
+	assert.Equal(t, "terra", ev.WorldID)
+	// half of 200 is kept back, so a second miner at 57.50 must wait.
+	assert.Equal(t, []autobuy.Purchase{{ID: "auto_miner", Name: "Auto Miner", Count: 1, Cost: 50}}, ev.Purchases)
+	assert.Equal(t, 1, terra.BuyOnCounts["auto_miner"])
+	assert.InDelta(t, 150, terra.Coins, 1e-9)
+	assert.InDelta(t, 0.1, terra.CPS, 1e-9)
+	assert.Empty(t, eng.State.Worlds["aqua"].BuyOnCounts, "aqua is switched off")
+}
+
+func TestTick_AutoBuyerLockedDoesNothing(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.ConfigureAutoBuyer(autobuy.Settings{
+		Worlds: map[string]autobuy.WorldSettings{"terra": {Enabled: true}},
+	})
+	eng.State.Worlds["terra"].Coins = 1000
+
+	events := eng.Tick(AutoBuyInterval)
+
+	assert.Zero(t, countEvents(events, EventAutoBuy))
+	assert.False(t, eng.State.AutoBuyer.Unlocked, "configuring does not unlock")
+}
+
+func TestAutoBuyCandidates_GainMatchesShopHints(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Player.Perks["industrialist"] = 2
+	terra := eng.State.Worlds["terra"]
+	terra.PrestigeTree["core_tap"] = true
+	terra.PrestigeMultiplier = 1.5
+	terra.BuyOnCounts["auto_miner"] = 3
+
+	hints := eng.ShopHints("terra")
+	for _, item := range eng.autoBuyCandidates("terra", eng.UpgradeReg["terra"]) {
+		if item.Upgrade {
+			continue
+		}
+		for _, h := range hints {
+			if h.BuyOnID == item.ID {
+				assert.InDelta(t, h.MarginalCPS, item.Gain, 1e-9, item.ID)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"slices"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/challenge"
+)
+
+// StartChallenges begins a play session for profile at now: it generates
+// the session's challenge and, if the day changed since the last session,
+// the day's challenges. Call it once per session, before a Recorder is
+// attached. Engines it is never called on, such as those of one-off
+// commands, track the challenges in the state but never generate new ones.
+func (e *Engine) StartChallenges(profile string, now time.Time) {
+	cs := &e.State.Challenges
+	cs.Seed = profile
+	s := challenge.Session(profile, now.UTC().Format("20060102T150405"), e.WorldReg.IDs())
+	cs.Session = &s
+	e.BeginChallengeDay(e.trustedNow(now).Format(time.DateOnly))
+}
+
+// BeginChallengeDay replaces the daily challenges with those of day, a
+// local date as "2006-01-02", and restores the day's rerolls. Unfinished
+// challenges of the previous day are dropped. It returns false if day is
+// not later than the challenges' day, so that moving the clock back and
+// forth never brings a day's challenges back, or if StartChallenges has
+// never been called for the state.
+func (e *Engine) BeginChallengeDay(day string) bool {
+	cs := &e.State.Challenges
+	if cs.Seed == "" || day <= cs.Day {
+		return false
+	}
+	cs.Day = day
+	cs.Daily = challenge.Daily(cs.Seed, day, e.WorldReg.IDs())
+	cs.Rerolls = 0
+	if e.Recorder != nil {
+		e.Recorder.RecordChallengeDay(day)
+	}
+	return true
+}
+
+// CanRerollChallenge reports whether the daily challenge id can be swapped
+// for another: it is unfinished and a reroll is left today.
+func (e *Engine) CanRerollChallenge(id string) bool {
+	cs := &e.State.Challenges
+	i := slices.IndexFunc(cs.Daily, func(c challenge.Challenge) bool { return c.ID == id })
+	return i >= 0 && !cs.Daily[i].Completed && cs.Rerolls < challenge.RerollsPerDay
+}
+
+// RerollChallenge swaps the daily challenge id for another one of a
+// template not in use today. It returns false if CanRerollChallenge does.
+func (e *Engine) RerollChallenge(id string) bool {
+	if !e.CanRerollChallenge(id) {
+		return false
+	}
+	cs := &e.State.Challenges
+	slot := slices.IndexFunc(cs.Daily, func(c challenge.Challenge) bool { return c.ID == id })
+	cs.Rerolls++
+	cs.Daily[slot] = challenge.Reroll(cs.Seed, cs.Day, slot, cs.Rerolls, e.WorldReg.IDs(), cs.Daily)
+	if e.Recorder != nil {
+		e.Recorder.RecordChallengeReroll(id)
+	}
+	return true
+}
+
+// advanceChallenges adds amount to the progress of every unfinished
+// challenge counting kind in worldID. Challenges are completed by the next
+// Tick.
+func (e *Engine) advanceChallenges(kind challenge.Kind, worldID string, amount float64) {
+	for _, c := range e.State.Challenges.Active() {
+		if c.Completed || c.Kind != kind || (c.World != "" && c.World != worldID) {
+			continue
+		}
+		c.Progress = min(c.Progress+amount, c.Target)
+	}
+}
+
+// completeChallenges grants the rewards of every challenge that reached its
+// target and returns an EventChallengeCompleted for each, preceded by an
+// EventLevelUp if the XP raised the player's level.
+func (e *Engine) completeChallenges() []EngineEvent {
+	var events []EngineEvent
+	cs := &e.State.Challenges
+	for _, c := range cs.Active() {
+		if c.Completed || !c.Done() {
+			continue
+		}
+		c.Completed = true
+		cs.CompletedTotal++
+		prevLevel := e.State.Player.Level
+		if e.addXP(c.RewardXP) {
+			events = append(events, EngineEvent{Type: EventLevelUp, NewLevel: e.State.Player.Level, PrevLevel: prevLevel})
+		}
+		e.State.Player.GeneralCoins += c.RewardGC
+		e.State.Player.LifetimeGeneralCoins += c.RewardGC
+		events = append(events, EngineEvent{Type: EventChallengeCompleted, ChallengeID: c.ID})
+	}
+	return events
+}
@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/clock"
+)
+
+func TestChallenges_ProgressAndRewards(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	eng.State.Challenges.Daily = []challenge.Challenge{
+		{ID: "clicks", Kind: challenge.KindClicks, Target: 3, RewardXP: 50, RewardGC: 2},
+		{ID: "aqua", Kind: challenge.KindEarn, World: "aqua", Target: 1e9, RewardXP: 10},
+	}
+
+	for range 3 {
+		eng.HandleClick("terra")
+	}
+	events := eng.Tick(0.1)
+
+	assert.Equal(t, 1, countEvents(events, EventChallengeCompleted))
+	cs := eng.State.Challenges
+	assert.True(t, cs.Daily[0].Completed)
+	assert.Zero(t, cs.Daily[1].Progress, "terra coins do not count for aqua")
+	assert.Equal(t, 1, cs.CompletedTotal)
+	assert.Equal(t, 50, eng.State.Player.XP)
+	assert.Equal(t, 2.0, eng.State.Player.GeneralCoins)
+
+	eng.HandleClick("terra")
+	assert.Zero(t, countEvents(eng.Tick(0.1), EventChallengeCompleted), "rewarded once")
+	assert.Equal(t, 3.0, eng.State.Challenges.Daily[0].Progress, "capped at the target")
+}
+
+func TestChallenges_DaysAndRerolls(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	assert.False(t, eng.BeginChallengeDay("2026-10-18"), "nothing is generated before a session starts")
+
+	eng.StartChallenges("p", time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local))
+	cs := &eng.State.Challenges
+	require.Len(t, cs.Daily, challenge.DailyCount)
+	require.NotNil(t, cs.Session)
+	assert.Equal(t, "2026-10-18", cs.Day)
+
+	old := cs.Daily[0]
+	require.True(t, eng.RerollChallenge(old.ID))
+	assert.NotEqual(t, old.Template, cs.Daily[0].Template)
+	assert.False(t, eng.RerollChallenge(cs.Daily[1].ID), "one reroll a day")
+
+	assert.False(t, eng.BeginChallengeDay("2026-10-18"))
+	require.True(t, eng.BeginChallengeDay("2026-10-19"))
+	assert.Zero(t, cs.Rerolls)
+	assert.Equal(t, "2026-10-19#0", cs.Daily[0].ID)
+}
+
+func TestChallenges_ClockSetBackKeepsTheDay(t *testing.T) {
+	eng := newTestEngine(t)
+	day := time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local)
+	fake := clock.NewFake(day)
+	eng.Clock = fake
+	eng.StartChallenges("p", day)
+	cs := &eng.State.Challenges
+	eng.Tick(0.1)
+	cs.Daily[0].Completed = true
+
+	// Set back a day, then forward again: neither brings a new set.
+	fake.SetWall(day.AddDate(0, 0, -1))
+	eng.Tick(0.1)
+	assert.Equal(t, "2026-10-19", cs.Day)
+	assert.False(t, eng.BeginChallengeDay("2026-10-18"), "an earlier day is never generated")
+	fake.SetWall(day)
+	eng.Tick(0.1)
+	assert.Equal(t, "2026-10-19", cs.Day)
+	assert.True(t, cs.Daily[0].Completed, "the day's challenges are kept")
+}
@@ -1,10 +1,14 @@
 package engine
 
 import (
+	"time"
+
 	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/clock"
+	"github.com/clicker-org/clicker/internal/config"
 	"github.com/clicker-org/clicker/internal/economy"
 	"github.com/clicker-org/clicker/internal/gamestate"
-	"github.com/clicker-org/clicker/internal/player"
 	"github.com/clicker-org/clicker/internal/upgrade"
 	"github.com/clicker-org/clicker/internal/world"
 )
@@ -22,8 +26,29 @@ type Engine struct {
 	// Earned achievements map (achievementID -> true if earned).
 	Earned map[string]bool
 
+	// Clock is the time source for clock-tamper detection and offline income.
+	// Defaults to the system clock; tests may replace it before the first Tick.
+	Clock clock.Clock
+
+	// Recorder, if set, receives every action from here on. Attach it to a
+	// freshly created engine: the debounce timers are not part of the
+	// snapshot a recording starts from.
+	Recorder Recorder
+
 	autosaveTimer    float64
 	achievCheckTimer float64
+	autoBuyTimer     float64
+
+	// pending holds the events of actions taken between ticks, for the next
+	// Tick to return.
+	pending []EngineEvent
+
+	// Clock observation anchors for the running session (see observeClock).
+	clockAnchored bool
+	anchorMono    time.Duration
+	anchorWall    time.Time
+	lastMono      time.Duration
+	lastWall      time.Time
 }
 
 // New creates and returns a new Engine. It builds per-world upgrade registries
@@ -51,7 +76,28 @@ func New(
 		AchievReg:  achievReg,
 		UpgradeReg: upReg,
 		Earned:     make(map[string]bool),
+		Clock:      clock.System(),
+	}
+}
+
+// Restore replaces the engine's state and earned achievements, e.g. after
+// the player imports a save while the game is running. Timers restart so the
+// next autosave and achievement check happen on their normal schedule.
+func (e *Engine) Restore(gs gamestate.GameState, earned map[string]bool) {
+	if e.Recorder != nil {
+		// a recording cannot describe a wholesale state change; end it here.
+		e.Recorder.End(e)
+		e.Recorder = nil
+	}
+	if earned == nil {
+		earned = make(map[string]bool)
 	}
+	e.State = gs
+	e.Earned = earned
+	e.autosaveTimer = 0
+	e.achievCheckTimer = 0
+	e.autoBuyTimer = 0
+	e.pending = nil
 }
 
 // ClickPower returns the coins generated per manual click in the given world.
@@ -60,7 +106,7 @@ func (e *Engine) ClickPower(worldID string) float64 {
 	if !ok {
 		return 0
 	}
-	base := 1.0 * ws.PrestigeMultiplier
+	base := 1.0 * ws.PrestigeMultiplier * (1 + e.treeEffects(worldID).ClickBonus)
 	if m := e.globalClickMultiplier(); m > 0 {
 		base *= m
 	}
@@ -68,16 +114,17 @@ func (e *Engine) ClickPower(worldID string) float64 {
 }
 
 // globalClickMultiplier returns the effective global click multiplier sourced
-// from engine-owned state. Phase 0 has no general shop effects yet.
+// from engine-owned state: the player's click perks.
 func (e *Engine) globalClickMultiplier() float64 {
-	return 1.0
+	return 1 + e.perkEffects().ClickBonus
 }
 
 // HandleClick records a manual click for the given world, adds coins, and
-// returns the number of coins earned by this click.
+// returns the number of coins earned by this click. Clicks are refused, and
+// earn nothing, while a challenge run forbidding them is in progress.
 func (e *Engine) HandleClick(worldID string) float64 {
 	ws, ok := e.State.Worlds[worldID]
-	if !ok {
+	if !ok || e.State.Runs.Restrictions(worldID).NoClicks {
 		return 0
 	}
 	earned := e.ClickPower(worldID)
@@ -89,12 +136,20 @@ func (e *Engine) HandleClick(worldID string) float64 {
 		e.State.Player.WorldTotalCoinsEarned = make(map[string]float64)
 	}
 	e.State.Player.WorldTotalCoinsEarned[worldID] += earned
+	e.advanceChallenges(challenge.KindClicks, worldID, 1)
+	e.advanceChallenges(challenge.KindEarn, worldID, earned)
+	e.advanceRun(worldID, earned)
+	if e.Recorder != nil {
+		e.Recorder.RecordClick(worldID)
+	}
 	return earned
 }
 
 // PurchaseBuyOn attempts to buy one unit of the given buy-on in the given world.
 // Returns (cost, true) on success, or (0, false) if the purchase cannot proceed
-// (insufficient coins, level gate not met, or unknown world/buy-on).
+// (insufficient coins, level gate not met, barred by the challenge run in
+// progress, or unknown world/buy-on). The world's prestige tree and the run
+// may change the cost.
 func (e *Engine) PurchaseBuyOn(worldID, buyOnID string) (float64, bool) {
 	ws, ok := e.State.Worlds[worldID]
 	if !ok {
@@ -108,75 +163,24 @@ func (e *Engine) PurchaseBuyOn(worldID, buyOnID string) (float64, bool) {
 	if !ok {
 		return 0, false
 	}
-	if b.LevelRequirement() > e.State.Player.Level {
+	if b.LevelRequirement() > e.State.Player.Level || !e.buyOnAllowed(worldID, reg, b) {
 		return 0, false
 	}
 	count := ws.BuyOnCounts[buyOnID]
-	cost := upgrade.CostForNext(b, count)
+	cost := e.buyOnCost(worldID, b, count)
 	if ws.Coins < cost {
 		return 0, false
 	}
-	ws.Coins -= cost
+	ws.Spend(cost)
 	ws.BuyOnCounts[buyOnID] = count + 1
 	// Recompute CPS after the purchase.
-	ws.CPS = upgrade.CalculateWorldCPS(reg, ws.BuyOnCounts, ws.PurchasedUpgrades, ws.PrestigeMultiplier, 1.0)
-	return cost, true
-}
-
-// CanPrestige reports whether the player has met the prestige threshold for the
-// given world. Returns false for unknown worlds.
-func (e *Engine) CanPrestige(worldID string) bool {
-	ws, ok := e.State.Worlds[worldID]
-	if !ok {
-		return false
-	}
-	w, ok := e.WorldReg.Get(worldID)
-	if !ok {
-		return false
-	}
-	threshold := w.Config().PrestigeThreshold
-	switch threshold.Type {
-	case "coins_earned":
-		return ws.TotalCoinsEarned >= threshold.Value
-	case "buy_ons_owned":
-		total := 0
-		for _, count := range ws.BuyOnCounts {
-			total += count
-		}
-		return float64(total) >= threshold.Value
-	case "completion_percent":
-		return ws.CompletionPercent*100 >= threshold.Value
-	default:
-		return false
-	}
-}
-
-// PrestigeProgress returns (current, threshold) for the active prestige metric
-// in the given world. Used by the UI to render a progress bar.
-func (e *Engine) PrestigeProgress(worldID string) (current, threshold float64) {
-	ws, ok := e.State.Worlds[worldID]
-	if !ok {
-		return 0, 1
-	}
-	w, ok := e.WorldReg.Get(worldID)
-	if !ok {
-		return 0, 1
-	}
-	cfg := w.Config().PrestigeThreshold
-	switch cfg.Type {
-	case "coins_earned":
-		return ws.TotalCoinsEarned, cfg.Value
-	case "buy_ons_owned":
-		total := 0
-		for _, count := range ws.BuyOnCounts {
-			total += count
-		}
-		return float64(total), cfg.Value
-	case "completion_percent":
-		return ws.CompletionPercent * 100, cfg.Value
-	default:
-		return 0, cfg.Value
+	ws.CPS = upgrade.CalculateWorldCPS(reg, ws.BuyOnCounts, ws.PurchasedUpgrades, e.cpsMultiplier(worldID), 1.0)
+	e.advanceChallenges(challenge.KindBuy, worldID, 1)
+	e.gainXP(config.XPSourceBuyOns, 1)
+	if e.Recorder != nil {
+		e.Recorder.RecordPurchase(worldID, buyOnID)
 	}
+	return cost, true
 }
 
 // ExecutePrestige performs a world prestige: computes rewards, applies them to
@@ -188,36 +192,39 @@ func (e *Engine) ExecutePrestige(worldID string) (economy.PrestigeReward, bool)
 	}
 	ws := e.State.Worlds[worldID]
 
-	reward := economy.CalculatePrestigeReward(ws.TotalCoinsEarned, ws.PrestigeCount, ws.PrestigeMultiplier)
+	reward := e.PrestigeRewardPreview(worldID)
 
 	// Apply rewards to the player.
 	e.State.Player.GeneralCoins += reward.GeneralCoinsEarned
 	e.State.Player.LifetimeGeneralCoins += reward.GeneralCoinsEarned
-	player.AddXP(&e.State.Player, reward.XPGrant)
+	e.addXP(reward.XPGrant)
 
 	// Update prestige state.
 	ws.PrestigeCount++
 	ws.PrestigeMultiplier = reward.PrestigeMultiplier
+	ws.PrestigePoints += reward.PrestigePoints
 
 	// Reset world — coins, buy-ons, CPS. Keep: prestige count/multiplier,
-	// exchange rate, offline cap upgrade level, lifetime stats (TotalCoinsEarned,
-	// TotalClicks) and completion progress.
-	ws.Coins = 0
-	ws.BuyOnCounts = make(map[string]int)
-	ws.PurchasedUpgrades = make(map[string]bool)
-	ws.CPS = 0
+	// prestige points and tree, exchange rate, offline cap upgrade level,
+	// lifetime stats (TotalCoinsEarned, TotalClicks) and completion progress.
+	e.resetWorld(worldID)
 
+	e.advanceChallenges(challenge.KindPrestige, worldID, 1)
+	if e.Recorder != nil {
+		e.Recorder.RecordPrestige(worldID)
+	}
 	return reward, true
 }
 
 // CanExchangeBoost reports whether the player has a non-zero world coin balance
-// to sacrifice for an exchange boost.
+// to sacrifice for an exchange boost, and the world's boost cooldown is over.
+// Coins bought on the market do not count.
 func (e *Engine) CanExchangeBoost(worldID string) bool {
 	ws, ok := e.State.Worlds[worldID]
 	if !ok {
 		return false
 	}
-	return ws.Coins > 0
+	return ws.BoostableCoins() > 0 && ws.BoostCooldown <= 0
 }
 
 // ExchangeBoostPreview returns the projected result of an exchange boost
@@ -227,26 +234,40 @@ func (e *Engine) ExchangeBoostPreview(worldID string) economy.ExchangeBoostResul
 	if !ok {
 		return economy.ExchangeBoostResult{}
 	}
-	return economy.CalculateExchangeBoost(ws.Coins, ws.ExchangeRate)
+	return e.exchangeBoost(ws)
+}
+
+// exchangeBoost computes an exchange boost of ws, with the general coins
+// raised by the player's exchange perks.
+func (e *Engine) exchangeBoost(ws *world.WorldState) economy.ExchangeBoostResult {
+	result := economy.CalculateExchangeBoost(ws.BoostableCoins(), ws.ExchangeRate)
+	result.GeneralCoinsEarned *= 1 + e.perkEffects().ExchangeBonus
+	return result
 }
 
 // ExecuteExchangeBoost performs an exchange boost: sacrifices a portion of the
 // world coin balance and converts it to general coins at the current exchange
-// rate. The exchange rate is permanently improved. Returns (result, true) on
-// success or (zero, false) if the balance is zero.
+// rate. The exchange rate is permanently improved, and the next boost in the
+// world waits economy.ExchangeBoostCooldown seconds of play. Returns (result,
+// true) on success or (zero, false) if CanExchangeBoost does.
 func (e *Engine) ExecuteExchangeBoost(worldID string) (economy.ExchangeBoostResult, bool) {
 	if !e.CanExchangeBoost(worldID) {
 		return economy.ExchangeBoostResult{}, false
 	}
 	ws := e.State.Worlds[worldID]
 
-	result := economy.CalculateExchangeBoost(ws.Coins, ws.ExchangeRate)
+	result := e.exchangeBoost(ws)
 
 	ws.Coins -= result.WorldCoinsCost
 	ws.ExchangeRate = result.NewExchangeRate
+	ws.BoostCooldown = economy.ExchangeBoostCooldown
 
 	e.State.Player.GeneralCoins += result.GeneralCoinsEarned
 	e.State.Player.LifetimeGeneralCoins += result.GeneralCoinsEarned
+	e.gainXP(config.XPSourceExchangeBoosts, 1)
 
+	if e.Recorder != nil {
+		e.Recorder.RecordExchangeBoost(worldID)
+	}
 	return result, true
 }
@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/market"
+)
+
+// StartMarket seeds the exchange market for profile at now, unless it has
+// been seeded before: a market keeps its seed for good. Call it once per
+// session, before a Recorder is attached, like StartChallenges.
+func (e *Engine) StartMarket(profile string, now time.Time) {
+	if e.State.Market.Seed == "" {
+		e.State.Market.Seed = fmt.Sprintf("%s|%d", profile, now.UnixNano())
+	}
+}
+
+// fairPrice returns the price worldID's coin reverts to on the market: its
+// exchange rate, which exchange boosts raise.
+func (e *Engine) fairPrice(worldID string) float64 {
+	ws, ok := e.State.Worlds[worldID]
+	if !ok {
+		return 0
+	}
+	return ws.ExchangeRate
+}
+
+// MarketPrice returns the market price of one worldID coin in general
+// coins, midway between what buying and selling it costs and pays.
+func (e *Engine) MarketPrice(worldID string) float64 {
+	return e.State.Market.Quotes[worldID].Price(e.fairPrice(worldID))
+}
+
+// SellPreview returns the general coins selling coins worldID coins on the
+// market pays now.
+func (e *Engine) SellPreview(worldID string, coins float64) float64 {
+	return coins * market.Bid(market.Params, e.MarketPrice(worldID))
+}
+
+// BuyPreview returns the worldID coins gc general coins buy on the market
+// now.
+func (e *Engine) BuyPreview(worldID string, gc float64) float64 {
+	ask := market.Ask(market.Params, e.MarketPrice(worldID))
+	if ask <= 0 {
+		return 0
+	}
+	return gc / ask
+}
+
+// CanSellWorldCoins reports whether coins worldID coins can be sold: a
+// positive amount no larger than the balance.
+func (e *Engine) CanSellWorldCoins(worldID string, coins float64) bool {
+	ws, ok := e.State.Worlds[worldID]
+	return ok && coins > 0 && coins <= ws.Coins && e.MarketPrice(worldID) > 0
+}
+
+// CanBuyWorldCoins reports whether gc general coins can be spent on
+// worldID coins: a positive amount the player has, in a world without a
+// challenge run in progress, whose coins must be earned.
+func (e *Engine) CanBuyWorldCoins(worldID string, gc float64) bool {
+	if _, ok := e.State.Worlds[worldID]; !ok {
+		return false
+	}
+	if _, _, running := e.State.Runs.Running(worldID); running {
+		return false
+	}
+	return gc > 0 && gc <= e.State.Player.GeneralCoins && e.MarketPrice(worldID) > 0
+}
+
+// SellWorldCoins sells coins worldID coins on the market for general coins
+// and returns the general coins paid, or false if CanSellWorldCoins does.
+func (e *Engine) SellWorldCoins(worldID string, coins float64) (float64, bool) {
+	if !e.CanSellWorldCoins(worldID, coins) {
+		return 0, false
+	}
+	gc := e.SellPreview(worldID, coins)
+	e.State.Worlds[worldID].Spend(coins)
+	e.State.Player.GeneralCoins += gc
+	e.State.Player.LifetimeGeneralCoins += gc
+	if e.Recorder != nil {
+		e.Recorder.RecordMarketSell(worldID, coins)
+	}
+	return gc, true
+}
+
+// BuyWorldCoins spends gc general coins on worldID coins on the market and
+// returns the coins bought, or false if CanBuyWorldCoins does. Bought coins
+// add to the balance only: they are not coins earned, so they count towards
+// no prestige, challenge or XP, and they are kept out of exchange boosts,
+// which would otherwise sell them back above the market price.
+func (e *Engine) BuyWorldCoins(worldID string, gc float64) (float64, bool) {
+	if !e.CanBuyWorldCoins(worldID, gc) {
+		return 0, false
+	}
+	coins := e.BuyPreview(worldID, gc)
+	e.State.Player.GeneralCoins -= gc
+	ws := e.State.Worlds[worldID]
+	ws.Coins += coins
+	ws.BoughtCoins += coins
+	if e.Recorder != nil {
+		e.Recorder.RecordMarketBuy(worldID, gc)
+	}
+	return coins, true
+}
+
+// advanceMarket moves the market prices on by dt seconds of play and
+// returns an EventMarketShock for every shock that hit.
+func (e *Engine) advanceMarket(dt float64) []EngineEvent {
+	hits := e.State.Market.Advance(market.Params, dt, sortedKeys(e.State.Worlds), e.fairPrice)
+	var events []EngineEvent
+	for _, h := range hits {
+		events = append(events, EngineEvent{Type: EventMarketShock, WorldID: h.WorldID, Shock: h.Shock.Name, Impact: h.Shock.Impact})
+	}
+	return events
+}
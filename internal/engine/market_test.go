@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/market"
+)
+
+func TestSellAndBuyWorldCoins_PayTheSpread(t *testing.T) {
+	eng := newTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	ws.Coins = 10000
+	eng.State.Market.Quotes = map[string]market.Quote{"terra": {Gap: math.Log(2)}}
+	price := 2 * ws.ExchangeRate
+	assert.InDelta(t, price, eng.MarketPrice("terra"), 1e-12)
+
+	gc, ok := eng.SellWorldCoins("terra", 4000)
+	require.True(t, ok)
+	assert.InDelta(t, 4000*market.Bid(market.Params, price), gc, 1e-9)
+	assert.InDelta(t, 6000, ws.Coins, 1e-9)
+	assert.InDelta(t, gc, eng.State.Player.GeneralCoins, 1e-9)
+	assert.InDelta(t, gc, eng.State.Player.LifetimeGeneralCoins, 1e-9)
+	_, ok = eng.SellWorldCoins("terra", 6001)
+	assert.False(t, ok, "more than the balance")
+
+	coins, ok := eng.BuyWorldCoins("terra", gc)
+	require.True(t, ok)
+	assert.Less(t, coins, 4000.0, "a round trip loses the spread")
+	assert.InDelta(t, gc/market.Ask(market.Params, price), coins, 1e-9)
+	assert.Zero(t, eng.State.Player.GeneralCoins)
+	assert.Zero(t, ws.TotalCoinsEarned, "bought coins are not earned")
+	_, ok = eng.BuyWorldCoins("terra", 1)
+	assert.False(t, ok, "no general coins left")
+}
+
+func TestBuyWorldCoins_RefusedDuringRun(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Player.GeneralCoins = 10
+	require.True(t, eng.StartRun("terra", "no_clicks"))
+	assert.False(t, eng.CanBuyWorldCoins("terra", 5))
+	assert.True(t, eng.CanBuyWorldCoins("aqua", 5))
+}
+
+func TestTick_MovesMarketAndReportsShocks(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.StartMarket("alice", time.Unix(0, 0))
+	seed := eng.State.Market.Seed
+	eng.StartMarket("alice", time.Unix(1, 0))
+	assert.Equal(t, seed, eng.State.Market.Seed, "a market keeps its seed")
+
+	shocks := 0
+	for range 2000 {
+		shocks += countEvents(eng.Tick(market.Params.StepSeconds), EventMarketShock)
+	}
+	assert.Equal(t, int64(2000), eng.State.Market.Step)
+	assert.Len(t, eng.State.Market.Quotes["terra"].History, market.Params.History)
+	assert.Positive(t, shocks, "2000 steps of two coins see a shock")
+	assert.NotEqual(t, eng.State.Worlds["terra"].ExchangeRate, eng.MarketPrice("terra"))
+}
+
+func TestBuyThenExchangeBoost_MakesNoGeneralCoins(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Player.Perks = map[string]int{"broker": 4}
+	eng.State.Player.GeneralCoins = 1000
+	// a crash makes the coins cheap to buy.
+	eng.State.Market.Quotes = map[string]market.Quote{"terra": {Gap: math.Log(0.5)}}
+	ws := eng.State.Worlds["terra"]
+
+	bought, ok := eng.BuyWorldCoins("terra", 1000)
+	require.True(t, ok)
+	assert.False(t, eng.CanExchangeBoost("terra"), "bought coins cannot be boosted")
+
+	// with coins of its own, the world boosts those alone, once a cooldown.
+	ws.Coins += 100
+	res, ok := eng.ExecuteExchangeBoost("terra")
+	require.True(t, ok)
+	assert.InDelta(t, 20, res.WorldCoinsCost, 1e-9)
+	_, ok = eng.ExecuteExchangeBoost("terra")
+	assert.False(t, ok, "the next boost waits for the cooldown")
+
+	spent := 1000.0
+	sold, ok := eng.SellWorldCoins("terra", bought)
+	require.True(t, ok)
+	assert.Less(t, sold, spent, "a buy→boost cycle returns less than it spent")
+	assert.InDelta(t, 80, ws.BoostableCoins(), 1e-9)
+}
+
+func TestExchangeBoost_Cooldown(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	eng.State.Worlds["terra"].Coins = 1000
+	_, ok := eng.ExecuteExchangeBoost("terra")
+	require.True(t, ok)
+	assert.False(t, eng.CanExchangeBoost("terra"))
+	eng.Tick(economy.ExchangeBoostCooldown)
+	assert.True(t, eng.CanExchangeBoost("terra"))
+}
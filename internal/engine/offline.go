@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/clicker-org/clicker/internal/offline"
+)
+
+// ApplyOffline credits offline income to the engine state and immediately
+// evaluates achievements, milestones and level-ups caused by it, instead of
+// waiting for the first debounced check in Tick. Must be called after Earned
+// has been restored from the save.
+//
+// The offline duration is measured with e.Clock and validated against the
+// clock high-water mark in State.Clock using the given policy.
+func (e *Engine) ApplyOffline(lastScreen, lastWorldID string, savedAt time.Time, policy offline.ClockPolicy) offline.Result {
+	// Record milestones already met before the offline session so that only
+	// those reached through offline earnings are reported.
+	e.updateMilestones(false)
+
+	prevLevel := e.State.Player.Level
+	sess := offline.Session{
+		LastScreen:  lastScreen,
+		LastWorldID: lastWorldID,
+		SavedAt:     savedAt,
+		LastWall:    e.State.Clock.LastWall,
+		Policy:      policy,
+	}
+	if e.Clock != nil {
+		sess.Now = e.Clock.Now
+	}
+	result := offline.ApplySession(sess, &e.State, e.WorldReg)
+	result.PrevLevel = prevLevel
+	result.NewLevel = prevLevel
+
+	for _, ev := range e.CheckUnlocks() {
+		switch ev.Type {
+		case EventAchievementUnlocked:
+			result.Achievements = append(result.Achievements, ev.AchievementID)
+		case EventLevelUp:
+			result.NewLevel = ev.NewLevel
+		case EventMilestoneReached:
+			for i := range result.Worlds {
+				if result.Worlds[i].WorldID == ev.WorldID {
+					result.Worlds[i].Milestones = append(result.Worlds[i].Milestones, ev.MilestoneID)
+				}
+			}
+		}
+	}
+	// milestones also grant XP, whose level-ups the next Tick reports.
+	result.NewLevel = max(result.NewLevel, e.State.Player.Level)
+	return result
+}
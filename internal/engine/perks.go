@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"github.com/clicker-org/clicker/internal/perk"
+)
+
+// PerkPoints returns the perk points the player has left to spend: one per
+// level above the first, less those spent on perk ranks.
+func (e *Engine) PerkPoints() int {
+	return perk.Available(e.State.Player.Level, e.State.Player.Perks)
+}
+
+// CanBuyPerk reports whether a rank of the perk id can be bought: its
+// prerequisites have a rank, it is below its max rank and the player has
+// the points.
+func (e *Engine) CanBuyPerk(id string) bool {
+	return perk.CanBuy(perk.Tree, e.State.Player.Perks, e.PerkPoints(), id)
+}
+
+// BuyPerk buys a rank of the perk id with perk points. Its effect applies
+// at once in every world. It returns false if CanBuyPerk does.
+func (e *Engine) BuyPerk(id string) bool {
+	if !e.CanBuyPerk(id) {
+		return false
+	}
+	if e.State.Player.Perks == nil {
+		e.State.Player.Perks = make(map[string]int)
+	}
+	e.State.Player.Perks[id]++
+	e.refreshAllCPS()
+	if e.Recorder != nil {
+		e.Recorder.RecordPerk(id)
+	}
+	return true
+}
+
+// PerkRespecCost returns the general coins a perk respec costs now.
+func (e *Engine) PerkRespecCost() float64 {
+	return perk.RespecCost(perk.Tree, e.State.Player.Perks)
+}
+
+// CanRespecPerks reports whether the player has perk points to refund and
+// the general coins to pay for it.
+func (e *Engine) CanRespecPerks() bool {
+	p := e.State.Player
+	return perk.Spent(perk.Tree, p.Perks) > 0 && p.GeneralCoins >= e.PerkRespecCost()
+}
+
+// RespecPerks pays general coins to clear every perk rank and refund the
+// points spent on them. It returns false if CanRespecPerks does.
+func (e *Engine) RespecPerks() bool {
+	if !e.CanRespecPerks() {
+		return false
+	}
+	e.State.Player.GeneralCoins -= e.PerkRespecCost()
+	e.State.Player.Perks = make(map[string]int)
+	e.refreshAllCPS()
+	if e.Recorder != nil {
+		e.Recorder.RecordPerkRespec()
+	}
+	return true
+}
+
+// perkEffects returns the effects of the player's perk ranks.
+func (e *Engine) perkEffects() perk.Effects {
+	return perk.Compute(perk.Tree, e.State.Player.Perks)
+}
+
+// refreshAllCPS recomputes every world's CPS, after a change that affects
+// them all.
+func (e *Engine) refreshAllCPS() {
+	for _, id := range sortedKeys(e.State.Worlds) {
+		e.refreshCPS(id)
+	}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/economy"
+)
+
+func TestBuyPerk_SpendsLevelPointsAndAppliesEverywhere(t *testing.T) {
+	eng := newTestEngine(t)
+	assert.Zero(t, eng.PerkPoints(), "level 1 has no points")
+	assert.False(t, eng.BuyPerk("strong_arm"))
+
+	eng.State.Player.Level = 4
+	ws := eng.State.Worlds["terra"]
+	ws.BuyOnCounts["auto_miner"] = 10
+	eng.refreshCPS("terra")
+	cps := ws.CPS
+	click := eng.ClickPower("aqua")
+
+	require.True(t, eng.BuyPerk("strong_arm"))
+	require.True(t, eng.BuyPerk("industrialist"))
+	assert.Equal(t, 1, eng.PerkPoints())
+	assert.InDelta(t, click*1.1, eng.ClickPower("aqua"), 1e-9)
+	assert.InDelta(t, cps*1.05, ws.CPS, 1e-9, "CPS is refreshed at once")
+	assert.False(t, eng.BuyPerk("broker"), "costs two points")
+
+	eng.State.Player.Level = 5
+	require.True(t, eng.BuyPerk("broker"))
+	ws.Coins = 1000
+	plain := economy.CalculateExchangeBoost(ws.Coins, ws.ExchangeRate).GeneralCoinsEarned
+	assert.InDelta(t, plain*1.05, eng.ExchangeBoostPreview("terra").GeneralCoinsEarned, 1e-9)
+
+	// survives a prestige
+	ws.TotalCoinsEarned = 1e6
+	_, ok := eng.ExecutePrestige("terra")
+	require.True(t, ok)
+	assert.Equal(t, 1, eng.State.Player.Perks["broker"])
+}
+
+func TestAddXP_AppliesXPPerks(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Player.Perks = map[string]int{"scholar": 5}
+	eng.addXP(50)
+	assert.Equal(t, 75, eng.State.Player.XP)
+}
+
+func TestRespecPerks(t *testing.T) {
+	eng := newTestEngine(t)
+	assert.False(t, eng.RespecPerks(), "nothing to refund")
+
+	eng.State.Player.Level = 3
+	require.True(t, eng.BuyPerk("scholar"))
+	require.True(t, eng.BuyPerk("scholar"))
+	assert.Equal(t, 100.0, eng.PerkRespecCost())
+	assert.False(t, eng.RespecPerks(), "no general coins")
+
+	eng.State.Player.GeneralCoins = 150
+	require.True(t, eng.RespecPerks())
+	assert.Equal(t, 50.0, eng.State.Player.GeneralCoins)
+	assert.Empty(t, eng.State.Player.Perks)
+	assert.Equal(t, 2, eng.PerkPoints())
+}
@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"slices"
+
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/economy"
+)
+
+// CanPrestige reports whether the player has met the prestige threshold for the
+// given world. Returns false for unknown worlds and while a challenge run is
+// in progress there.
+func (e *Engine) CanPrestige(worldID string) bool {
+	if _, running := e.State.Runs.Active[worldID]; running {
+		return false
+	}
+	return e.PrestigeProgress(worldID).Met()
+}
+
+// PrestigeCondition is the progress towards one condition of a world's
+// prestige threshold.
+type PrestigeCondition struct {
+	// Metric is a config.PrestigeMetric* constant.
+	Metric  string
+	Current float64
+	Target  float64
+}
+
+// Met reports whether the condition is met.
+func (c PrestigeCondition) Met() bool { return c.Current >= c.Target }
+
+// Fraction returns how far the condition is met, from 0 to 1.
+func (c PrestigeCondition) Fraction() float64 {
+	if c.Target <= 0 {
+		return 1
+	}
+	return min(max(c.Current/c.Target, 0), 1)
+}
+
+// ThresholdProgress is the progress towards a world's prestige threshold.
+type ThresholdProgress struct {
+	Conditions []PrestigeCondition
+	// AnyOf is set when meeting one of the conditions is enough; otherwise
+	// all of them must be met.
+	AnyOf bool
+}
+
+// Met reports whether the threshold is met. A threshold without conditions
+// is never met.
+func (p ThresholdProgress) Met() bool {
+	if len(p.Conditions) == 0 {
+		return false
+	}
+	if p.AnyOf {
+		return slices.ContainsFunc(p.Conditions, PrestigeCondition.Met)
+	}
+	return !slices.ContainsFunc(p.Conditions, func(c PrestigeCondition) bool { return !c.Met() })
+}
+
+// Fraction returns how far the threshold is met, from 0 to 1: the progress
+// of the furthest condition for AnyOf, else of the one furthest behind.
+func (p ThresholdProgress) Fraction() float64 {
+	if len(p.Conditions) == 0 {
+		return 0
+	}
+	f := p.Conditions[0].Fraction()
+	for _, c := range p.Conditions[1:] {
+		if p.AnyOf {
+			f = max(f, c.Fraction())
+		} else {
+			f = min(f, c.Fraction())
+		}
+	}
+	return f
+}
+
+// PrestigeProgress returns the progress towards each condition of the given
+// world's prestige threshold. Used by the UI to render progress bars.
+func (e *Engine) PrestigeProgress(worldID string) ThresholdProgress {
+	ws, ok := e.State.Worlds[worldID]
+	if !ok {
+		return ThresholdProgress{}
+	}
+	w, ok := e.WorldReg.Get(worldID)
+	if !ok {
+		return ThresholdProgress{}
+	}
+	conds, anyOf := w.Config().PrestigeThreshold.Conditions()
+	progress := ThresholdProgress{AnyOf: anyOf}
+	for _, c := range conds {
+		current := 0.0
+		switch c.Type {
+		case config.PrestigeMetricCoinsEarned:
+			current = ws.TotalCoinsEarned
+		case config.PrestigeMetricBuyOnsOwned:
+			total := 0
+			for _, count := range ws.BuyOnCounts {
+				total += count
+			}
+			current = float64(total)
+		case config.PrestigeMetricCompletionPercent:
+			current = ws.CompletionPercent * 100
+		}
+		progress.Conditions = append(progress.Conditions, PrestigeCondition{Metric: c.Type, Current: current, Target: c.Value})
+	}
+	return progress
+}
+
+// prestigeFormula returns the prestige reward formula of worldID: its own
+// if its config has one, else economy.DefaultPrestigeFormula.
+func (e *Engine) prestigeFormula(worldID string) economy.PrestigeFormula {
+	w, ok := e.WorldReg.Get(worldID)
+	if !ok || w.Config().PrestigeReward == nil {
+		return economy.DefaultPrestigeFormula
+	}
+	r := w.Config().PrestigeReward
+	return economy.PrestigeFormula{
+		GCScale:         r.GCScale,
+		GCExponent:      r.GCExponent,
+		MultiplierGain:  r.MultiplierGain,
+		MultiplierDecay: r.MultiplierDecay,
+		XPPerPrestige:   r.XPPerPrestige,
+	}
+}
+
+// PrestigeRewardPreview returns what prestiging the given world now would
+// earn, whether or not its threshold is met.
+func (e *Engine) PrestigeRewardPreview(worldID string) economy.PrestigeReward {
+	ws, ok := e.State.Worlds[worldID]
+	if !ok {
+		return economy.PrestigeReward{}
+	}
+	return e.prestigeFormula(worldID).Reward(ws.TotalCoinsEarned, ws.PrestigeCount, ws.PrestigeMultiplier)
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/world"
+)
+
+// configWorld is a shipped world with its config edited for a test.
+type configWorld struct {
+	world.World
+	cfg config.WorldConfig
+}
+
+func (w configWorld) Config() config.WorldConfig { return w.cfg }
+
+// newTestEngineWithAquaConfig returns a test engine whose Aqua config is
+// edited by edit, so the shipped config need not exercise every form.
+func newTestEngineWithAquaConfig(t *testing.T, edit func(*config.WorldConfig)) *Engine {
+	t.Helper()
+	reg := world.NewWorldRegistry()
+	gs := gamestate.NewGameState()
+	for _, w := range world.DefaultRegistry.List() {
+		if w.ID() == "aqua" {
+			cfg := w.Config()
+			edit(&cfg)
+			w = configWorld{World: w, cfg: cfg}
+		}
+		reg.Register(w)
+		gs.Worlds[w.ID()] = world.NewWorldState(w.ID(), w.BaseExchangeRate())
+	}
+	return New(gs, reg, achievement.NewAchievementRegistry())
+}
+
+func TestPrestigeProgress_SingleCondition(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Worlds["terra"].TotalCoinsEarned = 250_000
+
+	p := eng.PrestigeProgress("terra")
+	require.Len(t, p.Conditions, 1)
+	assert.Equal(t, config.PrestigeMetricCoinsEarned, p.Conditions[0].Metric)
+	assert.InDelta(t, 0.25, p.Fraction(), 1e-9)
+	assert.False(t, eng.CanPrestige("terra"))
+
+	eng.State.Worlds["terra"].TotalCoinsEarned = 1e6
+	assert.True(t, eng.CanPrestige("terra"))
+	assert.Empty(t, eng.PrestigeProgress("nowhere").Conditions)
+}
+
+func TestPrestigeProgress_AllOf(t *testing.T) {
+	eng := newTestEngineWithAquaConfig(t, func(cfg *config.WorldConfig) {
+		cfg.PrestigeThreshold = config.PrestigeThresholdConfig{AllOf: []config.PrestigeConditionConfig{
+			{Type: config.PrestigeMetricCoinsEarned, Value: 1e6},
+			{Type: config.PrestigeMetricBuyOnsOwned, Value: 50},
+		}}
+	})
+	ws := eng.State.Worlds["aqua"]
+	ws.TotalCoinsEarned = 2e6
+	ws.BuyOnCounts["bubble_collector"] = 10
+
+	p := eng.PrestigeProgress("aqua")
+	require.Len(t, p.Conditions, 2)
+	assert.False(t, p.AnyOf)
+	assert.True(t, p.Conditions[0].Met())
+	assert.False(t, p.Conditions[1].Met())
+	assert.InDelta(t, 0.2, p.Fraction(), 1e-9, "held back by the buy-ons")
+	assert.False(t, eng.CanPrestige("aqua"))
+
+	ws.BuyOnCounts["bubble_collector"] = 50
+	assert.True(t, eng.CanPrestige("aqua"))
+}
+
+func TestThresholdProgress_AnyOf(t *testing.T) {
+	p := ThresholdProgress{AnyOf: true, Conditions: []PrestigeCondition{
+		{Metric: config.PrestigeMetricCoinsEarned, Current: 10, Target: 100},
+		{Metric: config.PrestigeMetricCompletionPercent, Current: 30, Target: 50},
+	}}
+	assert.False(t, p.Met())
+	assert.InDelta(t, 0.6, p.Fraction(), 1e-9)
+	p.Conditions[1].Current = 50
+	assert.True(t, p.Met())
+	assert.False(t, ThresholdProgress{}.Met(), "no conditions, no prestige")
+}
+
+func TestPrestigeRewardPreview_UsesWorldFormula(t *testing.T) {
+	eng := newTestEngineWithAquaConfig(t, func(cfg *config.WorldConfig) {
+		cfg.PrestigeReward = &config.PrestigeRewardConfig{
+			GCScale: 0.12, GCExponent: 0.5, MultiplierGain: 0.6, MultiplierDecay: 0.6, XPPerPrestige: 600,
+		}
+	})
+	for _, id := range []string{"terra", "aqua"} {
+		eng.State.Worlds[id].TotalCoinsEarned = 1e6
+	}
+
+	terra := eng.PrestigeRewardPreview("terra")
+	assert.Equal(t, economy.DefaultPrestigeFormula.Reward(1e6, 0, 1), terra)
+
+	aqua := eng.PrestigeRewardPreview("aqua")
+	assert.Greater(t, aqua.GeneralCoinsEarned, terra.GeneralCoinsEarned)
+	assert.Greater(t, aqua.XPGrant, terra.XPGrant)
+
+	reward, ok := eng.ExecutePrestige("aqua")
+	require.True(t, ok)
+	assert.Equal(t, aqua, reward)
+}
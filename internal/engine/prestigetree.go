@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/prestigetree"
+	"github.com/clicker-org/clicker/internal/upgrade"
+)
+
+// PrestigeTree returns the nodes of worldID's prestige tree, or nil for an
+// unknown world.
+func (e *Engine) PrestigeTree(worldID string) []config.PrestigeNodeConfig {
+	w, ok := e.WorldReg.Get(worldID)
+	if !ok {
+		return nil
+	}
+	return w.Config().PrestigeTree
+}
+
+// CanBuyPrestigeNode reports whether the node id of worldID's prestige tree
+// can be bought: its prerequisites are owned and the world has the points.
+func (e *Engine) CanBuyPrestigeNode(worldID, id string) bool {
+	ws, ok := e.State.Worlds[worldID]
+	if !ok {
+		return false
+	}
+	return prestigetree.CanBuy(e.PrestigeTree(worldID), ws.PrestigeTree, ws.PrestigePoints, id)
+}
+
+// BuyPrestigeNode buys the node id of worldID's prestige tree with the
+// world's prestige points. Its effect applies at once: a node that starts
+// resets with buy-ons also tops the world up to that many. It returns false
+// if CanBuyPrestigeNode does.
+func (e *Engine) BuyPrestigeNode(worldID, id string) bool {
+	if !e.CanBuyPrestigeNode(worldID, id) {
+		return false
+	}
+	ws := e.State.Worlds[worldID]
+	n, _ := prestigetree.Find(e.PrestigeTree(worldID), id)
+	ws.PrestigePoints -= n.Cost
+	if ws.PrestigeTree == nil {
+		ws.PrestigeTree = make(map[string]bool)
+	}
+	ws.PrestigeTree[id] = true
+	e.grantStartBuyOns(worldID)
+	e.refreshCPS(worldID)
+	if e.Recorder != nil {
+		e.Recorder.RecordPrestigeNode(worldID, id)
+	}
+	return true
+}
+
+// treeEffects returns the effects of the nodes owned in worldID's prestige
+// tree.
+func (e *Engine) treeEffects(worldID string) prestigetree.Effects {
+	var owned map[string]bool
+	if ws, ok := e.State.Worlds[worldID]; ok {
+		owned = ws.PrestigeTree
+	}
+	return prestigetree.Compute(e.PrestigeTree(worldID), owned)
+}
+
+// resetWorld clears worldID's coins, buy-ons and upgrades, as prestiges and
+// challenge runs do, then gives it the buy-ons its prestige tree starts
+// resets with.
+func (e *Engine) resetWorld(worldID string) {
+	ws := e.State.Worlds[worldID]
+	ws.Coins = 0
+	ws.BoughtCoins = 0
+	ws.BuyOnCounts = make(map[string]int)
+	ws.PurchasedUpgrades = make(map[string]bool)
+	ws.SinceResetSeconds = 0
+	e.grantStartBuyOns(worldID)
+	e.refreshCPS(worldID)
+}
+
+// grantStartBuyOns raises worldID's buy-on counts to those its prestige
+// tree starts resets with, skipping buy-ons the challenge run in progress
+// bars.
+func (e *Engine) grantStartBuyOns(worldID string) {
+	ws, ok := e.State.Worlds[worldID]
+	reg, hasReg := e.UpgradeReg[worldID]
+	if !ok || !hasReg {
+		return
+	}
+	for id, n := range e.treeEffects(worldID).StartBuyOns {
+		b, ok := reg.GetBuyOn(id)
+		if !ok || !e.buyOnAllowed(worldID, reg, b) {
+			continue
+		}
+		ws.BuyOnCounts[id] = max(ws.BuyOnCounts[id], n)
+	}
+}
+
+// buyOnCost returns what the next unit of b costs in worldID with count
+// owned: its prestige tree may slow the cost growth, and the challenge run
+// in progress may speed it up.
+func (e *Engine) buyOnCost(worldID string, b upgrade.BuyOn, count int) float64 {
+	scaling := e.treeEffects(worldID).Scaling(b.CostScaling())
+	scaling = e.State.Runs.Restrictions(worldID).Scaling(scaling)
+	return b.BaseCost() * math.Pow(scaling, float64(count))
+}
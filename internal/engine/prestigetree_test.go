@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrestige_GrantsPrestigePoints(t *testing.T) {
+	eng := newTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	ws.TotalCoinsEarned = 9e6
+
+	reward, ok := eng.ExecutePrestige("terra")
+	require.True(t, ok)
+	assert.Equal(t, 3, reward.PrestigePoints)
+	assert.Equal(t, 3, ws.PrestigePoints)
+}
+
+func TestBuyPrestigeNode_AppliesEffectsAcrossPrestiges(t *testing.T) {
+	eng := newTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	ws.PrestigePoints = 5
+
+	assert.False(t, eng.BuyPrestigeNode("terra", "drill_crew"), "head_start comes first")
+	require.True(t, eng.BuyPrestigeNode("terra", "head_start"))
+	assert.False(t, eng.BuyPrestigeNode("terra", "head_start"), "nodes are bought once")
+	assert.Equal(t, 4, ws.PrestigePoints)
+	assert.Equal(t, 10, ws.BuyOnCounts["auto_miner"], "the world is topped up at once")
+	assert.Positive(t, ws.CPS)
+
+	click := eng.ClickPower("terra")
+	require.True(t, eng.BuyPrestigeNode("terra", "calloused_hands"))
+	assert.InDelta(t, click*1.5, eng.ClickPower("terra"), 1e-9)
+
+	hints := eng.ShopHints("terra")
+	require.True(t, eng.BuyPrestigeNode("terra", "bulk_orders"))
+	assert.Less(t, eng.ShopHints("terra")[0].Cost, hints[0].Cost, "costs grow slower")
+	assert.Zero(t, ws.PrestigePoints)
+
+	ws.TotalCoinsEarned = 1e6
+	_, ok := eng.ExecutePrestige("terra")
+	require.True(t, ok)
+	assert.True(t, ws.PrestigeTree["head_start"], "the tree survives prestiges")
+	assert.Equal(t, 10, ws.BuyOnCounts["auto_miner"])
+	assert.Equal(t, 1, ws.PrestigePoints)
+}
+
+func TestStartRun_KeepsStartBuyOns(t *testing.T) {
+	eng := newTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	ws.PrestigeTree = map[string]bool{"head_start": true, "drill_crew": true}
+
+	require.True(t, eng.StartRun("terra", "two_buy_ons"))
+	assert.Equal(t, 10, ws.BuyOnCounts["auto_miner"])
+	assert.Equal(t, 5, ws.BuyOnCounts["drill_bot"])
+	assert.Equal(t, 1, eng.State.Runs.Record("terra", "two_buy_ons").Attempts)
+}
@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+)
+
+// Recorder is told about every state-changing action the engine performs,
+// so that a session can be re-run from a snapshot of its starting state (see
+// package replay) or passed on to the engine this one mirrors (see package
+// daemon). Only actions that succeed are reported; anything the
+// engine refuses leaves no trace in the state and needs no replaying.
+type Recorder interface {
+	RecordTick(dt float64)
+	RecordClick(worldID string)
+	RecordPurchase(worldID, buyOnID string)
+	RecordPrestige(worldID string)
+	RecordExchangeBoost(worldID string)
+	RecordAutoBuyerUnlock()
+	RecordAutoBuyer(s autobuy.Settings)
+	RecordChallengeDay(day string)
+	RecordChallengeReroll(id string)
+	RecordLoginClaim(now time.Time)
+	RecordRunStart(worldID, runID string)
+	RecordRunAbandon(worldID string)
+	RecordPrestigeNode(worldID, nodeID string)
+	RecordPerk(perkID string)
+	RecordPerkRespec()
+	RecordMarketSell(worldID string, coins float64)
+	RecordMarketBuy(worldID string, gc float64)
+	// End is called when the recording can no longer be continued, with the
+	// engine in the state the recorded actions lead to. Restore calls it
+	// before replacing the state wholesale.
+	End(e *Engine)
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/level"
+	"github.com/clicker-org/clicker/internal/upgrade"
+)
+
+// Repair brings state that is derived from the world configs back in line
+// with them, e.g. after a balance change or a hand-edited save: buy-ons and
+// upgrades the configs no longer define are dropped, CPS is recomputed, and
+// the level is recomputed from XP, paying the rewards of any levels it
+// rises through. It returns one line per change, in world ID order.
+func (e *Engine) Repair() []string {
+	var fixes []string
+	ids := make([]string, 0, len(e.State.Worlds))
+	for id := range e.State.Worlds {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		ws := e.State.Worlds[id]
+		reg, ok := e.UpgradeReg[id]
+		if !ok {
+			continue
+		}
+		for _, buyOnID := range sortedKeys(ws.BuyOnCounts) {
+			count := ws.BuyOnCounts[buyOnID]
+			if _, known := reg.GetBuyOn(buyOnID); !known {
+				delete(ws.BuyOnCounts, buyOnID)
+				fixes = append(fixes, fmt.Sprintf("%s: dropped unknown buy-on %q (%d owned)", id, buyOnID, count))
+			} else if count < 0 {
+				delete(ws.BuyOnCounts, buyOnID)
+				fixes = append(fixes, fmt.Sprintf("%s: dropped negative count %d of %q", id, count, buyOnID))
+			}
+		}
+		for _, upgradeID := range sortedKeys(ws.PurchasedUpgrades) {
+			if _, known := reg.GetUpgrade(upgradeID); !known {
+				delete(ws.PurchasedUpgrades, upgradeID)
+				fixes = append(fixes, fmt.Sprintf("%s: dropped unknown upgrade %q", id, upgradeID))
+			}
+		}
+		cps := upgrade.CalculateWorldCPS(reg, ws.BuyOnCounts, ws.PurchasedUpgrades, e.cpsMultiplier(id), 1.0)
+		// float noise is fixed silently; only visible changes are reported.
+		if economy.FormatCPS(cps) != economy.FormatCPS(ws.CPS) {
+			fixes = append(fixes, fmt.Sprintf("%s: CPS %s → %s", id, economy.FormatCPS(ws.CPS), economy.FormatCPS(cps)))
+		}
+		ws.CPS = cps
+	}
+
+	p := &e.State.Player
+	if lvl := level.Default.LevelFor(p.XP); lvl != p.Level {
+		fixes = append(fixes, fmt.Sprintf("level %d → %d (from %d XP)", p.Level, lvl, p.XP))
+		e.payLevelRewards(p.Level, lvl)
+		p.Level = lvl
+	}
+	return fixes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/level"
+)
+
+func TestRepair_DropsUnknownIDsAndRecomputes(t *testing.T) {
+	eng := newTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	ws.BuyOnCounts["auto_miner"] = 2
+	ws.BuyOnCounts["retired_bot"] = 5
+	ws.PurchasedUpgrades["retired_upgrade"] = true
+	ws.CPS = 999
+	eng.State.Player.Level = 9
+	eng.State.Player.XP = 150
+
+	fixes := eng.Repair()
+
+	assert.Equal(t, []string{
+		`terra: dropped unknown buy-on "retired_bot" (5 owned)`,
+		`terra: dropped unknown upgrade "retired_upgrade"`,
+		"terra: CPS 999.00 → 0.20",
+		"level 9 → 2 (from 150 XP)",
+	}, fixes)
+	assert.Equal(t, map[string]int{"auto_miner": 2}, ws.BuyOnCounts)
+	assert.Empty(t, eng.Repair(), "repairing twice changes nothing")
+}
+
+func TestRepair_RaisedLevelPaysItsRewards(t *testing.T) {
+	eng := newTestEngine(t)
+	p := &eng.State.Player
+	p.XP = level.Default.XPFor(10)
+	want := 0.0
+	for _, r := range level.Between(level.Rewards, 1, 10) {
+		want += r.GeneralCoins
+	}
+	require.Positive(t, want, "levels 2-10 pay general coins")
+
+	eng.Repair()
+	assert.Equal(t, 10, p.Level)
+	assert.Equal(t, want, p.GeneralCoins)
+	assert.Equal(t, want, p.LifetimeGeneralCoins)
+
+	p.Level = 12
+	eng.Repair()
+	assert.Equal(t, 10, p.Level)
+	assert.Equal(t, want, p.GeneralCoins, "lowering the level takes nothing back")
+}
@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"slices"
+
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/runs"
+	"github.com/clicker-org/clicker/internal/upgrade"
+)
+
+// CanStartRun reports whether the challenge run id can be started in
+// worldID: both exist and no run is in progress there.
+func (e *Engine) CanStartRun(worldID, id string) bool {
+	if _, ok := e.State.Worlds[worldID]; !ok {
+		return false
+	}
+	if _, ok := runs.Get(id); !ok {
+		return false
+	}
+	_, running := e.State.Runs.Active[worldID]
+	return !running
+}
+
+// StartRun starts the challenge run id in worldID. The world is reset as by
+// a prestige, without its rewards: coins, buy-ons and upgrades are lost,
+// prestige progress and lifetime stats are kept. It returns false if
+// CanStartRun does.
+func (e *Engine) StartRun(worldID, id string) bool {
+	if !e.CanStartRun(worldID, id) {
+		return false
+	}
+	rs := &e.State.Runs
+	if rs.Active == nil {
+		rs.Active = make(map[string]runs.Active)
+	}
+	rs.Active[worldID] = runs.Active{ID: id, Goal: e.runGoal(worldID)}
+	// reset under the run, so it starts with only the buy-ons it allows.
+	e.resetWorld(worldID)
+	rec := rs.Record(worldID, id)
+	rec.Attempts++
+	rs.SetRecord(worldID, id, rec)
+	if e.Recorder != nil {
+		e.Recorder.RecordRunStart(worldID, id)
+	}
+	return true
+}
+
+// AbandonRun gives up the challenge run in progress in worldID. The world
+// is left as it is. It returns false if no run is in progress there.
+func (e *Engine) AbandonRun(worldID string) bool {
+	if _, ok := e.State.Runs.Active[worldID]; !ok {
+		return false
+	}
+	delete(e.State.Runs.Active, worldID)
+	if e.Recorder != nil {
+		e.Recorder.RecordRunAbandon(worldID)
+	}
+	return true
+}
+
+// runGoal returns the coins a challenge run in worldID must earn: the coins
+// its prestige threshold asks for, if any, else runs.DefaultGoal.
+func (e *Engine) runGoal(worldID string) float64 {
+	if w, ok := e.WorldReg.Get(worldID); ok {
+		conds, _ := w.Config().PrestigeThreshold.Conditions()
+		for _, c := range conds {
+			if c.Type == config.PrestigeMetricCoinsEarned && c.Value > 0 {
+				return c.Value
+			}
+		}
+	}
+	return runs.DefaultGoal
+}
+
+// cpsMultiplier returns the multiplier on worldID's CPS: its prestige
+// multiplier, its prestige tree's bonus, the bonuses of the challenge runs
+// completed there and the player's CPS perks.
+func (e *Engine) cpsMultiplier(worldID string) float64 {
+	mult := e.State.Runs.Multiplier(worldID) * (1 + e.treeEffects(worldID).CPSBonus) * (1 + e.perkEffects().CPSBonus)
+	if ws, ok := e.State.Worlds[worldID]; ok {
+		mult *= ws.PrestigeMultiplier
+	}
+	return mult
+}
+
+// buyOnAllowed reports whether the challenge run in progress in worldID, if
+// any, allows buying b.
+func (e *Engine) buyOnAllowed(worldID string, reg *upgrade.WorldUpgradeRegistry, b upgrade.BuyOn) bool {
+	r := e.State.Runs.Restrictions(worldID)
+	if r.MaxBuyOns == 0 {
+		return true
+	}
+	i := slices.IndexFunc(reg.ListBuyOns(), func(o upgrade.BuyOn) bool { return o.ID() == b.ID() })
+	return r.AllowsBuyOn(i)
+}
+
+// advanceRun adds amount to the coins earned by the challenge run in
+// progress in worldID, if any. The run is completed by the next Tick.
+func (e *Engine) advanceRun(worldID string, amount float64) {
+	a, ok := e.State.Runs.Active[worldID]
+	if !ok {
+		return
+	}
+	a.Earned += amount
+	e.State.Runs.Active[worldID] = a
+}
+
+// updateRuns adds dt seconds of play to every challenge run in progress and
+// ends those that reached their goal or ran out of time, returning an
+// EventRunCompleted or EventRunFailed for each. Completing a run the first
+// time grants its bonus.
+func (e *Engine) updateRuns(dt float64) []EngineEvent {
+	var events []EngineEvent
+	rs := &e.State.Runs
+	for _, worldID := range sortedKeys(rs.Active) {
+		a := rs.Active[worldID]
+		d, ok := runs.Get(a.ID)
+		if !ok {
+			// a run this version does not know cannot be enforced.
+			delete(rs.Active, worldID)
+			continue
+		}
+		a.Elapsed += dt
+		rs.Active[worldID] = a
+		switch {
+		case a.Earned >= a.Goal:
+			delete(rs.Active, worldID)
+			rec := rs.Record(worldID, a.ID)
+			if !rec.Completed || a.Elapsed < rec.BestSeconds {
+				rec.BestSeconds = a.Elapsed
+			}
+			rec.Completed = true
+			rs.SetRecord(worldID, a.ID, rec)
+			e.refreshCPS(worldID)
+			events = append(events, EngineEvent{Type: EventRunCompleted, WorldID: worldID, RunID: a.ID})
+		case d.TimeLimit > 0 && a.Elapsed > d.TimeLimit:
+			delete(rs.Active, worldID)
+			events = append(events, EngineEvent{Type: EventRunFailed, WorldID: worldID, RunID: a.ID})
+		}
+	}
+	return events
+}
+
+// refreshCPS recomputes worldID's CPS from its buy-ons and multipliers.
+func (e *Engine) refreshCPS(worldID string) {
+	ws, ok := e.State.Worlds[worldID]
+	reg, hasReg := e.UpgradeReg[worldID]
+	if !ok || !hasReg {
+		return
+	}
+	ws.CPS = upgrade.CalculateWorldCPS(reg, ws.BuyOnCounts, ws.PurchasedUpgrades, e.cpsMultiplier(worldID), 1.0)
+}
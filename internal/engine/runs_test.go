@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/upgrade"
+)
+
+func newRunTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	return eng
+}
+
+func TestStartRun_ResetsWorldAndEnforcesRestrictions(t *testing.T) {
+	eng := newRunTestEngine(t)
+	eng.State.Player.Level = 2 // the smelter's requirement
+	ws := eng.State.Worlds["terra"]
+	ws.Coins = 1e4
+	ws.TotalCoinsEarned = 2e6
+	_, ok := eng.PurchaseBuyOn("terra", "auto_miner")
+	require.True(t, ok)
+
+	require.True(t, eng.StartRun("terra", "two_buy_ons"))
+	assert.Zero(t, ws.Coins)
+	assert.Empty(t, ws.BuyOnCounts)
+	assert.Zero(t, ws.CPS)
+	assert.Equal(t, 1e6, eng.State.Runs.Active["terra"].Goal, "the goal is the prestige threshold")
+	assert.Equal(t, 1, eng.State.Runs.Record("terra", "two_buy_ons").Attempts)
+	assert.False(t, eng.CanPrestige("terra"), "no prestige during a run")
+	assert.False(t, eng.StartRun("terra", "no_clicks"), "one run per world at a time")
+
+	ws.Coins = 1e6
+	_, ok = eng.PurchaseBuyOn("terra", "smelter")
+	assert.False(t, ok, "only the first two buy-ons")
+	_, ok = eng.PurchaseBuyOn("terra", "drill_bot")
+	assert.True(t, ok)
+	hints := eng.ShopHints("terra")
+	assert.False(t, hints[1].Barred)
+	assert.True(t, hints[2].Barred)
+	assert.False(t, hints[2].BestValue)
+
+	require.True(t, eng.AbandonRun("terra"))
+	assert.False(t, eng.AbandonRun("terra"))
+	assert.True(t, eng.CanPrestige("terra"))
+	_, ok = eng.PurchaseBuyOn("terra", "smelter")
+	assert.True(t, ok, "restrictions end with the run")
+}
+
+func TestRun_NoClicksAndInflation(t *testing.T) {
+	eng := newRunTestEngine(t)
+	require.True(t, eng.StartRun("terra", "no_clicks"))
+	assert.Zero(t, eng.HandleClick("terra"))
+	assert.Zero(t, eng.State.Worlds["terra"].TotalClicks)
+	assert.Equal(t, 1.0, eng.HandleClick("aqua"), "other worlds are not restricted")
+
+	require.True(t, eng.StartRun("aqua", "inflation"))
+	aqua := eng.State.Worlds["aqua"]
+	aqua.Coins = 1e6
+	b, _ := eng.UpgradeReg["aqua"].GetBuyOn(eng.ShopHints("aqua")[0].BuyOnID)
+	_, ok := eng.PurchaseBuyOn("aqua", b.ID())
+	require.True(t, ok)
+	assert.Greater(t, eng.ShopHints("aqua")[0].Cost, upgrade.CostForNext(b, 1), "costs grow faster")
+}
+
+func TestTick_CompletesRunAndGrantsBonus(t *testing.T) {
+	eng := newRunTestEngine(t)
+	ws := eng.State.Worlds["terra"]
+	require.True(t, eng.StartRun("terra", "no_clicks"))
+	ws.Coins = 50
+	_, ok := eng.PurchaseBuyOn("terra", "auto_miner")
+	require.True(t, ok)
+	cps := ws.CPS
+
+	ws.CPS = 5e5
+	eng.Tick(1)
+	assert.Contains(t, eng.State.Runs.Active, "terra", "half way there")
+	events := eng.Tick(1)
+	require.Equal(t, 1, countEvents(events, EventRunCompleted))
+	assert.Empty(t, eng.State.Runs.Active)
+	rec := eng.State.Runs.Record("terra", "no_clicks")
+	assert.True(t, rec.Completed)
+	assert.Equal(t, 2.0, rec.BestSeconds)
+	assert.InDelta(t, cps*1.1, ws.CPS, 1e-9, "the bonus applies at once")
+	assert.InDelta(t, 1.1, eng.State.Runs.Multiplier("terra"), 1e-9)
+}
+
+func TestTick_FailsRunPastTimeLimit(t *testing.T) {
+	eng := newRunTestEngine(t)
+	require.True(t, eng.StartRun("terra", "speedrun"))
+	assert.Zero(t, countEvents(eng.Tick(900), EventRunFailed), "the limit itself is still in time")
+	events := eng.Tick(1)
+	require.Equal(t, 1, countEvents(events, EventRunFailed))
+	assert.Empty(t, eng.State.Runs.Active)
+	assert.False(t, eng.State.Runs.Record("terra", "speedrun").Completed)
+	assert.Equal(t, 1.0, eng.State.Runs.Multiplier("terra"))
+}
+
+func TestAutoBuyer_RespectsRunRestrictions(t *testing.T) {
+	eng := newRunTestEngine(t)
+	eng.State.AutoBuyer.Unlocked = true
+	eng.State.AutoBuyer.Worlds = map[string]autobuy.WorldSettings{"terra": {Enabled: true, Policy: autobuy.PolicyCheapest}}
+	eng.State.AutoBuyer.Reserve = 0
+	require.True(t, eng.StartRun("terra", "two_buy_ons"))
+	ws := eng.State.Worlds["terra"]
+	ws.Coins = 1e9
+
+	eng.Tick(AutoBuyInterval)
+	require.NotEmpty(t, ws.BuyOnCounts)
+	for id := range ws.BuyOnCounts {
+		assert.Contains(t, []string{"auto_miner", "drill_bot"}, id)
+	}
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"math"
+
+	"github.com/clicker-org/clicker/internal/upgrade"
+)
+
+// BuyOnHint is decision support for buying the next unit of a buy-on.
+type BuyOnHint struct {
+	BuyOnID string
+	Cost    float64
+	// MarginalCPS is the CPS the next unit adds, with upgrade and prestige
+	// multipliers applied.
+	MarginalCPS float64
+	// PaybackSeconds is how long the next unit takes to earn back its cost:
+	// Cost / MarginalCPS.
+	PaybackSeconds float64
+	// AffordSeconds is how long until the balance covers Cost at the current
+	// CPS: 0 if it already does, +Inf if there is no income.
+	AffordSeconds float64
+	// Locked is set if the player's level is below the buy-on's requirement.
+	Locked bool
+	// Barred is set if the challenge run in progress forbids buying it.
+	Barred bool
+	// BestValue marks the unlocked, unbarred buy-on that has paid for itself
+	// soonest from now, counting the wait to afford it: the lowest
+	// AffordSeconds + PaybackSeconds.
+	BestValue bool
+}
+
+// ShopHints returns a hint for every buy-on of worldID in registry order, or
+// nil for an unknown world. They depend on the balance, so callers showing
+// them should recompute them every tick.
+func (e *Engine) ShopHints(worldID string) []BuyOnHint {
+	ws, ok := e.State.Worlds[worldID]
+	if !ok {
+		return nil
+	}
+	reg, ok := e.UpgradeReg[worldID]
+	if !ok {
+		return nil
+	}
+	upgrades := reg.ListUpgrades()
+	buyOns := reg.ListBuyOns()
+	restrictions := e.State.Runs.Restrictions(worldID)
+	hints := make([]BuyOnHint, 0, len(buyOns))
+	best, bestTime := -1, math.Inf(1)
+	for i, b := range buyOns {
+		h := BuyOnHint{
+			BuyOnID:     b.ID(),
+			Cost:        e.buyOnCost(worldID, b, ws.BuyOnCounts[b.ID()]),
+			MarginalCPS: upgrade.MarginalCPS(b, upgrades, ws.PurchasedUpgrades, e.cpsMultiplier(worldID)),
+			Locked:      b.LevelRequirement() > e.State.Player.Level,
+			Barred:      !restrictions.AllowsBuyOn(i),
+		}
+		h.PaybackSeconds = secondsToEarn(h.Cost, h.MarginalCPS)
+		h.AffordSeconds = secondsToEarn(h.Cost-ws.Coins, ws.CPS)
+		if total := h.AffordSeconds + h.PaybackSeconds; !h.Locked && !h.Barred && total < bestTime {
+			best, bestTime = i, total
+		}
+		hints = append(hints, h)
+	}
+	if best >= 0 {
+		hints[best].BestValue = true
+	}
+	return hints
+}
+
+// secondsToEarn returns how long earning amount takes at cps: 0 if there is
+// nothing to earn, +Inf if there is no income.
+func secondsToEarn(amount, cps float64) float64 {
+	switch {
+	case amount <= 0:
+		return 0
+	case cps <= 0:
+		return math.Inf(1)
+	}
+	return amount / cps
+}
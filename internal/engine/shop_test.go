@@ -0,0 +1,48 @@
+package engine
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShopHints(t *testing.T) {
+	eng := newTestEngine(t)
+	terra := eng.State.Worlds["terra"]
+	terra.BuyOnCounts["auto_miner"] = 1
+	terra.PurchasedUpgrades["turbo_miner"] = true
+	terra.PrestigeMultiplier = 1.5
+	terra.CPS = 10
+	terra.Coins = 100
+
+	hints := eng.ShopHints("terra")
+	require.Len(t, hints, 5)
+
+	miner := hints[0]
+	assert.Equal(t, "auto_miner", miner.BuyOnID)
+	assert.InDelta(t, 57.5, miner.Cost, 1e-9)
+	assert.InDelta(t, 0.1*2*1.5, miner.MarginalCPS, 1e-9, "turbo_miner and prestige apply")
+	assert.InDelta(t, 57.5/0.3, miner.PaybackSeconds, 1e-9)
+	assert.Zero(t, miner.AffordSeconds, "already affordable")
+
+	drill := hints[1]
+	assert.InDelta(t, 40, drill.AffordSeconds, 1e-9, "400 more at 10 CPS")
+	assert.InDelta(t, 500/1.5, drill.PaybackSeconds, 1e-9)
+	assert.True(t, miner.BestValue, "paid back in 192s beats 40s of saving plus 333s")
+	assert.False(t, drill.BestValue)
+}
+
+func TestShopHints_NoIncome(t *testing.T) {
+	eng := newTestEngine(t)
+
+	hints := eng.ShopHints("terra")
+
+	assert.True(t, math.IsInf(hints[0].AffordSeconds, 1))
+	assert.True(t, hints[2].Locked, "smelter needs level 2")
+	for _, h := range hints {
+		assert.False(t, h.BestValue, "nothing can be bought without income")
+	}
+	assert.Nil(t, eng.ShopHints("no_such_world"))
+}
@@ -9,6 +9,10 @@ const (
 	ScreenDashboard     ScreenID = "dashboard"
 	ScreenAchievements  ScreenID = "achievements"
 	ScreenOfflineReport ScreenID = "offline_report"
+	ScreenLoginReward   ScreenID = "login_reward"
+	ScreenSettings      ScreenID = "settings"
+	ScreenPerks         ScreenID = "perks"
+	ScreenMarket        ScreenID = "market"
 )
 
 // NavigateTo returns the target screen ID.
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/clicker-org/clicker/internal/streak"
+)
+
+// LoginReward returns the login streak reward the player can claim at now,
+// or false if today's has been claimed.
+func (e *Engine) LoginReward(now time.Time) (streak.Offer, bool) {
+	return e.State.Streak.Next(e.trustedNow(now))
+}
+
+// ClaimLoginReward claims the login streak reward available at now and
+// grants it, queueing an EventLevelUp for the next Tick if its XP levels the
+// player up. It returns false if today's has already been claimed.
+func (e *Engine) ClaimLoginReward(now time.Time) (streak.Offer, bool) {
+	o, ok := e.State.Streak.Claim(e.trustedNow(now))
+	if !ok {
+		return o, false
+	}
+	prevLevel := e.State.Player.Level
+	if e.addXP(o.Reward.XP) {
+		e.pending = append(e.pending, EngineEvent{Type: EventLevelUp, NewLevel: e.State.Player.Level, PrevLevel: prevLevel})
+	}
+	e.State.Player.GeneralCoins += o.Reward.GC
+	e.State.Player.LifetimeGeneralCoins += o.Reward.GC
+	if e.Recorder != nil {
+		e.Recorder.RecordLoginClaim(now)
+	}
+	return o, true
+}
+
+// Now returns the current wall-clock time from e.Clock, which clock-jump
+// detection watches, or the system time for an engine without a clock.
+// Actions taken "now" from outside a Tick, such as claiming the login
+// reward, should use it.
+func (e *Engine) Now() time.Time {
+	if e.Clock == nil {
+		return time.Now()
+	}
+	return e.Clock.Now()
+}
+
+// trustedNow returns now, or the clock high-water mark in now's time zone
+// if the system clock has been set back before it.
+func (e *Engine) trustedNow(now time.Time) time.Time {
+	if hw := e.State.Clock.LastWall; now.Before(hw) {
+		return hw.In(now.Location())
+	}
+	return now
+}
@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/clock"
+	"github.com/clicker-org/clicker/internal/streak"
+)
+
+func TestClaimLoginReward(t *testing.T) {
+	eng := newTestEngine(t)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 9, 0, 0, 0, time.UTC) }
+
+	o, ok := eng.ClaimLoginReward(day(1))
+	require.True(t, ok)
+	assert.Equal(t, streak.Rewards[0], o.Reward)
+	assert.Equal(t, streak.Rewards[0].XP, eng.State.Player.XP)
+	assert.Equal(t, streak.Rewards[0].GC, eng.State.Player.GeneralCoins)
+	_, ok = eng.LoginReward(day(1))
+	assert.False(t, ok)
+
+	// the clock was seen at day 5; a claim dated day 2 is clamped to it.
+	eng.State.Clock.LastWall = day(5)
+	o, ok = eng.ClaimLoginReward(day(2))
+	require.True(t, ok)
+	assert.Equal(t, "2026-10-05", o.Day)
+	assert.True(t, o.Broken)
+	_, ok = eng.ClaimLoginReward(day(3))
+	assert.False(t, ok, "setting the clock back does not reach a new day")
+}
+
+func TestClaimLoginReward_LevelUpIsReported(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Player.XP = 95
+	_, ok := eng.ClaimLoginReward(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	require.Equal(t, 2, eng.State.Player.Level)
+
+	events := eng.Tick(0.1)
+	require.Equal(t, 1, countEvents(events, EventLevelUp))
+	for _, ev := range events {
+		if ev.Type == EventLevelUp {
+			assert.Equal(t, 1, ev.PrevLevel)
+			assert.Equal(t, 2, ev.NewLevel)
+		}
+	}
+}
+
+func TestClaimLoginReward_AtEngineClock(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = clock.NewFake(time.Date(2026, 10, 7, 9, 0, 0, 0, time.UTC))
+
+	o, ok := eng.ClaimLoginReward(eng.Now())
+	require.True(t, ok)
+	assert.Equal(t, "2026-10-07", o.Day, "the engine's clock, not the system's, dates the claim")
+}
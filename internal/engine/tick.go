@@ -2,9 +2,13 @@ package engine
 
 import (
 	"math"
+	"time"
 
 	"github.com/clicker-org/clicker/internal/achievement"
-	"github.com/clicker-org/clicker/internal/player"
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/world"
 )
 
 // EngineEventType identifies the kind of engine event.
@@ -13,7 +17,15 @@ type EngineEventType string
 const (
 	EventAchievementUnlocked EngineEventType = "achievement_unlocked"
 	EventLevelUp             EngineEventType = "level_up"
+	EventMilestoneReached    EngineEventType = "milestone_reached"
 	EventAutoSave            EngineEventType = "autosave"
+	EventClockJump           EngineEventType = "clock_jump"
+	EventAutoBuy             EngineEventType = "auto_buy"
+	EventChallengeCompleted  EngineEventType = "challenge_completed"
+	EventRunCompleted        EngineEventType = "run_completed"
+	EventRunFailed           EngineEventType = "run_failed"
+	EventXPGained            EngineEventType = "xp_gained"
+	EventMarketShock         EngineEventType = "market_shock"
 )
 
 // EngineEvent is emitted by Tick to communicate side-effects to the UI layer.
@@ -21,23 +33,61 @@ type EngineEvent struct {
 	Type EngineEventType
 	// For EventAchievementUnlocked: ID of the achievement.
 	AchievementID string
-	// For EventLevelUp: the new level.
-	NewLevel int
+	// For EventLevelUp: the new level and the level before, whose rewards
+	// in between (see level.Between) have been granted.
+	NewLevel  int
+	PrevLevel int
+	// For EventMilestoneReached: the world and the milestone ID within it.
+	WorldID     string
+	MilestoneID string
+	// For EventClockJump: how far the wall clock jumped relative to the
+	// monotonic clock (positive = forward).
+	ClockJump time.Duration
+	// For EventAutoBuy: what the auto-buyer bought in WorldID.
+	Purchases []autobuy.Purchase
+	// For EventChallengeCompleted: ID of the challenge.
+	ChallengeID string
+	// For EventRunCompleted and EventRunFailed: ID of the challenge run that
+	// ended in WorldID.
+	RunID string
+	// For EventXPGained: the gameplay source (a config.XPSource* constant)
+	// and the XP it granted.
+	XPSource string
+	XP       int
+	// For EventMarketShock: the name of the shock that hit WorldID's coin on
+	// the market, and the factor it moved the price by.
+	Shock  string
+	Impact float64
 }
 
 // Timing constants.
 const (
-	AutoSaveInterval    = 30.0  // seconds
-	AchievCheckInterval = 5.0   // seconds
-	TickIntervalMs      = 100   // milliseconds per tick
+	AutoSaveInterval    = 30.0 // seconds
+	AchievCheckInterval = 5.0  // seconds
+	AutoBuyInterval     = 1.0  // seconds
+	TickIntervalMs      = 100  // milliseconds per tick
 )
 
+// ClockJumpTolerance is how far wall time may drift from monotonic time
+// between two ticks before it is reported as a clock jump.
+const ClockJumpTolerance = 2 * time.Minute
+
 // Tick advances the engine by dt seconds and returns any events that occurred.
 func (e *Engine) Tick(dt float64) []EngineEvent {
 	var events []EngineEvent
+	// A new day's challenges are recorded as an action of their own, ahead
+	// of the tick, since replays run without a clock.
+	if e.Clock != nil {
+		e.BeginChallengeDay(e.trustedNow(e.Clock.Now()).Format(time.DateOnly))
+	}
+	if e.Recorder != nil {
+		e.Recorder.RecordTick(dt)
+	}
 
-	// 1. Apply CPS to all active worlds.
+	// 1. Apply CPS to all active worlds, then move the market prices on.
 	for _, ws := range e.State.Worlds {
+		ws.SinceResetSeconds += dt
+		ws.BoostCooldown = max(ws.BoostCooldown-dt, 0)
 		if ws.CPS > 0 {
 			earned := ws.CPS * dt
 			ws.Coins += earned
@@ -46,53 +96,162 @@ func (e *Engine) Tick(dt float64) []EngineEvent {
 				e.State.Player.WorldTotalCoinsEarned = make(map[string]float64)
 			}
 			e.State.Player.WorldTotalCoinsEarned[ws.WorldID] += earned
+			e.advanceChallenges(challenge.KindEarn, ws.WorldID, earned)
+			e.advanceRun(ws.WorldID, earned)
 		}
 	}
+	e.gainCoinsXP()
+	events = append(events, e.advanceMarket(dt)...)
 
-	// 2. Update total play seconds.
+	// 2. Update total play seconds and observe the clock.
 	e.State.Player.TotalPlaySeconds += dt
+	if ev, jumped := e.observeClock(); jumped {
+		events = append(events, ev)
+	}
 
-	// 3. Debounced achievement check.
+	// 3. Debounced achievement and milestone check.
 	e.achievCheckTimer += dt
 	if e.achievCheckTimer >= AchievCheckInterval {
 		e.achievCheckTimer = 0
-		newlyUnlocked := achievement.CheckAchievements(e.State, e.AchievReg, e.Earned)
-		for _, id := range newlyUnlocked {
-			e.Earned[id] = true
-			prevLevel := e.State.Player.Level
-			if a, ok := e.AchievReg.Get(id); ok {
-				if a.XPGrant > 0 {
-					player.AddXP(&e.State.Player, a.XPGrant)
-				}
-				if a.Reward != nil {
-					switch a.Reward.Type {
-					case achievement.RewardTypeXP:
-						player.AddXP(&e.State.Player, int(math.Round(a.Reward.Value)))
-					case achievement.RewardTypeGeneralCoins:
-						e.State.Player.GeneralCoins += a.Reward.Value
-						e.State.Player.LifetimeGeneralCoins += a.Reward.Value
-					}
+		events = append(events, e.CheckUnlocks()...)
+	}
+
+	// 4. Auto-buyer, on its own debounce.
+	e.autoBuyTimer += dt
+	if e.autoBuyTimer >= AutoBuyInterval {
+		e.autoBuyTimer = 0
+		events = append(events, e.runAutoBuyer()...)
+	}
+
+	// 5. Challenges that reached their target, and challenge runs that
+	// reached their goal or ran out of time.
+	events = append(events, e.completeChallenges()...)
+	events = append(events, e.updateRuns(dt)...)
+
+	// 6. XP gained since the last tick, including by the player's actions.
+	events = append(events, e.pending...)
+	e.pending = nil
+
+	// 7. Autosave timer.
+	e.autosaveTimer += dt
+	if e.autosaveTimer >= AutoSaveInterval {
+		e.autosaveTimer = 0
+		events = append(events, EngineEvent{Type: EventAutoSave})
+	}
+
+	return events
+}
+
+// CheckUnlocks evaluates achievements and world completion milestones against
+// the current state, applies their rewards, and returns the resulting events.
+// Tick calls it on a debounce; callers that change state in bulk (e.g. offline
+// income) can call it directly to surface unlocks immediately.
+func (e *Engine) CheckUnlocks() []EngineEvent {
+	var events []EngineEvent
+
+	newlyUnlocked := achievement.CheckAchievements(e.State, e.AchievReg, e.Earned)
+	for _, id := range newlyUnlocked {
+		e.Earned[id] = true
+		prevLevel := e.State.Player.Level
+		if a, ok := e.AchievReg.Get(id); ok {
+			if a.XPGrant > 0 {
+				e.addXP(a.XPGrant)
+			}
+			if a.Reward != nil {
+				switch a.Reward.Type {
+				case achievement.RewardTypeXP:
+					e.addXP(int(math.Round(a.Reward.Value)))
+				case achievement.RewardTypeGeneralCoins:
+					e.State.Player.GeneralCoins += a.Reward.Value
+					e.State.Player.LifetimeGeneralCoins += a.Reward.Value
 				}
 			}
-			if e.State.Player.Level > prevLevel {
+		}
+		if e.State.Player.Level > prevLevel {
+			events = append(events, EngineEvent{
+				Type:      EventLevelUp,
+				NewLevel:  e.State.Player.Level,
+				PrevLevel: prevLevel,
+			})
+		}
+		events = append(events, EngineEvent{
+			Type:          EventAchievementUnlocked,
+			AchievementID: id,
+		})
+	}
+
+	events = append(events, e.updateMilestones(true)...)
+	return events
+}
+
+// updateMilestones records newly met completion milestones for every world and
+// refreshes each world's CompletionPercent. When emit is false the milestones
+// are recorded silently, which is used to establish a baseline before a bulk
+// state change so that only milestones caused by that change are reported.
+func (e *Engine) updateMilestones(emit bool) []EngineEvent {
+	var events []EngineEvent
+	for _, w := range e.WorldReg.List() {
+		ws, ok := e.State.Worlds[w.ID()]
+		if !ok {
+			continue
+		}
+		if ws.Milestones == nil {
+			ws.Milestones = make(map[string]bool)
+		}
+		milestones := w.Config().CompletionMilestones
+		for _, m := range milestones {
+			if ws.Milestones[m.ID] || !world.MilestoneMet(m, ws) {
+				continue
+			}
+			ws.Milestones[m.ID] = true
+			e.gainXP(config.XPSourceMilestones, 1)
+			if emit {
 				events = append(events, EngineEvent{
-					Type:     EventLevelUp,
-					NewLevel: e.State.Player.Level,
+					Type:        EventMilestoneReached,
+					WorldID:     w.ID(),
+					MilestoneID: m.ID,
 				})
 			}
-			events = append(events, EngineEvent{
-				Type:          EventAchievementUnlocked,
-				AchievementID: id,
-			})
 		}
+		ws.CompletionPercent = world.CompletionFromMilestones(milestones, ws)
 	}
+	return events
+}
 
-	// 4. Autosave timer.
-	e.autosaveTimer += dt
-	if e.autosaveTimer >= AutoSaveInterval {
-		e.autosaveTimer = 0
-		events = append(events, EngineEvent{Type: EventAutoSave})
+// observeClock advances the monotonic play-time counter and the wall-clock
+// high-water mark in State.Clock. Trusted time for the session is the wall
+// time at launch plus monotonic time elapsed since, so changing the system
+// clock while playing never raises the high-water mark. A wall-clock move of
+// more than ClockJumpTolerance relative to monotonic time since the previous
+// call is reported as an EventClockJump.
+func (e *Engine) observeClock() (EngineEvent, bool) {
+	if e.Clock == nil {
+		return EngineEvent{}, false
+	}
+	mono := e.Clock.Monotonic()
+	wall := e.Clock.Now()
+	rec := &e.State.Clock
+	if !e.clockAnchored {
+		e.clockAnchored = true
+		e.anchorMono, e.anchorWall = mono, wall
+		e.lastMono, e.lastWall = mono, wall
+		if wall.After(rec.LastWall) {
+			rec.LastWall = wall
+		}
+		return EngineEvent{}, false
 	}
 
-	return events
+	monoDelta := mono - e.lastMono
+	jump := wall.Sub(e.lastWall) - monoDelta
+	rec.PlaySeconds += monoDelta.Seconds()
+	e.lastMono, e.lastWall = mono, wall
+
+	if trusted := e.anchorWall.Add(mono - e.anchorMono); trusted.After(rec.LastWall) {
+		rec.LastWall = trusted
+	}
+
+	if jump > ClockJumpTolerance || jump < -ClockJumpTolerance {
+		return EngineEvent{Type: EventClockJump, ClockJump: jump}, true
+	}
+	return EngineEvent{}, false
 }
@@ -2,12 +2,15 @@ package engine
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/clock"
 	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/level"
 	"github.com/clicker-org/clicker/internal/world"
 	_ "github.com/clicker-org/clicker/internal/world/worlds"
 )
@@ -24,6 +27,17 @@ func newTestEngineWithAchievement(t *testing.T, a achievement.Achievement) *Engi
 	return New(gs, world.DefaultRegistry, achReg)
 }
 
+// newTestEngine builds an Engine over every registered world with an empty
+// achievement registry.
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+	gs := gamestate.NewGameState()
+	for _, w := range world.DefaultRegistry.List() {
+		gs.Worlds[w.ID()] = world.NewWorldState(w.ID(), w.BaseExchangeRate())
+	}
+	return New(gs, world.DefaultRegistry, achievement.NewAchievementRegistry())
+}
+
 func countEvents(events []EngineEvent, typ EngineEventType) int {
 	n := 0
 	for _, ev := range events {
@@ -51,8 +65,10 @@ func TestTick_AchievementAppliesXPAndGeneralCoinsReward(t *testing.T) {
 	require.True(t, eng.Earned["test_reward_gc"])
 	assert.Equal(t, 120, eng.State.Player.XP)
 	assert.Equal(t, 2, eng.State.Player.Level, "120 XP should level from 1 to 2")
-	assert.Equal(t, 17.5, eng.State.Player.GeneralCoins)
-	assert.Equal(t, 17.5, eng.State.Player.LifetimeGeneralCoins)
+	// plus the level 2 reward.
+	gc := 17.5 + level.Between(level.Rewards, 1, 2)[0].GeneralCoins
+	assert.Equal(t, gc, eng.State.Player.GeneralCoins)
+	assert.Equal(t, gc, eng.State.Player.LifetimeGeneralCoins)
 	assert.Equal(t, 1, countEvents(events, EventAchievementUnlocked))
 	assert.Equal(t, 1, countEvents(events, EventLevelUp))
 }
@@ -75,3 +91,52 @@ func TestTick_AchievementRewardTypeXPAddsXP(t *testing.T) {
 	assert.Equal(t, 60, eng.State.Player.XP)
 	assert.Equal(t, 1, eng.State.Player.Level)
 }
+
+func TestTick_MilestoneReachedUpdatesCompletion(t *testing.T) {
+	eng := newTestEngineWithAchievement(t, achievement.Achievement{
+		ID:        "test_never",
+		Name:      "Never",
+		Condition: func(gs gamestate.GameState) bool { return false },
+	})
+
+	eng.HandleClick("terra")
+	events := eng.Tick(AchievCheckInterval)
+
+	require.Equal(t, 1, countEvents(events, EventMilestoneReached))
+	assert.Equal(t, "terra", events[0].WorldID)
+	assert.Equal(t, "first_click", events[0].MilestoneID)
+	assert.InDelta(t, 0.05, eng.State.Worlds["terra"].CompletionPercent, 0.0001)
+
+	// Already-reached milestones are not re-emitted.
+	events = eng.Tick(AchievCheckInterval)
+	assert.Equal(t, 0, countEvents(events, EventMilestoneReached))
+}
+
+func TestTick_ClockJumpDoesNotRaiseHighWaterMark(t *testing.T) {
+	eng := newTestEngineWithAchievement(t, achievement.Achievement{
+		ID:        "test_never",
+		Name:      "Never",
+		Condition: func(gs gamestate.GameState) bool { return false },
+	})
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	eng.Clock = fake
+
+	eng.Tick(0.1)
+	fake.Advance(10 * time.Second)
+	eng.Tick(0.1)
+	assert.Equal(t, start.Add(10*time.Second), eng.State.Clock.LastWall)
+	assert.InDelta(t, 10.0, eng.State.Clock.PlaySeconds, 0.001)
+
+	// The user moves the system clock a day forward mid-session.
+	fake.SetWall(fake.Now().Add(24 * time.Hour))
+	fake.Advance(time.Second)
+	events := eng.Tick(0.1)
+	require.Equal(t, 1, countEvents(events, EventClockJump))
+
+	fake.Advance(time.Second)
+	assert.Equal(t, 0, countEvents(eng.Tick(0.1), EventClockJump), "jump is reported once")
+	assert.Equal(t, start.Add(12*time.Second), eng.State.Clock.LastWall,
+		"high-water mark advances by monotonic time only")
+	assert.InDelta(t, 12.0, eng.State.Clock.PlaySeconds, 0.001)
+}
@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/level"
+	"github.com/clicker-org/clicker/internal/player"
+	"github.com/clicker-org/clicker/internal/xp"
+)
+
+// XPMultiplier returns the global multiplier on all the XP the player
+// earns, raised by general shop items and perks.
+func (e *Engine) XPMultiplier() float64 {
+	return xp.Multiplier(e.generalShopItems(), e.perkEffects().XPBonus)
+}
+
+// generalShopItems returns the general shop items the player owns. The
+// general shop is not open yet, so there are none.
+func (e *Engine) generalShopItems() []economy.GeneralShopItem {
+	return nil
+}
+
+// addXP grants the player base XP under the global XP multiplier and
+// reports whether they leveled up.
+func (e *Engine) addXP(base int) bool {
+	return e.grantXP(xp.Apply(base, e.XPMultiplier()))
+}
+
+// grantXP adds n XP to the player, granting the rewards of every level it
+// takes them to, and reports whether they leveled up. Perk points and
+// cosmetics follow from the level alone; only general coins are paid out.
+func (e *Engine) grantXP(n int) bool {
+	p := &e.State.Player
+	prevLevel := p.Level
+	if !player.AddXP(p, n) {
+		return false
+	}
+	e.payLevelRewards(prevLevel, p.Level)
+	return true
+}
+
+// payLevelRewards pays out the general coins of the level rewards between
+// levels from and to.
+func (e *Engine) payLevelRewards(from, to int) {
+	p := &e.State.Player
+	for _, r := range level.Between(level.Rewards, from, to) {
+		p.GeneralCoins += r.GeneralCoins
+		p.LifetimeGeneralCoins += r.GeneralCoins
+	}
+}
+
+// gainXP makes n grants from the gameplay XP source (a config.XPSource*
+// constant), each worth less than the one before, and queues an
+// EventXPGained, and an EventLevelUp if the player leveled up, for the next
+// Tick to return.
+func (e *Engine) gainXP(source string, n int) {
+	if n <= 0 {
+		return
+	}
+	p := &e.State.Player
+	if p.XPSources == nil {
+		p.XPSources = make(map[string]int)
+	}
+	src := xp.Rates.Sources[source]
+	base := 0
+	for range n {
+		base += xp.Grant(src, p.XPSources[source])
+		p.XPSources[source]++
+	}
+	granted := xp.Apply(base, e.XPMultiplier())
+	if granted <= 0 {
+		return
+	}
+	e.pending = append(e.pending, EngineEvent{Type: EventXPGained, XPSource: source, XP: granted})
+	prevLevel := p.Level
+	if e.grantXP(granted) {
+		e.pending = append(e.pending, EngineEvent{Type: EventLevelUp, NewLevel: p.Level, PrevLevel: prevLevel})
+	}
+}
+
+// gainCoinsXP makes the config.XPSourceCoinsEarned grants due for the coins
+// earned over all worlds.
+func (e *Engine) gainCoinsXP() {
+	p := e.State.Player
+	total := 0.0
+	for _, id := range sortedKeys(p.WorldTotalCoinsEarned) {
+		total += p.WorldTotalCoinsEarned[id]
+	}
+	e.gainXP(config.XPSourceCoinsEarned, xp.Decades(total)-p.XPSources[config.XPSourceCoinsEarned])
+}
@@ -0,0 +1,128 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/level"
+	"github.com/clicker-org/clicker/internal/player"
+	"github.com/clicker-org/clicker/internal/xp"
+)
+
+// xpEvents returns the XP granted by source in events.
+func xpEvents(events []EngineEvent) map[string]int {
+	got := map[string]int{}
+	for _, ev := range events {
+		if ev.Type == EventXPGained {
+			got[ev.XPSource] += ev.XP
+		}
+	}
+	return got
+}
+
+func TestGainXP_FromActionsReportedByNextTick(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	ws := eng.State.Worlds["terra"]
+	ws.Coins = 1000
+	_, ok := eng.PurchaseBuyOn("terra", "auto_miner")
+	require.True(t, ok)
+	_, ok = eng.PurchaseBuyOn("terra", "auto_miner")
+	require.True(t, ok)
+	_, ok = eng.ExecuteExchangeBoost("terra")
+	require.True(t, ok)
+
+	rates := xp.Rates.Sources
+	want := xp.Grant(rates[config.XPSourceBuyOns], 0) + xp.Grant(rates[config.XPSourceBuyOns], 1) +
+		xp.Grant(rates[config.XPSourceExchangeBoosts], 0)
+	assert.Equal(t, want, eng.State.Player.XP, "granted at once")
+	assert.Equal(t, 2, eng.State.Player.XPSources[config.XPSourceBuyOns])
+
+	got := xpEvents(eng.Tick(0.1))
+	assert.Equal(t, want-xp.Grant(rates[config.XPSourceExchangeBoosts], 0), got[config.XPSourceBuyOns])
+	assert.Positive(t, got[config.XPSourceExchangeBoosts])
+	assert.Empty(t, xpEvents(eng.Tick(0.1)), "reported once")
+}
+
+func TestGainXP_ExchangeBoostSpamRunsDry(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	eng.State.Worlds["terra"].Coins = 1e9
+	boost := func(n int) {
+		for range n {
+			_, ok := eng.ExecuteExchangeBoost("terra")
+			require.True(t, ok, "the balance never runs out")
+			eng.State.Worlds["terra"].BoostCooldown = 0
+		}
+	}
+
+	boost(500)
+	earned := eng.State.Player.XP
+	assert.Positive(t, earned)
+	boost(200)
+	assert.Equal(t, earned, eng.State.Player.XP, "XP stops growing")
+}
+
+func TestGainXP_CoinsEarnedAndMilestones(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	eng.State.Player.WorldTotalCoinsEarned["terra"] = 600
+	eng.State.Player.WorldTotalCoinsEarned["aqua"] = 500
+
+	got := xpEvents(eng.Tick(0.1))
+	assert.Equal(t, 3, eng.State.Player.XPSources[config.XPSourceCoinsEarned], "1,100 coins over all worlds")
+	assert.Positive(t, got[config.XPSourceCoinsEarned])
+	assert.Empty(t, xpEvents(eng.Tick(0.1)))
+
+	eng.HandleClick("terra")
+	events := eng.CheckUnlocks()
+	require.NotZero(t, countEvents(events, EventMilestoneReached), "first click")
+	assert.Positive(t, xpEvents(eng.Tick(0.1))[config.XPSourceMilestones])
+}
+
+func TestXPMultiplier_RaisedByPerks(t *testing.T) {
+	eng := newTestEngine(t)
+	assert.Equal(t, 1.0, eng.XPMultiplier())
+	eng.State.Player.Perks = map[string]int{"scholar": 2}
+	assert.InDelta(t, 1.2, eng.XPMultiplier(), 1e-9)
+
+	eng.gainXP(config.XPSourceExchangeBoosts, 1)
+	assert.Equal(t, xp.Apply(xp.Grant(xp.Rates.Sources[config.XPSourceExchangeBoosts], 0), 1.2), eng.State.Player.XP)
+}
+
+func TestGrantXP_PaysLevelRewards(t *testing.T) {
+	eng := newTestEngine(t)
+	p := &eng.State.Player
+	target := level.Rewards[1].Level
+
+	require.True(t, eng.grantXP(player.XPForLevel(target)))
+	assert.Equal(t, target, p.Level)
+	want := 0.0
+	for _, r := range level.Between(level.Rewards, 1, target) {
+		want += r.GeneralCoins
+	}
+	assert.Equal(t, want, p.GeneralCoins, "every level passed pays out")
+	assert.Equal(t, want, p.LifetimeGeneralCoins)
+
+	assert.False(t, eng.grantXP(1))
+	assert.Equal(t, want, p.GeneralCoins, "paid once")
+}
+
+func TestGainXP_LevelUpEventCarriesPreviousLevel(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.Clock = nil
+	eng.State.Player.XP = player.XPForLevel(2) - 1
+
+	eng.gainXP(config.XPSourceMilestones, 1)
+	for _, ev := range eng.Tick(0.1) {
+		if ev.Type == EventLevelUp {
+			assert.Equal(t, 1, ev.PrevLevel)
+			assert.Equal(t, 2, ev.NewLevel)
+			return
+		}
+	}
+	t.Fatal("no level up")
+}
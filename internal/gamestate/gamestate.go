@@ -4,7 +4,13 @@
 package gamestate
 
 import (
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/clock"
+	"github.com/clicker-org/clicker/internal/market"
 	"github.com/clicker-org/clicker/internal/player"
+	"github.com/clicker-org/clicker/internal/runs"
+	"github.com/clicker-org/clicker/internal/streak"
 	"github.com/clicker-org/clicker/internal/world"
 )
 
@@ -15,6 +21,18 @@ type GameState struct {
 	LastScreen    string
 	LastWorldID   string
 	ActiveWorldID string
+	// Clock holds the persisted clock bookkeeping used for tamper detection.
+	Clock clock.Record
+	// AutoBuyer holds the auto-buyer's settings.
+	AutoBuyer autobuy.Settings
+	// Challenges holds the daily and session challenges.
+	Challenges challenge.State
+	// Streak holds the daily login streak.
+	Streak streak.State
+	// Runs holds the challenge runs in progress and each world's records.
+	Runs runs.State
+	// Market holds the exchange market's prices.
+	Market market.State
 }
 
 // NewGameState returns a freshly initialized GameState with no worlds.
@@ -25,5 +43,6 @@ func NewGameState() GameState {
 		LastScreen:    "overview",
 		LastWorldID:   "",
 		ActiveWorldID: "",
+		AutoBuyer:     autobuy.DefaultSettings(),
 	}
 }
@@ -0,0 +1,144 @@
+// Package level holds the account level curve and the rewards for reaching
+// each level, both defined in configs/levels.toml (config.LevelsConfig). The
+// XP thresholds of the curve are worked out once and cached. The player
+// package levels up against Default, and the engine grants the rewards (see
+// engine.Engine.grantXP). It has no Bubble Tea imports.
+package level
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/clicker-org/clicker/configs"
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/economy"
+)
+
+// MaxLevel is the highest level a curve has thresholds for. A curve also
+// stops early at a level whose threshold would overflow; levels past the end
+// of a curve cannot be reached.
+const MaxLevel = 1000
+
+// Default is the level curve, and Rewards the level rewards table, loaded
+// from the embedded configs/levels.toml.
+var (
+	Default Curve
+	Rewards []config.LevelRewardConfig
+)
+
+func init() {
+	var cfg config.LevelsConfig
+	if _, err := toml.NewDecoder(bytes.NewReader(configs.LevelsToml)).Decode(&cfg); err != nil {
+		log.Panicf("level: failed to load levels config: %v", err)
+	}
+	Default = NewCurve(cfg.Curve)
+	Rewards = cfg.Rewards
+}
+
+// Curve is a level curve with its cumulative XP thresholds cached.
+type Curve struct {
+	// thresholds[i] is the total XP needed to reach level i+1.
+	thresholds []int
+}
+
+// NewCurve works out the thresholds of the curve cfg.
+func NewCurve(cfg config.LevelCurveConfig) Curve {
+	thresholds := []int{0}
+	total := 0
+	step := cfg.Base
+	for n := 1; n < MaxLevel && step < math.MaxInt/2; n++ {
+		cost := int(step)
+		if n <= len(cfg.Steps) {
+			cost = cfg.Steps[n-1]
+		}
+		cost = max(cost, 1)
+		if total > math.MaxInt/2-cost {
+			break
+		}
+		total += cost
+		thresholds = append(thresholds, total)
+		step *= cfg.Growth
+	}
+	return Curve{thresholds: thresholds}
+}
+
+// XPFor returns the total XP needed to reach level n from level 1, or
+// math.MaxInt if the curve does not reach n.
+func (c Curve) XPFor(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	if n > len(c.thresholds) {
+		return math.MaxInt
+	}
+	return c.thresholds[n-1]
+}
+
+// LevelFor returns the level reached with xp.
+func (c Curve) LevelFor(xp int) int {
+	return max(sort.Search(len(c.thresholds), func(i int) bool { return c.thresholds[i] > xp }), 1)
+}
+
+// Between returns the rewards of rewards for the levels above from up to and
+// including to.
+func Between(rewards []config.LevelRewardConfig, from, to int) []config.LevelRewardConfig {
+	var out []config.LevelRewardConfig
+	for _, r := range rewards {
+		if r.Level > from && r.Level <= to {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Upcoming returns up to n rewards of rewards for levels above lvl, nearest
+// first.
+func Upcoming(rewards []config.LevelRewardConfig, lvl, n int) []config.LevelRewardConfig {
+	out := Between(rewards, lvl, math.MaxInt)
+	return out[:min(n, len(out))]
+}
+
+// PerkPoints returns the bonus perk points of rewards earned by reaching
+// lvl.
+func PerkPoints(rewards []config.LevelRewardConfig, lvl int) int {
+	n := 0
+	for _, r := range Between(rewards, 1, lvl) {
+		n += r.PerkPoints
+	}
+	return n
+}
+
+// Cosmetics returns the names of the cosmetics of rewards unlocked by
+// reaching lvl, in level order.
+func Cosmetics(rewards []config.LevelRewardConfig, lvl int) []string {
+	var out []string
+	for _, r := range Between(rewards, 1, lvl) {
+		if r.Cosmetic != "" {
+			out = append(out, r.Cosmetic)
+		}
+	}
+	return out
+}
+
+// Describe returns what r grants, e.g. "+50 GC, +1 perk point, Bronze badge".
+func Describe(r config.LevelRewardConfig) string {
+	var parts []string
+	if r.GeneralCoins > 0 {
+		parts = append(parts, "+"+economy.FormatCoinsBare(r.GeneralCoins)+" GC")
+	}
+	switch {
+	case r.PerkPoints == 1:
+		parts = append(parts, "+1 perk point")
+	case r.PerkPoints > 1:
+		parts = append(parts, fmt.Sprintf("+%d perk points", r.PerkPoints))
+	}
+	if r.Cosmetic != "" {
+		parts = append(parts, r.Cosmetic)
+	}
+	return strings.Join(parts, ", ")
+}
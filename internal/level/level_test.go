@@ -0,0 +1,75 @@
+package level
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/configs"
+	"github.com/clicker-org/clicker/internal/config"
+)
+
+var testRewards = []config.LevelRewardConfig{
+	{Level: 2, GeneralCoins: 10},
+	{Level: 5, PerkPoints: 1, Cosmetic: "Badge"},
+	{Level: 8, GeneralCoins: 100, PerkPoints: 2},
+}
+
+func TestDefault_IsValid(t *testing.T) {
+	var cfg config.LevelsConfig
+	_, err := toml.NewDecoder(bytes.NewReader(configs.LevelsToml)).Decode(&cfg)
+	require.NoError(t, err)
+	assert.Empty(t, config.ValidateLevels(cfg))
+	assert.Equal(t, 1318, Default.XPFor(6), "configs/levels.toml keeps the original curve")
+}
+
+func TestCurve_Formula(t *testing.T) {
+	c := NewCurve(config.LevelCurveConfig{Base: 100, Growth: 1.5})
+	assert.Equal(t, 0, c.XPFor(1))
+	assert.Equal(t, 100, c.XPFor(2))
+	assert.Equal(t, 250, c.XPFor(3))
+	assert.Equal(t, 1318, c.XPFor(6)) // 100+150+225+337+506
+
+	assert.Equal(t, 1, c.LevelFor(0))
+	assert.Equal(t, 1, c.LevelFor(99))
+	assert.Equal(t, 2, c.LevelFor(100))
+	assert.Equal(t, 5, c.LevelFor(1317))
+	assert.Equal(t, 6, c.LevelFor(1318))
+}
+
+func TestCurve_StepsOverrideFormula(t *testing.T) {
+	c := NewCurve(config.LevelCurveConfig{Base: 100, Growth: 2, Steps: []int{10, 20}})
+	assert.Equal(t, 10, c.XPFor(2))
+	assert.Equal(t, 30, c.XPFor(3))
+	assert.Equal(t, 30+400, c.XPFor(4), "the formula takes over after the table")
+}
+
+func TestCurve_EndsBeforeOverflow(t *testing.T) {
+	c := NewCurve(config.LevelCurveConfig{Base: 100, Growth: 10})
+	assert.Equal(t, math.MaxInt, c.XPFor(MaxLevel))
+	assert.Less(t, c.LevelFor(math.MaxInt), MaxLevel)
+
+	flat := NewCurve(config.LevelCurveConfig{Base: 1, Growth: 1})
+	assert.Equal(t, MaxLevel-1, flat.XPFor(MaxLevel))
+	assert.Equal(t, MaxLevel, flat.LevelFor(math.MaxInt))
+}
+
+func TestRewards(t *testing.T) {
+	assert.Len(t, Between(testRewards, 1, 5), 2)
+	assert.Empty(t, Between(testRewards, 5, 7))
+	assert.Equal(t, []config.LevelRewardConfig{testRewards[1]}, Upcoming(testRewards, 2, 1))
+	assert.Empty(t, Upcoming(testRewards, 8, 3))
+	assert.Equal(t, 0, PerkPoints(testRewards, 4))
+	assert.Equal(t, 3, PerkPoints(testRewards, 8))
+	assert.Equal(t, []string{"Badge"}, Cosmetics(testRewards, 9))
+}
+
+func TestDescribe(t *testing.T) {
+	assert.Equal(t, "+10 GC", Describe(testRewards[0]))
+	assert.Equal(t, "+1 perk point, Badge", Describe(testRewards[1]))
+	assert.Equal(t, "+100 GC, +2 perk points", Describe(testRewards[2]))
+}
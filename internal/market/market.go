@@ -0,0 +1,151 @@
+// Package market is the exchange market, where world coins trade for
+// general coins at prices that move over time: a random walk that reverts to
+// each world's exchange rate, hit now and then by shocks, driven by
+// configs/market.toml (config.MarketConfig). Prices move in steps of play
+// time, and each step's randomness is drawn from the state's seed, so a
+// state always moves the same way. It keeps the prices; the engine advances
+// them and makes the trades (see engine.Engine.SellWorldCoins). It has no
+// Bubble Tea imports.
+package market
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"math/rand/v2"
+
+	"github.com/BurntSushi/toml"
+	"github.com/clicker-org/clicker/configs"
+	"github.com/clicker-org/clicker/internal/config"
+)
+
+// MaxGap bounds how far a price strays from its fair price, on a log
+// scale: prices stay between a tenth and ten times the fair price.
+const MaxGap = math.Ln10
+
+// Params are the market parameters, loaded from the embedded
+// configs/market.toml.
+var Params config.MarketConfig
+
+func init() {
+	if _, err := toml.NewDecoder(bytes.NewReader(configs.MarketToml)).Decode(&Params); err != nil {
+		log.Panicf("market: failed to load market config: %v", err)
+	}
+}
+
+// Quote is the market for one world coin.
+type Quote struct {
+	// Gap is the log of the price over the fair price.
+	Gap float64 `json:"gap"`
+	// History holds the prices at the latest steps, oldest first.
+	History []float64 `json:"history,omitempty"`
+	// Shock is the name of the last shock to hit the coin, and ShockStep the
+	// step it hit on.
+	Shock     string `json:"shock,omitempty"`
+	ShockStep int64  `json:"shock_step,omitempty"`
+}
+
+// Price returns the coin's price, in general coins, for the fair price fair.
+func (q Quote) Price(fair float64) float64 {
+	return fair * math.Exp(q.Gap)
+}
+
+// Change returns the relative change of the price over its history, e.g.
+// 0.1 for +10%, or 0 with fewer than two prices.
+func (q Quote) Change() float64 {
+	if len(q.History) < 2 || q.History[0] <= 0 {
+		return 0
+	}
+	return q.History[len(q.History)-1]/q.History[0] - 1
+}
+
+// Bid returns what the market pays for a coin at price under cfg.
+func Bid(cfg config.MarketConfig, price float64) float64 {
+	return price * (1 - cfg.Spread/2)
+}
+
+// Ask returns what the market charges for a coin at price under cfg.
+func Ask(cfg config.MarketConfig, price float64) float64 {
+	return price * (1 + cfg.Spread/2)
+}
+
+// State is the persisted exchange market.
+type State struct {
+	// Seed identifies the market in its random walk.
+	Seed string `json:"seed,omitempty"`
+	// Step is the number of steps taken.
+	Step int64 `json:"step"`
+	// Elapsed is the play time since the last step, in seconds.
+	Elapsed float64 `json:"elapsed"`
+	// Quotes holds each world coin's market, by world ID.
+	Quotes map[string]Quote `json:"quotes,omitempty"`
+}
+
+// Clone returns a deep copy of s.
+func (s State) Clone() State {
+	out := s
+	out.Quotes = make(map[string]Quote, len(s.Quotes))
+	for id, q := range s.Quotes {
+		q.History = append([]float64(nil), q.History...)
+		out.Quotes[id] = q
+	}
+	return out
+}
+
+// Hit is a shock that hit a world coin.
+type Hit struct {
+	WorldID string
+	Shock   config.MarketShockConfig
+}
+
+// Advance adds dt seconds of play to s and takes the steps that are due
+// under cfg, moving the coins of worlds in that order. fair returns a
+// world's fair price. It returns the shocks that hit.
+func (s *State) Advance(cfg config.MarketConfig, dt float64, worlds []string, fair func(worldID string) float64) []Hit {
+	if cfg.StepSeconds <= 0 {
+		return nil
+	}
+	var hits []Hit
+	s.Elapsed += dt
+	for s.Elapsed >= cfg.StepSeconds {
+		s.Elapsed -= cfg.StepSeconds
+		hits = append(hits, s.step(cfg, worlds, fair)...)
+	}
+	return hits
+}
+
+// step moves the coin of every world in worlds by one step.
+func (s *State) step(cfg config.MarketConfig, worlds []string, fair func(worldID string) float64) []Hit {
+	if s.Quotes == nil {
+		s.Quotes = make(map[string]Quote)
+	}
+	s.Step++
+	var hits []Hit
+	for _, id := range worlds {
+		q := s.Quotes[id]
+		rng := s.rng(id)
+		q.Gap = q.Gap*(1-cfg.Reversion) + cfg.Volatility*rng.NormFloat64()
+		if len(cfg.Shocks) > 0 && rng.Float64() < cfg.ShockChance {
+			sh := cfg.Shocks[rng.IntN(len(cfg.Shocks))]
+			q.Gap += math.Log(sh.Impact)
+			q.Shock, q.ShockStep = sh.Name, s.Step
+			hits = append(hits, Hit{WorldID: id, Shock: sh})
+		}
+		q.Gap = min(max(q.Gap, -MaxGap), MaxGap)
+		q.History = append(q.History, q.Price(fair(id)))
+		if n := len(q.History) - max(cfg.History, 1); n > 0 {
+			q.History = append(q.History[:0:0], q.History[n:]...)
+		}
+		s.Quotes[id] = q
+	}
+	return hits
+}
+
+// rng returns the random source of the current step for worldID's coin.
+func (s *State) rng(worldID string) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%d|%s", s.Seed, s.Step, worldID)
+	return rand.New(rand.NewPCG(h.Sum64(), 0))
+}
@@ -0,0 +1,82 @@
+package market
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/config"
+)
+
+var worlds = []string{"aqua", "terra"}
+
+func fair(string) float64 { return 0.001 }
+
+func TestParams_Valid(t *testing.T) {
+	assert.Empty(t, config.ValidateMarket(Params))
+}
+
+func TestAdvance_StepsEveryStepSeconds(t *testing.T) {
+	var s State
+	s.Advance(Params, Params.StepSeconds*2.5, worlds, fair)
+	assert.Equal(t, int64(2), s.Step)
+	assert.InDelta(t, Params.StepSeconds/2, s.Elapsed, 1e-9)
+	assert.Len(t, s.Quotes["terra"].History, 2)
+}
+
+func TestAdvance_SameSeedSamePrices(t *testing.T) {
+	a, b, c := State{Seed: "x"}, State{Seed: "x"}, State{Seed: "y"}
+	for range 50 {
+		a.Advance(Params, 1, worlds, fair)
+	}
+	b.Advance(Params, 50, worlds, fair)
+	c.Advance(Params, 50, worlds, fair)
+	assert.Equal(t, a.Quotes, b.Quotes, "one long advance moves as many short ones")
+	assert.NotEqual(t, a.Quotes["terra"].History, c.Quotes["terra"].History)
+	assert.NotEqual(t, a.Quotes["terra"].History, a.Quotes["aqua"].History, "each coin walks on its own")
+}
+
+func TestAdvance_RevertsToFairPrice(t *testing.T) {
+	cfg := Params
+	cfg.Volatility, cfg.ShockChance = 0, 0
+	s := State{Quotes: map[string]Quote{"terra": {Gap: math.Log(2)}}}
+	s.Advance(cfg, cfg.StepSeconds*100, []string{"terra"}, fair)
+	q := s.Quotes["terra"]
+	assert.InDelta(t, math.Log(2)*math.Pow(1-cfg.Reversion, 100), q.Gap, 1e-9)
+	assert.Less(t, q.Change(), 0.0)
+	assert.InDelta(t, 0.001, q.Price(0.001), 0.0001)
+}
+
+func TestAdvance_ShocksAndHistoryCap(t *testing.T) {
+	cfg := Params
+	cfg.Volatility, cfg.Reversion, cfg.ShockChance, cfg.History = 0, 0, 1, 5
+	cfg.Shocks = []config.MarketShockConfig{{Name: "Boom", Impact: 1.5}}
+	var s State
+	hits := s.Advance(cfg, cfg.StepSeconds*10, []string{"terra"}, fair)
+	require.Len(t, hits, 10)
+	assert.Equal(t, Hit{WorldID: "terra", Shock: cfg.Shocks[0]}, hits[0])
+
+	q := s.Quotes["terra"]
+	assert.Equal(t, MaxGap, q.Gap, "prices stay within ten times the fair price")
+	assert.Equal(t, "Boom", q.Shock)
+	assert.Equal(t, int64(10), q.ShockStep)
+	assert.Len(t, q.History, 5)
+	assert.InDelta(t, 0.01, q.History[4], 1e-9)
+}
+
+func TestBidAsk(t *testing.T) {
+	cfg := config.MarketConfig{Spread: 0.1}
+	assert.InDelta(t, 0.95, Bid(cfg, 1), 1e-9)
+	assert.InDelta(t, 1.05, Ask(cfg, 1), 1e-9)
+}
+
+func TestClone_IsDeep(t *testing.T) {
+	s := State{Quotes: map[string]Quote{"terra": {History: []float64{1, 2}}}}
+	c := s.Clone()
+	c.Quotes["terra"].History[0] = 9
+	c.Quotes["aqua"] = Quote{}
+	assert.Equal(t, 1.0, s.Quotes["terra"].History[0])
+	assert.NotContains(t, s.Quotes, "aqua")
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/perk"
 	"github.com/clicker-org/clicker/internal/world"
 )
 
@@ -26,55 +27,108 @@ const (
 	MinReportDuration = 60 * time.Second
 )
 
+// WorldResult holds the offline outcome for a single world.
+type WorldResult struct {
+	WorldID string
+	Coins   float64
+	// CapHours is the effective offline cap for this world.
+	CapHours float64
+	// CapHit is true if the player was away longer than CapHours. CappedAfter
+	// is how long into the offline session the cap was reached and Wasted is
+	// the offline time that earned nothing because of it.
+	CapHit      bool
+	CappedAfter time.Duration
+	Wasted      time.Duration
+	// Milestones lists completion milestones reached because of the offline
+	// earnings. Populated by the engine after Apply.
+	Milestones []string
+}
+
 // Result holds the outcome of an offline income calculation.
 type Result struct {
-	Duration    time.Duration
-	WorldCoins  float64
+	Duration time.Duration
+	// WorldCoins is the income of the world the player quit from (WorldID).
+	WorldCoins   float64
 	GeneralCoins float64
-	WorldID     string
+	WorldID      string
+	// Worlds holds the breakdown of the world that earned offline income, if
+	// any.
+	Worlds []WorldResult
+	// GeneralCoinsCapped is true if the overview trickle hit OverviewOfflineGCCap.
+	GeneralCoinsCapped bool
+
+	// ClockAnomaly is set when the system clock looked wrong on launch;
+	// ClockPolicy is the policy that was applied to the offline duration.
+	ClockAnomaly ClockAnomaly
+	ClockPolicy  ClockPolicy
+
+	// Achievements and level-ups unlocked by the offline earnings. Populated
+	// by the engine after Apply.
+	Achievements []string
+	PrevLevel    int
+	NewLevel     int
 }
 
+// LeveledUp reports whether the offline earnings led to at least one level-up.
+func (r Result) LeveledUp() bool { return r.NewLevel > r.PrevLevel }
+
 // Apply computes offline income based on how long the game was closed and
 // where the player was when they quit, then applies the earned amounts
-// directly to gs. Returns the Result for display in the offline report.
-//
-// For world-screen sessions, world-specific offline settings are sourced from
-// worldReg. If a world is missing from the registry, engine-level defaults are
-// used as a fallback.
+// directly to gs. It uses the system clock and the default clock policy; see
+// ApplySession for full control.
 func Apply(lastScreen, lastWorldID string, savedAt time.Time, gs *gamestate.GameState, worldReg *world.WorldRegistry) Result {
-	if savedAt.IsZero() {
+	return ApplySession(Session{
+		LastScreen:  lastScreen,
+		LastWorldID: lastWorldID,
+		SavedAt:     savedAt,
+	}, gs, worldReg)
+}
+
+// ApplySession computes offline income for sess and applies it to gs.
+// Returns the Result for display in the offline report.
+//
+// Quitting from a world keeps that world earning a fraction of its CPS while
+// offline, up to its cap; quitting from anywhere else yields a general-coin
+// trickle instead. World-specific offline settings are sourced from worldReg;
+// if a world is missing from the registry, engine-level defaults are used.
+//
+// The elapsed time is validated with CheckClock, and any clock anomaly is
+// reported in the Result together with the policy that was applied.
+func ApplySession(sess Session, gs *gamestate.GameState, worldReg *world.WorldRegistry) Result {
+	if sess.SavedAt.IsZero() {
 		return Result{}
 	}
-	elapsed := time.Since(savedAt).Seconds()
-	if elapsed <= 0 {
-		return Result{}
+	now := time.Now
+	if sess.Now != nil {
+		now = sess.Now
 	}
+	policy := ParseClockPolicy(string(sess.Policy))
+	perkHours := perk.Compute(perk.Tree, gs.Player.Perks).OfflineCapHours
+	limit := offlineLimit(sess, gs, worldReg, perkHours)
+	d, anomaly := CheckClock(sess.SavedAt, sess.LastWall, now(), limit, policy)
 
 	result := Result{
-		Duration: time.Duration(elapsed * float64(time.Second)),
-		WorldID:  lastWorldID,
+		Duration:     d,
+		WorldID:      sess.LastWorldID,
+		ClockAnomaly: anomaly,
+		ClockPolicy:  policy,
+	}
+	elapsed := d.Seconds()
+	if elapsed <= 0 {
+		return result
 	}
 
-	if lastScreen == "world" && lastWorldID != "" {
-		if ws, ok := gs.Worlds[lastWorldID]; ok {
-			offlinePct := WorldOfflinePct
-			capHours := WorldOfflineCapHours
-			if worldReg != nil {
-				if w, ok := worldReg.Get(lastWorldID); ok {
-					if p := w.OfflinePercentage(); p > 0 {
-						offlinePct = p
-					}
-					if baseCap := w.OfflineCapHours(); baseCap > 0 {
-						capHours = world.EffectiveOfflineCapHours(ws, baseCap)
-					}
+	if inWorld(sess) {
+		if ws, ok := gs.Worlds[sess.LastWorldID]; ok && ws.CPS > 0 {
+			wr := applyWorld(ws, elapsed, worldReg, perkHours)
+			if wr.Coins > 0 {
+				if gs.Player.WorldTotalCoinsEarned == nil {
+					gs.Player.WorldTotalCoinsEarned = make(map[string]float64)
 				}
+				gs.Player.WorldTotalCoinsEarned[ws.WorldID] += wr.Coins
 			}
-			coins := CalculateOfflineIncome(ws.CPS, offlinePct, elapsed, capHours)
-			if coins > 0 {
-				ws.Coins += coins
-				ws.TotalCoinsEarned += coins
-			}
-			result.WorldCoins = coins
+			result.WorldCoins = wr.Coins
+			result.Worlds = append(result.Worlds, wr)
 		}
 	} else {
 		gc := CalculateOverviewOfflineIncome(OverviewOfflineBaseRate, elapsed, OverviewOfflineGCCap)
@@ -82,11 +136,76 @@ func Apply(lastScreen, lastWorldID string, savedAt time.Time, gs *gamestate.Game
 			gs.Player.GeneralCoins += gc
 		}
 		result.GeneralCoins = gc
+		result.GeneralCoinsCapped = gc >= OverviewOfflineGCCap
 	}
 
 	return result
 }
 
+// applyWorld credits offline income to a single world and describes it.
+// perkHours is added to the world's offline cap by the player's perks.
+func applyWorld(ws *world.WorldState, elapsed float64, worldReg *world.WorldRegistry, perkHours float64) WorldResult {
+	offlinePct, capHours := worldOffline(ws, worldReg, perkHours)
+	coins := CalculateOfflineIncome(ws.CPS, offlinePct, elapsed, capHours)
+	if coins > 0 {
+		ws.Coins += coins
+		ws.TotalCoinsEarned += coins
+	}
+
+	wr := WorldResult{
+		WorldID:  ws.WorldID,
+		Coins:    coins,
+		CapHours: capHours,
+	}
+	capSecs := capHours * 3600
+	if elapsed > capSecs {
+		wr.CapHit = true
+		wr.CappedAfter = time.Duration(capSecs * float64(time.Second))
+		wr.Wasted = time.Duration((elapsed - capSecs) * float64(time.Second))
+	}
+	return wr
+}
+
+// worldOffline returns the fraction of ws's CPS earned offline and its
+// effective offline cap in hours, perkHours included.
+func worldOffline(ws *world.WorldState, worldReg *world.WorldRegistry, perkHours float64) (offlinePct, capHours float64) {
+	offlinePct = WorldOfflinePct
+	capHours = WorldOfflineCapHours
+	if worldReg != nil {
+		if w, ok := worldReg.Get(ws.WorldID); ok {
+			if p := w.OfflinePercentage(); p > 0 {
+				offlinePct = p
+			}
+			if baseCap := w.OfflineCapHours(); baseCap > 0 {
+				capHours = world.EffectiveOfflineCapHours(ws, baseCap)
+			}
+		}
+	}
+	return offlinePct, capHours + perkHours
+}
+
+// inWorld reports whether sess was quit from a world screen, so that the
+// world earns offline income rather than the overview trickle.
+func inWorld(sess Session) bool {
+	return sess.LastScreen == "world" && sess.LastWorldID != ""
+}
+
+// offlineLimit returns the longest offline session that can still earn
+// anything: the effective cap of the world quit from, or the time the
+// overview trickle takes to fill. Zero means no limit is known. Past it,
+// CheckClock treats the session as a forward clock jump.
+func offlineLimit(sess Session, gs *gamestate.GameState, worldReg *world.WorldRegistry, perkHours float64) time.Duration {
+	if !inWorld(sess) {
+		return time.Duration(OverviewOfflineGCCap / OverviewOfflineBaseRate * float64(time.Second))
+	}
+	ws, ok := gs.Worlds[sess.LastWorldID]
+	if !ok || ws.CPS <= 0 {
+		return 0
+	}
+	_, capHours := worldOffline(ws, worldReg, perkHours)
+	return time.Duration(capHours * float64(time.Hour))
+}
+
 // CalculateOfflineIncome computes coins earned while the game was closed
 // for a world that was active when the player quit.
 //
@@ -0,0 +1,110 @@
+package offline
+
+import "time"
+
+// ClockPolicy decides how offline income is computed when the system clock
+// looks wrong.
+type ClockPolicy string
+
+const (
+	// ClockPolicyClamp measures the offline session from the latest
+	// trustworthy time and caps implausibly long sessions. This is the default.
+	ClockPolicyClamp ClockPolicy = "clamp"
+	// ClockPolicyIgnore grants no offline income when an anomaly is detected.
+	ClockPolicyIgnore ClockPolicy = "ignore"
+	// ClockPolicyFlag grants offline income as measured but reports the anomaly.
+	ClockPolicyFlag ClockPolicy = "flag"
+)
+
+// ParseClockPolicy returns the ClockPolicy named by s, falling back to
+// ClockPolicyClamp for empty or unknown values.
+func ParseClockPolicy(s string) ClockPolicy {
+	switch p := ClockPolicy(s); p {
+	case ClockPolicyClamp, ClockPolicyIgnore, ClockPolicyFlag:
+		return p
+	default:
+		return ClockPolicyClamp
+	}
+}
+
+// ClockAnomaly identifies a suspicious clock state detected on launch.
+type ClockAnomaly string
+
+const (
+	ClockOK ClockAnomaly = ""
+	// ClockFutureSave means the save was written later than the current time.
+	ClockFutureSave ClockAnomaly = "future_save"
+	// ClockBackwardJump means the clock is behind the latest time observed in
+	// a previous session, i.e. it was set back.
+	ClockBackwardJump ClockAnomaly = "backward_jump"
+	// ClockForwardJump means the offline session is longer than any offline
+	// cap, so part of it cannot have earned anything and the clock may have
+	// been moved forward.
+	ClockForwardJump ClockAnomaly = "forward_jump"
+)
+
+const (
+	// ClockSkewTolerance absorbs ordinary clock drift and NTP corrections.
+	ClockSkewTolerance = 5 * time.Minute
+	// MaxPlausibleOffline is the longest offline session accepted without
+	// being treated as a forward clock jump when CheckClock is given no
+	// shorter limit.
+	MaxPlausibleOffline = 30 * 24 * time.Hour
+)
+
+// Session describes the offline session to resolve on launch.
+type Session struct {
+	LastScreen  string
+	LastWorldID string
+	// SavedAt is the wall-clock time recorded when the save was written.
+	SavedAt time.Time
+	// LastWall is the high-water mark of trustworthy wall-clock time from the
+	// save (see clock.Record). Zero for saves that predate it.
+	LastWall time.Time
+	Policy   ClockPolicy
+	// Now returns the current wall-clock time. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// CheckClock classifies the clock state for an offline session and returns
+// the elapsed offline time to credit under the given policy. limit is the
+// longest session that can still earn anything, normally the longest
+// effective offline cap; sessions past it are treated as a forward jump.
+// A limit of zero or above MaxPlausibleOffline means MaxPlausibleOffline.
+func CheckClock(savedAt, lastWall, now time.Time, limit time.Duration, policy ClockPolicy) (time.Duration, ClockAnomaly) {
+	elapsed := now.Sub(savedAt)
+	if limit <= 0 || limit > MaxPlausibleOffline {
+		limit = MaxPlausibleOffline
+	}
+
+	var anomaly ClockAnomaly
+	switch {
+	case savedAt.Sub(now) > ClockSkewTolerance:
+		anomaly = ClockFutureSave
+	case !lastWall.IsZero() && lastWall.Sub(now) > ClockSkewTolerance:
+		anomaly = ClockBackwardJump
+	case elapsed > limit:
+		anomaly = ClockForwardJump
+	}
+
+	if anomaly == ClockOK {
+		return max(elapsed, 0), ClockOK
+	}
+
+	switch policy {
+	case ClockPolicyIgnore:
+		return 0, anomaly
+	case ClockPolicyFlag:
+		return max(elapsed, 0), anomaly
+	default:
+		// Measure from the latest trustworthy time, so setting the clock back
+		// and forth cannot credit the same hours twice.
+		ref := savedAt
+		if lastWall.After(ref) {
+			ref = lastWall
+		}
+		// past the limit nothing earns anyway, so the caps do the clamping
+		// and the report can still show the time that went to waste.
+		return min(max(now.Sub(ref), 0), MaxPlausibleOffline), anomaly
+	}
+}
@@ -0,0 +1,55 @@
+package offline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckClock(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		savedAt     time.Time
+		lastWall    time.Time
+		limit       time.Duration
+		policy      ClockPolicy
+		wantElapsed time.Duration
+		wantAnomaly ClockAnomaly
+	}{
+		{"normal", now.Add(-2 * time.Hour), now.Add(-2 * time.Hour), 0, ClockPolicyClamp, 2 * time.Hour, ClockOK},
+		{"legacy_save_without_last_wall", now.Add(-2 * time.Hour), time.Time{}, 0, ClockPolicyClamp, 2 * time.Hour, ClockOK},
+		{"small_skew_tolerated", now.Add(time.Minute), time.Time{}, 0, ClockPolicyClamp, 0, ClockOK},
+		{"future_save_clamp", now.Add(3 * time.Hour), now.Add(3 * time.Hour), 0, ClockPolicyClamp, 0, ClockFutureSave},
+		{"future_save_flag", now.Add(3 * time.Hour), time.Time{}, 0, ClockPolicyFlag, 0, ClockFutureSave},
+		// Clock set back after a session that observed a later time: credit
+		// only time since the high-water mark, which is nothing yet.
+		{"backward_clamp", now.Add(-time.Hour), now.Add(2 * time.Hour), 0, ClockPolicyClamp, 0, ClockBackwardJump},
+		{"backward_flag", now.Add(-time.Hour), now.Add(2 * time.Hour), 0, ClockPolicyFlag, time.Hour, ClockBackwardJump},
+		{"backward_ignore", now.Add(-time.Hour), now.Add(2 * time.Hour), 0, ClockPolicyIgnore, 0, ClockBackwardJump},
+		{"forward_clamp", now.Add(-60 * 24 * time.Hour), time.Time{}, 0, ClockPolicyClamp, MaxPlausibleOffline, ClockForwardJump},
+		{"forward_ignore", now.Add(-60 * 24 * time.Hour), time.Time{}, 0, ClockPolicyIgnore, 0, ClockForwardJump},
+		{"forward_flag", now.Add(-60 * 24 * time.Hour), time.Time{}, 0, ClockPolicyFlag, 60 * 24 * time.Hour, ClockForwardJump},
+		// A day away when nothing earns past an 8h cap: the clock may have
+		// been moved forward a day.
+		{"within_limit", now.Add(-6 * time.Hour), time.Time{}, 8 * time.Hour, ClockPolicyClamp, 6 * time.Hour, ClockOK},
+		{"forward_day_clamp", now.Add(-24 * time.Hour), now.Add(-24 * time.Hour), 8 * time.Hour, ClockPolicyClamp, 24 * time.Hour, ClockForwardJump},
+		{"forward_day_ignore", now.Add(-24 * time.Hour), time.Time{}, 8 * time.Hour, ClockPolicyIgnore, 0, ClockForwardJump},
+		{"forward_day_flag", now.Add(-24 * time.Hour), time.Time{}, 8 * time.Hour, ClockPolicyFlag, 24 * time.Hour, ClockForwardJump},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			elapsed, anomaly := CheckClock(tc.savedAt, tc.lastWall, now, tc.limit, tc.policy)
+			assert.Equal(t, tc.wantAnomaly, anomaly)
+			assert.Equal(t, tc.wantElapsed, elapsed)
+		})
+	}
+}
+
+func TestParseClockPolicy(t *testing.T) {
+	assert.Equal(t, ClockPolicyIgnore, ParseClockPolicy("ignore"))
+	assert.Equal(t, ClockPolicyFlag, ParseClockPolicy("flag"))
+	assert.Equal(t, ClockPolicyClamp, ParseClockPolicy(""))
+	assert.Equal(t, ClockPolicyClamp, ParseClockPolicy("bogus"))
+}
@@ -0,0 +1,118 @@
+// Package perk evaluates the account perk tree: perks defined in
+// configs/perks.toml (config.PerkConfig), bought with the perk point the
+// player earns for every level and the bonus points of the level rewards,
+// and kept through every kind of reset. It works out which perks can be
+// bought, what the owned ranks add up to and what a respec costs; the engine
+// spends the points and applies the effects (see engine.Engine.BuyPerk). It
+// has no Bubble Tea imports.
+package perk
+
+import (
+	"bytes"
+	"log"
+	"slices"
+
+	"github.com/BurntSushi/toml"
+	"github.com/clicker-org/clicker/configs"
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/level"
+)
+
+// RespecCostPerPoint is the general coins a respec costs per perk point it
+// refunds.
+const RespecCostPerPoint = 50.0
+
+// Tree is the account perk tree, loaded from the embedded configs/perks.toml.
+var Tree []config.PerkConfig
+
+func init() {
+	var cfg config.PerksConfig
+	if _, err := toml.NewDecoder(bytes.NewReader(configs.PerksToml)).Decode(&cfg); err != nil {
+		log.Panicf("perk: failed to load perks config: %v", err)
+	}
+	Tree = cfg.Perks
+}
+
+// Effects are the combined effects of the owned perk ranks.
+type Effects struct {
+	// OfflineCapHours is added to every world's offline cap.
+	OfflineCapHours float64
+	// CPSBonus, ClickBonus, ExchangeBonus and XPBonus are added to their
+	// multipliers, e.g. 0.1 for +10%.
+	CPSBonus      float64
+	ClickBonus    float64
+	ExchangeBonus float64
+	XPBonus       float64
+}
+
+// Compute returns the combined effects of ranks, the rank owned of each
+// perk in tree.
+func Compute(tree []config.PerkConfig, ranks map[string]int) Effects {
+	var e Effects
+	for _, p := range tree {
+		v := p.Value * float64(min(ranks[p.ID], p.MaxRank))
+		switch p.Effect {
+		case config.PerkEffectOfflineCap:
+			e.OfflineCapHours += v
+		case config.PerkEffectCPSBonus:
+			e.CPSBonus += v
+		case config.PerkEffectClickBonus:
+			e.ClickBonus += v
+		case config.PerkEffectExchangeBonus:
+			e.ExchangeBonus += v
+		case config.PerkEffectXPBonus:
+			e.XPBonus += v
+		}
+	}
+	return e
+}
+
+// Find returns the perk id of tree.
+func Find(tree []config.PerkConfig, id string) (config.PerkConfig, bool) {
+	i := slices.IndexFunc(tree, func(p config.PerkConfig) bool { return p.ID == id })
+	if i < 0 {
+		return config.PerkConfig{}, false
+	}
+	return tree[i], true
+}
+
+// Earned returns the perk points a player of level lvl has earned: one per
+// level above the first, plus those of the level rewards reached (see
+// level.Rewards).
+func Earned(lvl int) int {
+	return max(lvl-1, 0) + level.PerkPoints(level.Rewards, lvl)
+}
+
+// Spent returns the perk points ranks cost.
+func Spent(tree []config.PerkConfig, ranks map[string]int) int {
+	total := 0
+	for _, p := range tree {
+		total += p.Cost * min(ranks[p.ID], p.MaxRank)
+	}
+	return total
+}
+
+// Unlocked reports whether every perk p requires has a rank.
+func Unlocked(p config.PerkConfig, ranks map[string]int) bool {
+	return !slices.ContainsFunc(p.Requires, func(r string) bool { return ranks[r] == 0 })
+}
+
+// Available returns the perk points left to spend by a player of level lvl
+// who owns ranks.
+func Available(lvl int, ranks map[string]int) int {
+	return Earned(lvl) - Spent(Tree, ranks)
+}
+
+// CanBuy reports whether a rank of the perk id can be bought with points:
+// it exists, is unlocked, is below its max rank and costs no more than
+// points.
+func CanBuy(tree []config.PerkConfig, ranks map[string]int, points int, id string) bool {
+	p, ok := Find(tree, id)
+	return ok && Unlocked(p, ranks) && ranks[id] < p.MaxRank && p.Cost <= points
+}
+
+// RespecCost returns the general coins refunding every point spent on
+// ranks costs.
+func RespecCost(tree []config.PerkConfig, ranks map[string]int) float64 {
+	return RespecCostPerPoint * float64(Spent(tree, ranks))
+}
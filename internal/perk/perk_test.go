@@ -0,0 +1,54 @@
+package perk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/level"
+)
+
+var testTree = []config.PerkConfig{
+	{ID: "grip", Cost: 1, MaxRank: 3, Effect: config.PerkEffectClickBonus, Value: 0.1},
+	{ID: "naps", Cost: 1, MaxRank: 2, Effect: config.PerkEffectOfflineCap, Value: 2},
+	{ID: "trade", Cost: 2, MaxRank: 1, Requires: []string{"grip"}, Effect: config.PerkEffectExchangeBonus, Value: 0.05},
+}
+
+func TestTree_IsValid(t *testing.T) {
+	assert.NotEmpty(t, Tree)
+	assert.Empty(t, config.ValidatePerks(config.PerksConfig{Perks: Tree}))
+}
+
+func TestCompute(t *testing.T) {
+	e := Compute(testTree, map[string]int{"grip": 2, "naps": 5, "trade": 1})
+	assert.InDelta(t, 0.2, e.ClickBonus, 1e-9)
+	assert.InDelta(t, 4, e.OfflineCapHours, 1e-9, "ranks beyond the max do not count")
+	assert.InDelta(t, 0.05, e.ExchangeBonus, 1e-9)
+	assert.Zero(t, e.CPSBonus)
+	assert.Zero(t, e.XPBonus)
+}
+
+func TestCanBuyAndSpent(t *testing.T) {
+	ranks := map[string]int{}
+	assert.True(t, CanBuy(testTree, ranks, 1, "grip"))
+	assert.False(t, CanBuy(testTree, ranks, 0, "grip"), "no points")
+	assert.False(t, CanBuy(testTree, ranks, 9, "trade"), "needs grip first")
+	assert.False(t, CanBuy(testTree, ranks, 9, "missing"))
+
+	ranks["grip"] = 3
+	assert.False(t, CanBuy(testTree, ranks, 9, "grip"), "at max rank")
+	assert.True(t, CanBuy(testTree, ranks, 2, "trade"))
+
+	ranks["trade"] = 1
+	assert.Equal(t, 5, Spent(testTree, ranks))
+	assert.Equal(t, 250.0, RespecCost(testTree, ranks))
+}
+
+func TestEarned(t *testing.T) {
+	assert.Equal(t, 0, Earned(1))
+	assert.Equal(t, 4, Earned(5))
+	assert.Equal(t, 0, Earned(0))
+	assert.Equal(t, 7+level.PerkPoints(level.Rewards, 8), Earned(8), "level rewards add points")
+	assert.Positive(t, level.PerkPoints(level.Rewards, 8))
+}
@@ -9,6 +9,11 @@ type Player struct {
 	TotalPlaySeconds     float64            `json:"total_play_seconds"`
 	LifetimeGeneralCoins float64            `json:"lifetime_general_coins"`
 	WorldTotalCoinsEarned map[string]float64 `json:"world_total_coins_earned"`
+	// Perks is the rank owned of each account perk (see internal/perk).
+	Perks map[string]int `json:"perks"`
+	// XPSources is how many XP grants each gameplay source has made (see
+	// internal/xp), for their diminishing returns.
+	XPSources map[string]int `json:"xp_sources"`
 }
 
 // NewPlayer returns a freshly initialized player.
@@ -21,5 +26,7 @@ func NewPlayer() Player {
 		TotalPlaySeconds:      0,
 		LifetimeGeneralCoins:  0,
 		WorldTotalCoinsEarned: make(map[string]float64),
+		Perks:                 make(map[string]int),
+		XPSources:             make(map[string]int),
 	}
 }
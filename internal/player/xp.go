@@ -1,18 +1,11 @@
 package player
 
-// XPForLevel returns the cumulative XP required to reach level n from level 1.
-// Formula per level step: cost(n→n+1) = int(100 * 1.5^(n-1))
+import "github.com/clicker-org/clicker/internal/level"
+
+// XPForLevel returns the cumulative XP required to reach level n from level 1
+// on the level curve in configs/levels.toml (see level.Default).
 func XPForLevel(n int) int {
-	if n <= 1 {
-		return 0
-	}
-	total := 0
-	xp := 100.0
-	for i := 1; i < n; i++ {
-		total += int(xp)
-		xp *= 1.5
-	}
-	return total
+	return level.Default.XPFor(n)
 }
 
 // XPNeededForNextLevel returns XP needed to advance from level n to n+1.
@@ -24,12 +17,11 @@ func XPNeededForNextLevel(n int) int {
 // Returns true if at least one level-up occurred.
 func AddXP(p *Player, xp int) bool {
 	p.XP += xp
-	leveled := false
-	for p.XP >= XPForLevel(p.Level+1) {
-		p.Level++
-		leveled = true
+	if l := level.Default.LevelFor(p.XP); l > p.Level {
+		p.Level = l
+		return true
 	}
-	return leveled
+	return false
 }
 
 // LevelGateCheck returns true if the player meets the required level.
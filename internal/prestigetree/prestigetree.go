@@ -0,0 +1,140 @@
+// Package prestigetree evaluates a world's prestige upgrade tree: the nodes
+// defined in the world's config (config.PrestigeNodeConfig), bought with the
+// world's prestige points and kept across its prestiges. It works out which
+// nodes can be bought, what the owned ones add up to, and how the tree is
+// laid out for display; the engine spends the points and applies the
+// effects (see engine.Engine.BuyPrestigeNode). It has no Bubble Tea imports.
+package prestigetree
+
+import (
+	"slices"
+
+	"github.com/clicker-org/clicker/internal/config"
+)
+
+// MaxScalingCut caps the share of cost growth the tree can remove, so costs
+// always keep growing.
+const MaxScalingCut = 0.5
+
+// Effects are the combined effects of the owned nodes of a tree.
+type Effects struct {
+	// StartBuyOns is how many units of each buy-on a reset starts with.
+	StartBuyOns map[string]int
+	// ClickBonus and CPSBonus are added to the world's click and CPS
+	// multipliers, e.g. 0.5 for +50%.
+	ClickBonus float64
+	CPSBonus   float64
+	// ScalingCut is the share of every buy-on's cost growth removed, at
+	// most MaxScalingCut.
+	ScalingCut float64
+}
+
+// Compute returns the combined effects of the nodes in owned.
+func Compute(nodes []config.PrestigeNodeConfig, owned map[string]bool) Effects {
+	e := Effects{StartBuyOns: make(map[string]int)}
+	for _, n := range nodes {
+		if !owned[n.ID] {
+			continue
+		}
+		switch n.Effect {
+		case config.PrestigeEffectStartBuyOns:
+			e.StartBuyOns[n.Target] = max(e.StartBuyOns[n.Target], int(n.Value))
+		case config.PrestigeEffectClickBonus:
+			e.ClickBonus += n.Value
+		case config.PrestigeEffectCPSBonus:
+			e.CPSBonus += n.Value
+		case config.PrestigeEffectCostScaling:
+			e.ScalingCut += n.Value
+		}
+	}
+	e.ScalingCut = min(e.ScalingCut, MaxScalingCut)
+	return e
+}
+
+// Scaling returns a buy-on's cost growth per unit owned, scaling, with the
+// cut applied.
+func (e Effects) Scaling(scaling float64) float64 {
+	return 1 + (scaling-1)*(1-e.ScalingCut)
+}
+
+// Find returns the node id of nodes.
+func Find(nodes []config.PrestigeNodeConfig, id string) (config.PrestigeNodeConfig, bool) {
+	i := slices.IndexFunc(nodes, func(n config.PrestigeNodeConfig) bool { return n.ID == id })
+	if i < 0 {
+		return config.PrestigeNodeConfig{}, false
+	}
+	return nodes[i], true
+}
+
+// Status is where a node stands for the player.
+type Status int
+
+const (
+	// StatusLocked nodes have prerequisites not owned yet.
+	StatusLocked Status = iota
+	// StatusAvailable nodes can be bought once there are points enough.
+	StatusAvailable
+	// StatusOwned nodes are bought.
+	StatusOwned
+)
+
+// StatusOf returns n's status given the owned nodes.
+func StatusOf(n config.PrestigeNodeConfig, owned map[string]bool) Status {
+	switch {
+	case owned[n.ID]:
+		return StatusOwned
+	case slices.ContainsFunc(n.Requires, func(r string) bool { return !owned[r] }):
+		return StatusLocked
+	default:
+		return StatusAvailable
+	}
+}
+
+// CanBuy reports whether the node id can be bought with points: it exists,
+// is available and costs no more than points.
+func CanBuy(nodes []config.PrestigeNodeConfig, owned map[string]bool, points int, id string) bool {
+	n, ok := Find(nodes, id)
+	return ok && StatusOf(n, owned) == StatusAvailable && n.Cost <= points
+}
+
+// Row is a node in display order.
+type Row struct {
+	Node config.PrestigeNodeConfig
+	// Depth is the node's distance from a root through its first
+	// prerequisite.
+	Depth int
+	// Last is set if no later sibling follows the node under the same
+	// parent.
+	Last bool
+	// Also lists the prerequisites besides the first one, which the node is
+	// not shown under.
+	Also []string
+}
+
+// Layout returns the nodes as a tree, depth first in declaration order:
+// every node is listed under its first prerequisite.
+func Layout(nodes []config.PrestigeNodeConfig) []Row {
+	children := make(map[string][]config.PrestigeNodeConfig)
+	var roots []config.PrestigeNodeConfig
+	for _, n := range nodes {
+		if len(n.Requires) == 0 {
+			roots = append(roots, n)
+		} else {
+			children[n.Requires[0]] = append(children[n.Requires[0]], n)
+		}
+	}
+	var rows []Row
+	var walk func(list []config.PrestigeNodeConfig, depth int)
+	walk = func(list []config.PrestigeNodeConfig, depth int) {
+		for i, n := range list {
+			row := Row{Node: n, Depth: depth, Last: i == len(list)-1}
+			if len(n.Requires) > 1 {
+				row.Also = n.Requires[1:]
+			}
+			rows = append(rows, row)
+			walk(children[n.ID], depth+1)
+		}
+	}
+	walk(roots, 0)
+	return rows
+}
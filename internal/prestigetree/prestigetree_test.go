@@ -0,0 +1,63 @@
+package prestigetree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/config"
+)
+
+var testNodes = []config.PrestigeNodeConfig{
+	{ID: "start", Cost: 1, Effect: config.PrestigeEffectStartBuyOns, Target: "miner", Value: 10},
+	{ID: "click", Cost: 1, Effect: config.PrestigeEffectClickBonus, Value: 0.5},
+	{ID: "cheap", Cost: 2, Requires: []string{"start"}, Effect: config.PrestigeEffectCostScaling, Value: 0.4},
+	{ID: "cheaper", Cost: 3, Requires: []string{"cheap"}, Effect: config.PrestigeEffectCostScaling, Value: 0.4},
+	{ID: "cps", Cost: 5, Requires: []string{"start", "click"}, Effect: config.PrestigeEffectCPSBonus, Value: 0.25},
+}
+
+func TestCompute(t *testing.T) {
+	e := Compute(testNodes, map[string]bool{"start": true, "click": true, "cps": true})
+	assert.Equal(t, map[string]int{"miner": 10}, e.StartBuyOns)
+	assert.Equal(t, 0.5, e.ClickBonus)
+	assert.Equal(t, 0.25, e.CPSBonus)
+	assert.Zero(t, e.ScalingCut)
+	assert.Equal(t, 1.15, e.Scaling(1.15))
+
+	e = Compute(testNodes, map[string]bool{"cheap": true, "cheaper": true})
+	assert.Equal(t, MaxScalingCut, e.ScalingCut, "the cut is capped")
+	assert.InDelta(t, 1.075, e.Scaling(1.15), 1e-9)
+}
+
+func TestStatusOfAndCanBuy(t *testing.T) {
+	owned := map[string]bool{"start": true}
+	cps, _ := Find(testNodes, "cps")
+	cheap, _ := Find(testNodes, "cheap")
+	start, _ := Find(testNodes, "start")
+	assert.Equal(t, StatusOwned, StatusOf(start, owned))
+	assert.Equal(t, StatusAvailable, StatusOf(cheap, owned))
+	assert.Equal(t, StatusLocked, StatusOf(cps, owned), "every prerequisite is needed")
+
+	assert.True(t, CanBuy(testNodes, owned, 2, "cheap"))
+	assert.False(t, CanBuy(testNodes, owned, 1, "cheap"), "not enough points")
+	assert.False(t, CanBuy(testNodes, owned, 9, "start"), "already owned")
+	assert.False(t, CanBuy(testNodes, owned, 9, "cps"))
+	assert.False(t, CanBuy(testNodes, owned, 9, "missing"))
+}
+
+func TestLayout(t *testing.T) {
+	rows := Layout(testNodes)
+	require.Len(t, rows, len(testNodes))
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.Node.ID)
+	}
+	assert.Equal(t, []string{"start", "cheap", "cheaper", "cps", "click"}, got)
+	assert.Equal(t, 0, rows[0].Depth)
+	assert.Equal(t, 2, rows[2].Depth)
+	assert.False(t, rows[1].Last, "cps follows cheap under start")
+	assert.True(t, rows[3].Last)
+	assert.Equal(t, []string{"click"}, rows[3].Also)
+	assert.True(t, rows[4].Last)
+}
@@ -0,0 +1,220 @@
+// Package replay records a play session as a compact action log against a
+// snapshot of its starting state, and re-runs such logs through
+// engine.Engine to reproduce bugs and to serve as regression tests. It has no
+// Bubble Tea imports.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/save"
+)
+
+// FormatVersion is the version of the action log format.
+const FormatVersion = 1
+
+// Kind identifies the type of a recorded action.
+type Kind string
+
+const (
+	KindTick          Kind = "tick"
+	KindClick         Kind = "click"
+	KindPurchase      Kind = "buy"
+	KindPrestige      Kind = "prestige"
+	KindExchangeBoost Kind = "exchange"
+	KindAutoBuyUnlock Kind = "autobuy_unlock"
+	// KindAutoBuyer changes the auto-buyer's settings to Action.AutoBuyer.
+	// Its purchases are not logged: they happen inside ticks.
+	KindAutoBuyer Kind = "autobuy"
+	// KindChallengeDay starts the daily challenges of the day in ID.
+	KindChallengeDay Kind = "challenge_day"
+	// KindChallengeReroll rerolls the daily challenge ID.
+	KindChallengeReroll Kind = "reroll"
+	// KindLoginClaim claims the login streak reward at the time in ID, in
+	// RFC 3339 format with the local UTC offset.
+	KindLoginClaim Kind = "login"
+	// KindRunStart starts the challenge run ID in World.
+	KindRunStart Kind = "run_start"
+	// KindRunAbandon abandons the challenge run in progress in World.
+	KindRunAbandon Kind = "run_abandon"
+	// KindPrestigeNode buys the node ID of World's prestige tree.
+	KindPrestigeNode Kind = "prestige_node"
+	// KindPerk buys a rank of the account perk ID.
+	KindPerk Kind = "perk"
+	// KindPerkRespec refunds every account perk rank.
+	KindPerkRespec Kind = "perk_respec"
+	// KindMarketSell sells Amount World coins on the market.
+	KindMarketSell Kind = "market_sell"
+	// KindMarketBuy spends Amount general coins on World coins on the market.
+	KindMarketBuy Kind = "market_buy"
+	// KindEnd is the last line of a finished recording and carries the final
+	// state. Logs cut short by a crash have no end line.
+	KindEnd Kind = "end"
+)
+
+// Header is the first line of an action log.
+type Header struct {
+	Format     int       `json:"clicker_replay"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Initial is the state the recording starts from. It is stored unsigned:
+	// a log is a debugging artifact and is never loaded as a save.
+	Initial save.SaveFile `json:"initial"`
+}
+
+// Action is one line of an action log. Runs of identical actions are
+// coalesced into one line with N set to the repeat count.
+type Action struct {
+	// Tick is the number of engine ticks recorded before this action.
+	Tick  int64   `json:"t"`
+	Kind  Kind    `json:"k"`
+	DT    float64 `json:"dt,omitempty"`
+	World string  `json:"w,omitempty"`
+	// ID is the buy-on ID for KindPurchase, the day for KindChallengeDay,
+	// the challenge ID for KindChallengeReroll, the time for KindLoginClaim,
+	// the challenge run ID for KindRunStart, the node ID for
+	// KindPrestigeNode and the perk ID for KindPerk.
+	ID string `json:"id,omitempty"`
+	// Amount is the world coins sold for KindMarketSell and the general
+	// coins spent for KindMarketBuy.
+	Amount float64 `json:"a,omitempty"`
+	// N is the repeat count; 0 and 1 both mean once.
+	N int `json:"n,omitempty"`
+	// AutoBuyer is the new settings for KindAutoBuyer.
+	AutoBuyer *autobuy.Settings `json:"auto_buyer,omitempty"`
+	// Final is the state at the end of the recording, for KindEnd.
+	Final *save.SaveFile `json:"final,omitempty"`
+}
+
+// Count returns how many times the action is applied.
+func (a Action) Count() int { return max(a.N, 1) }
+
+func (a Action) String() string {
+	var s string
+	switch a.Kind {
+	case KindTick:
+		s = fmt.Sprintf("tick %.2fs", a.DT)
+	case KindPurchase:
+		s = fmt.Sprintf("buy %s in %s", a.ID, a.World)
+	case KindAutoBuyUnlock:
+		s = "unlock the auto-buyer"
+	case KindAutoBuyer:
+		s = "configure the auto-buyer"
+	case KindChallengeDay:
+		s = "challenges of " + a.ID
+	case KindChallengeReroll:
+		s = "reroll challenge " + a.ID
+	case KindLoginClaim:
+		s = "claim the login reward at " + a.ID
+	case KindRunStart:
+		s = fmt.Sprintf("start run %s in %s", a.ID, a.World)
+	case KindRunAbandon:
+		s = "abandon the run in " + a.World
+	case KindPrestigeNode:
+		s = fmt.Sprintf("buy prestige node %s in %s", a.ID, a.World)
+	case KindPerk:
+		s = "buy perk " + a.ID
+	case KindPerkRespec:
+		s = "respec perks"
+	case KindMarketSell:
+		s = fmt.Sprintf("sell %g coins in %s", a.Amount, a.World)
+	case KindMarketBuy:
+		s = fmt.Sprintf("buy coins for %g GC in %s", a.Amount, a.World)
+	default:
+		s = fmt.Sprintf("%s in %s", a.Kind, a.World)
+	}
+	if a.N > 1 {
+		s += fmt.Sprintf(" ×%d", a.N)
+	}
+	return s
+}
+
+// sameRun reports whether b repeats a and can be folded into it.
+func (a Action) sameRun(b Action) bool {
+	return a.Kind == b.Kind && a.World == b.World && a.ID == b.ID && a.DT == b.DT && a.Amount == b.Amount &&
+		a.AutoBuyer == nil && b.AutoBuyer == nil
+}
+
+// Log is a parsed action log.
+type Log struct {
+	Header
+	Actions []Action
+	// Final is the recorded end state, or nil if the recording was cut short.
+	Final *save.SaveFile
+}
+
+// TotalTicks returns the number of engine ticks the log covers.
+func (l Log) TotalTicks() int64 {
+	var n int64
+	for _, a := range l.Actions {
+		if a.Kind == KindTick {
+			n += int64(a.Count())
+		}
+	}
+	return n
+}
+
+// ErrNotALog is returned by Read for input that does not start with an
+// action log header.
+var ErrNotALog = errors.New("replay: not an action log")
+
+// Read parses an action log.
+func Read(r io.Reader) (Log, error) {
+	sc := bufio.NewScanner(r)
+	// the header and end lines hold whole save files.
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+
+	var l Log
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return l, fmt.Errorf("replay: read: %w", err)
+		}
+		return l, ErrNotALog
+	}
+	if err := json.Unmarshal(sc.Bytes(), &l.Header); err != nil || l.Format == 0 {
+		return l, ErrNotALog
+	}
+	if l.Format > FormatVersion {
+		return l, fmt.Errorf("replay: log format %d is newer than this build (%d)", l.Format, FormatVersion)
+	}
+
+	for line := 2; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var a Action
+		if err := json.Unmarshal(sc.Bytes(), &a); err != nil {
+			return l, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		switch a.Kind {
+		case KindEnd:
+			l.Final = a.Final
+		case KindTick, KindClick, KindPurchase, KindPrestige, KindExchangeBoost, KindAutoBuyUnlock, KindAutoBuyer,
+			KindChallengeDay, KindChallengeReroll, KindLoginClaim, KindRunStart, KindRunAbandon,
+			KindPrestigeNode, KindPerk, KindPerkRespec, KindMarketSell, KindMarketBuy:
+			l.Actions = append(l.Actions, a)
+		default:
+			return l, fmt.Errorf("replay: line %d: unknown action %q", line, a.Kind)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return l, fmt.Errorf("replay: read: %w", err)
+	}
+	return l, nil
+}
+
+// ReadFile parses the action log at path.
+func ReadFile(path string) (Log, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Log{}, fmt.Errorf("replay: %w", err)
+	}
+	defer f.Close()
+	return Read(f)
+}
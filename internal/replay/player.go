@@ -0,0 +1,166 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/world"
+)
+
+// Player re-runs an action log through a fresh engine one action at a time,
+// for stepping through a session or playing it back visually. Use Run to
+// replay a whole log at once.
+type Player struct {
+	log  Log
+	eng  *engine.Engine
+	next int // index into log.Actions
+	rep  int // repetitions of log.Actions[next] already applied
+
+	ticks      int64
+	elapsed    float64
+	totalTicks int64
+}
+
+// NewPlayer returns a Player positioned before the first action of l, with
+// an engine built from the log's initial snapshot.
+func NewPlayer(l Log, worldReg *world.WorldRegistry, achievReg *achievement.AchievementRegistry) *Player {
+	// round-trip the snapshot so the replayed engine never shares maps with l.
+	initial := l.Initial
+	if data, err := json.Marshal(initial); err == nil {
+		_ = json.Unmarshal(data, &initial)
+	}
+	eng := engine.New(save.GameStateFromSave(initial, worldReg), worldReg, achievReg)
+	eng.Earned = initial.Achievements
+	if eng.Earned == nil {
+		eng.Earned = make(map[string]bool)
+	}
+	// the clock record is bookkeeping, not gameplay, and replays run far
+	// faster than real time.
+	eng.Clock = nil
+	return &Player{log: l, eng: eng, totalTicks: l.TotalTicks()}
+}
+
+// Engine returns the engine being replayed into.
+func (p *Player) Engine() *engine.Engine { return p.eng }
+
+// Done reports whether every action has been applied.
+func (p *Player) Done() bool { return p.next >= len(p.log.Actions) }
+
+// Ticks returns how many engine ticks have been replayed so far.
+func (p *Player) Ticks() int64 { return p.ticks }
+
+// TotalTicks returns how many engine ticks the whole log covers.
+func (p *Player) TotalTicks() int64 { return p.totalTicks }
+
+// Elapsed returns the game time replayed so far, in seconds.
+func (p *Player) Elapsed() float64 { return p.elapsed }
+
+// Step applies one repetition of the next action and returns it with N
+// cleared. It returns an error if the engine refuses an action the recording
+// says succeeded: the replay has diverged from the recorded session.
+func (p *Player) Step() (Action, error) {
+	if p.Done() {
+		return Action{}, fmt.Errorf("replay: no actions left")
+	}
+	a := p.log.Actions[p.next]
+	a.N = 0
+	a.Tick = p.ticks
+
+	ok := true
+	switch a.Kind {
+	case KindTick:
+		p.eng.Tick(a.DT)
+		p.ticks++
+		p.elapsed += a.DT
+	case KindClick:
+		_, exists := p.eng.State.Worlds[a.World]
+		p.eng.HandleClick(a.World)
+		ok = exists
+	case KindPurchase:
+		_, ok = p.eng.PurchaseBuyOn(a.World, a.ID)
+	case KindPrestige:
+		_, ok = p.eng.ExecutePrestige(a.World)
+	case KindExchangeBoost:
+		_, ok = p.eng.ExecuteExchangeBoost(a.World)
+	case KindAutoBuyUnlock:
+		ok = p.eng.UnlockAutoBuyer()
+	case KindAutoBuyer:
+		if ok = a.AutoBuyer != nil; ok {
+			p.eng.ConfigureAutoBuyer(*a.AutoBuyer)
+		}
+	case KindChallengeDay:
+		ok = p.eng.BeginChallengeDay(a.ID)
+	case KindChallengeReroll:
+		ok = p.eng.RerollChallenge(a.ID)
+	case KindLoginClaim:
+		now, err := time.Parse(time.RFC3339Nano, a.ID)
+		if ok = err == nil; ok {
+			_, ok = p.eng.ClaimLoginReward(now)
+		}
+	case KindRunStart:
+		ok = p.eng.StartRun(a.World, a.ID)
+	case KindRunAbandon:
+		ok = p.eng.AbandonRun(a.World)
+	case KindPrestigeNode:
+		ok = p.eng.BuyPrestigeNode(a.World, a.ID)
+	case KindPerk:
+		ok = p.eng.BuyPerk(a.ID)
+	case KindPerkRespec:
+		ok = p.eng.RespecPerks()
+	case KindMarketSell:
+		_, ok = p.eng.SellWorldCoins(a.World, a.Amount)
+	case KindMarketBuy:
+		_, ok = p.eng.BuyWorldCoins(a.World, a.Amount)
+	default:
+		ok = false
+	}
+
+	p.rep++
+	if p.rep >= p.log.Actions[p.next].Count() {
+		p.next, p.rep = p.next+1, 0
+	}
+	if !ok {
+		return a, fmt.Errorf("replay: %s at tick %d was refused; the replay has diverged from the recording", a, a.Tick)
+	}
+	return a, nil
+}
+
+// Run replays every action of l and returns the resulting engine.
+func Run(l Log, worldReg *world.WorldRegistry, achievReg *achievement.AchievementRegistry) (*engine.Engine, error) {
+	p := NewPlayer(l, worldReg, achievReg)
+	for !p.Done() {
+		if _, err := p.Step(); err != nil {
+			return p.eng, err
+		}
+	}
+	return p.eng, nil
+}
+
+// tolerance is the relative difference allowed between recorded and replayed
+// numbers. Replays are deterministic, but achievement rewards may be summed
+// in a different order.
+const tolerance = 1e-9
+
+// Check compares the state of e with want and returns one line per
+// difference, e.g. "worlds.terra.coins: recorded 1800, replayed 1799.5".
+// Every gameplay field of SaveFile is compared, so state added by new
+// features is checked without changes here.
+func Check(want save.SaveFile, e *engine.Engine) []string {
+	got := save.SaveFileFromGameState(e.State, e.Earned, want.Settings)
+	var diffs []string
+	for _, d := range save.Diff(want, got, save.DiffOptions{Ignore: save.BookkeepingFields, Tolerance: tolerance}) {
+		diffs = append(diffs, fmt.Sprintf("%s: recorded %s, replayed %s", d.Path, show(d.A), show(d.B)))
+	}
+	return diffs
+}
+
+func show(v string) string {
+	if v == "" {
+		return "nothing"
+	}
+	return v
+}
@@ -0,0 +1,177 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+)
+
+// maxRun caps how many identical actions are folded into one line, so that
+// an idle session still reaches the disk about once a minute and a crash
+// loses little of the log.
+const maxRun = 600
+
+// Recorder writes an action log as the engine reports actions. It implements
+// engine.Recorder. Write errors are logged once and end the recording; they
+// never interrupt the game.
+type Recorder struct {
+	w        io.WriteCloser
+	enc      *json.Encoder
+	settings save.Settings
+
+	pending    Action
+	hasPending bool
+	ticks      int64
+	ended      bool
+	err        error
+}
+
+// NewRecorder creates an action log at path, starting from the current
+// state of e, and attaches the recorder to e. settings are stored in the
+// snapshot only so it is a complete SaveFile; they do not affect replay.
+func NewRecorder(path string, e *engine.Engine, settings save.Settings) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("replay: mkdir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	r := &Recorder{w: f, enc: json.NewEncoder(f), settings: settings}
+	header := Header{
+		Format:     FormatVersion,
+		RecordedAt: time.Now().UTC(),
+		Initial:    save.SaveFileFromGameState(e.State, e.Earned, settings),
+	}
+	if err := r.enc.Encode(header); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("replay: write header: %w", err)
+	}
+	e.Recorder = r
+	return r, nil
+}
+
+// Err returns the write error that ended the recording early, if any.
+func (r *Recorder) Err() error { return r.err }
+
+func (r *Recorder) RecordTick(dt float64) {
+	r.record(Action{Kind: KindTick, DT: dt})
+	r.ticks++
+}
+
+func (r *Recorder) RecordClick(worldID string) {
+	r.record(Action{Kind: KindClick, World: worldID})
+}
+
+func (r *Recorder) RecordPurchase(worldID, buyOnID string) {
+	r.record(Action{Kind: KindPurchase, World: worldID, ID: buyOnID})
+}
+
+func (r *Recorder) RecordPrestige(worldID string) {
+	r.record(Action{Kind: KindPrestige, World: worldID})
+}
+
+func (r *Recorder) RecordExchangeBoost(worldID string) {
+	r.record(Action{Kind: KindExchangeBoost, World: worldID})
+}
+
+func (r *Recorder) RecordAutoBuyerUnlock() {
+	r.record(Action{Kind: KindAutoBuyUnlock})
+}
+
+func (r *Recorder) RecordAutoBuyer(s autobuy.Settings) {
+	r.record(Action{Kind: KindAutoBuyer, AutoBuyer: &s})
+}
+
+func (r *Recorder) RecordChallengeDay(day string) {
+	r.record(Action{Kind: KindChallengeDay, ID: day})
+}
+
+func (r *Recorder) RecordChallengeReroll(id string) {
+	r.record(Action{Kind: KindChallengeReroll, ID: id})
+}
+
+func (r *Recorder) RecordLoginClaim(now time.Time) {
+	r.record(Action{Kind: KindLoginClaim, ID: now.Format(time.RFC3339Nano)})
+}
+
+func (r *Recorder) RecordRunStart(worldID, runID string) {
+	r.record(Action{Kind: KindRunStart, World: worldID, ID: runID})
+}
+
+func (r *Recorder) RecordRunAbandon(worldID string) {
+	r.record(Action{Kind: KindRunAbandon, World: worldID})
+}
+
+func (r *Recorder) RecordPrestigeNode(worldID, nodeID string) {
+	r.record(Action{Kind: KindPrestigeNode, World: worldID, ID: nodeID})
+}
+
+func (r *Recorder) RecordPerk(perkID string) {
+	r.record(Action{Kind: KindPerk, ID: perkID})
+}
+
+func (r *Recorder) RecordPerkRespec() {
+	r.record(Action{Kind: KindPerkRespec})
+}
+
+func (r *Recorder) RecordMarketSell(worldID string, coins float64) {
+	r.record(Action{Kind: KindMarketSell, World: worldID, Amount: coins})
+}
+
+func (r *Recorder) RecordMarketBuy(worldID string, gc float64) {
+	r.record(Action{Kind: KindMarketBuy, World: worldID, Amount: gc})
+}
+
+// End writes the final state of e and closes the log. Later calls, and
+// actions reported after it, are ignored.
+func (r *Recorder) End(e *engine.Engine) {
+	if r.ended {
+		return
+	}
+	r.flush()
+	final := save.SaveFileFromGameState(e.State, e.Earned, r.settings)
+	r.write(Action{Tick: r.ticks, Kind: KindEnd, Final: &final})
+	r.ended = true
+	if err := r.w.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *Recorder) record(a Action) {
+	if r.ended || r.err != nil {
+		return
+	}
+	a.Tick = r.ticks
+	if r.hasPending && r.pending.sameRun(a) && r.pending.Count() < maxRun {
+		r.pending.N = r.pending.Count() + 1
+		return
+	}
+	r.flush()
+	r.pending, r.hasPending = a, true
+}
+
+func (r *Recorder) flush() {
+	if r.hasPending {
+		r.write(r.pending)
+		r.hasPending = false
+	}
+}
+
+func (r *Recorder) write(a Action) {
+	if r.err != nil {
+		return
+	}
+	if err := r.enc.Encode(a); err != nil {
+		r.err = err
+		log.Printf("replay: recording stopped: %v", err)
+	}
+}
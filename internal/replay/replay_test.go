@@ -0,0 +1,201 @@
+package replay
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/world"
+	_ "github.com/clicker-org/clicker/internal/world/worlds"
+)
+
+func newRegistries() (*world.WorldRegistry, *achievement.AchievementRegistry) {
+	achReg := achievement.NewAchievementRegistry()
+	achievement.RegisterDefaults(achReg)
+	return world.DefaultRegistry, achReg
+}
+
+// newEngine returns an engine with enough terra coins to buy, prestige and
+// boost, as a recording session would start from a loaded save.
+func newEngine(t *testing.T) *engine.Engine {
+	t.Helper()
+	worldReg, achReg := newRegistries()
+	eng := engine.New(save.GameStateFromSave(save.DefaultSaveFile(), worldReg), worldReg, achReg)
+	eng.Clock = nil
+	eng.StartChallenges("replay-test", time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local))
+	ws := eng.State.Worlds["terra"]
+	ws.Coins = 5000
+	ws.TotalCoinsEarned = 2e6
+	return eng
+}
+
+// playSession drives eng through every kind of recorded action.
+func playSession(eng *engine.Engine) {
+	for i := 0; i < 20; i++ {
+		eng.Tick(0.1)
+	}
+	for i := 0; i < 30; i++ {
+		eng.HandleClick("terra")
+	}
+	for i := 0; i < 5; i++ {
+		eng.PurchaseBuyOn("terra", "auto_miner")
+	}
+	eng.PurchaseBuyOn("terra", "no_such_buy_on") // refused: not recorded
+	eng.BeginChallengeDay("2026-01-02")
+	eng.ClaimLoginReward(time.Date(2026, 1, 2, 8, 0, 0, 0, time.FixedZone("", -5*3600)))
+	eng.RerollChallenge(eng.State.Challenges.Daily[0].ID)
+	for i := 0; i < 700; i++ {
+		eng.Tick(0.1)
+	}
+	eng.ExecuteExchangeBoost("terra")
+	eng.SellWorldCoins("terra", 10)
+	eng.ExecutePrestige("terra")
+	// all paid for by the prestige
+	eng.BuyPrestigeNode("terra", "calloused_hands")
+	eng.BuyPerk("strong_arm")
+	eng.BuyWorldCoins("aqua", 0.01)
+	eng.UnlockAutoBuyer()
+	eng.ConfigureAutoBuyer(autobuy.Settings{
+		Worlds: map[string]autobuy.WorldSettings{"terra": {Enabled: true, Policy: autobuy.PolicyCheapest}},
+	})
+	eng.StartRun("aqua", "no_clicks")
+	eng.HandleClick("aqua") // refused by the run: not recorded
+	for i := 0; i < 40; i++ {
+		eng.HandleClick("terra")
+	}
+	for i := 0; i < 100; i++ {
+		eng.Tick(0.1)
+	}
+	eng.AbandonRun("aqua")
+}
+
+func record(t *testing.T) (string, *engine.Engine) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	eng := newEngine(t)
+	rec, err := NewRecorder(path, eng, save.DefaultSaveFile().Settings)
+	require.NoError(t, err)
+	playSession(eng)
+	rec.End(eng)
+	require.NoError(t, rec.Err())
+	return path, eng
+}
+
+func TestRecordAndReplay_FinalStateMatches(t *testing.T) {
+	path, recorded := record(t)
+
+	l, err := ReadFile(path)
+	require.NoError(t, err)
+	require.NotNil(t, l.Final)
+	assert.Equal(t, int64(820), l.TotalTicks())
+
+	worldReg, achReg := newRegistries()
+	replayed, err := Run(l, worldReg, achReg)
+	require.NoError(t, err)
+	assert.Empty(t, Check(*l.Final, replayed))
+	assert.Equal(t, 1, replayed.State.Worlds["terra"].PrestigeCount)
+	assert.Equal(t, 1, replayed.State.Worlds["terra"].BuyOnCounts["auto_miner"], "bought by the auto-buyer after the prestige")
+	assert.InDelta(t, recorded.State.Player.GeneralCoins, replayed.State.Player.GeneralCoins, 1e-9)
+	assert.Equal(t, recorded.State.Challenges, replayed.State.Challenges, "rerolls are generated again")
+	assert.Equal(t, "2026-01-02", replayed.State.Streak.LastDay)
+	assert.Equal(t, -5*3600, replayed.State.Streak.Zone, "claims keep their time zone")
+	assert.Equal(t, 1, replayed.State.Runs.Record("aqua", "no_clicks").Attempts)
+	assert.Empty(t, replayed.State.Runs.Active)
+	assert.True(t, replayed.State.Worlds["terra"].PrestigeTree["calloused_hands"])
+	assert.Equal(t, 1, replayed.State.Player.Perks["strong_arm"])
+}
+
+func TestRecorder_CoalescesRuns(t *testing.T) {
+	path, _ := record(t)
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	// header, 20 ticks, 30 clicks, 5 buys, new challenge day, login claim,
+	// reroll, 700 ticks (two lines at maxRun), exchange, market sale,
+	// prestige, prestige node, perk, market purchase, auto-buyer unlock and
+	// settings, run start, 40 clicks, 100 ticks (with the auto-buyer's
+	// purchases), run abandon, end.
+	assert.Len(t, lines, 22)
+	assert.Contains(t, lines[2], `"k":"click","w":"terra","n":30`)
+}
+
+func TestCheck_ReportsDifferences(t *testing.T) {
+	path, _ := record(t)
+	l, err := ReadFile(path)
+	require.NoError(t, err)
+	final := *l.Final
+	final.Worlds = map[string]save.WorldSaveData{}
+	for id, ws := range l.Final.Worlds {
+		final.Worlds[id] = ws
+	}
+	terra := final.Worlds["terra"]
+	terra.Coins += 1
+	final.Worlds["terra"] = terra
+
+	worldReg, achReg := newRegistries()
+	replayed, err := Run(l, worldReg, achReg)
+	require.NoError(t, err)
+	diffs := Check(final, replayed)
+	require.Len(t, diffs, 1)
+	assert.True(t, strings.HasPrefix(diffs[0], "worlds.terra.coins: recorded"), diffs[0])
+}
+
+func TestRun_DivergenceIsAnError(t *testing.T) {
+	path, _ := record(t)
+	l, err := ReadFile(path)
+	require.NoError(t, err)
+	// a purchase the initial state cannot afford.
+	l.Initial.Worlds["terra"] = save.WorldSaveData{WorldID: "terra", PrestigeMultiplier: 1, ExchangeRate: 1}
+
+	worldReg, achReg := newRegistries()
+	_, err = Run(l, worldReg, achReg)
+	assert.ErrorContains(t, err, "diverged")
+}
+
+func TestRead_TruncatedLog(t *testing.T) {
+	path, _ := record(t)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	cut := strings.Join(lines[:len(lines)-1], "\n")
+
+	l, err := Read(strings.NewReader(cut))
+	require.NoError(t, err)
+	assert.Nil(t, l.Final, "a crashed session has no end line")
+	assert.NotEmpty(t, l.Actions)
+
+	_, err = Read(strings.NewReader(`{"version":2}`))
+	assert.ErrorIs(t, err, ErrNotALog)
+}
+
+func TestRestore_EndsRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	eng := newEngine(t)
+	_, err := NewRecorder(path, eng, save.Settings{})
+	require.NoError(t, err)
+	eng.HandleClick("terra")
+
+	eng.Restore(eng.State, eng.Earned)
+	assert.Nil(t, eng.Recorder)
+
+	l, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.NotNil(t, l.Final)
+}
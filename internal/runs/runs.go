@@ -0,0 +1,161 @@
+// Package runs defines challenge runs: opt-in replays of a world under
+// restrictions that reward a permanent CPS bonus in that world. It holds the
+// definitions and the bookkeeping; the engine resets the world, enforces the
+// restrictions and grants the bonus (see engine.Engine.StartRun). It has no
+// Bubble Tea imports.
+package runs
+
+// DefaultGoal is the coins a run must earn in a world whose prestige
+// threshold is not a coin amount.
+const DefaultGoal = 1e6
+
+// Restrictions are the rules a run is played under. The zero value
+// restricts nothing.
+type Restrictions struct {
+	// NoClicks disables clicking.
+	NoClicks bool
+	// MaxBuyOns allows only the first MaxBuyOns buy-ons of the world, in
+	// registry order; 0 allows all.
+	MaxBuyOns int
+	// CostScaling multiplies every buy-on's cost growth per unit owned; 0
+	// leaves it unchanged.
+	CostScaling float64
+	// TimeLimit is the play time, in seconds, the goal must be reached in;
+	// 0 is no limit.
+	TimeLimit float64
+}
+
+// AllowsBuyOn reports whether the buy-on at index in registry order may be
+// bought; a negative index is a buy-on the registry does not know.
+func (r Restrictions) AllowsBuyOn(index int) bool {
+	return r.MaxBuyOns == 0 || (index >= 0 && index < r.MaxBuyOns)
+}
+
+// Scaling returns a buy-on's cost growth per unit owned, scaling, under r.
+func (r Restrictions) Scaling(scaling float64) float64 {
+	if r.CostScaling == 0 {
+		return scaling
+	}
+	return scaling * r.CostScaling
+}
+
+// Definition describes a challenge run.
+type Definition struct {
+	ID          string
+	Name        string
+	Description string
+	Restrictions
+	// Bonus is the world's permanent CPS bonus for completing the run the
+	// first time, e.g. 0.1 for +10%.
+	Bonus float64
+}
+
+// Definitions lists every challenge run, in the order the prestige tab
+// shows them.
+var Definitions = []Definition{
+	{ID: "no_clicks", Name: "Hands Off", Description: "Clicking is disabled.", Restrictions: Restrictions{NoClicks: true}, Bonus: 0.10},
+	{ID: "two_buy_ons", Name: "Minimalist", Description: "Only the first two buy-ons can be bought.", Restrictions: Restrictions{MaxBuyOns: 2}, Bonus: 0.15},
+	{ID: "inflation", Name: "Inflation", Description: "Costs grow 1.3× faster with each unit owned.", Restrictions: Restrictions{CostScaling: 1.3}, Bonus: 0.20},
+	{ID: "speedrun", Name: "Against the Clock", Description: "Reach the goal within 15 minutes of play.", Restrictions: Restrictions{TimeLimit: 15 * 60}, Bonus: 0.20},
+}
+
+// Get returns the definition of the run id.
+func Get(id string) (Definition, bool) {
+	for _, d := range Definitions {
+		if d.ID == id {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Active is a run in progress in a world.
+type Active struct {
+	ID string `json:"id"`
+	// Goal is the coins to earn in the world during the run.
+	Goal float64 `json:"goal"`
+	// Earned is the coins earned in the world since the run started, while
+	// playing; offline income does not count.
+	Earned float64 `json:"earned"`
+	// Elapsed is the play time since the run started, in seconds.
+	Elapsed float64 `json:"elapsed"`
+}
+
+// Record is a world's history with one run.
+type Record struct {
+	Attempts  int  `json:"attempts"`
+	Completed bool `json:"completed,omitempty"`
+	// BestSeconds is the fastest completion, in seconds of play.
+	BestSeconds float64 `json:"best_seconds,omitempty"`
+}
+
+// State is the persisted challenge run state.
+type State struct {
+	// Active holds the run in progress in each world, by world ID.
+	Active map[string]Active `json:"active,omitempty"`
+	// Records holds each world's records, by world ID and run ID.
+	Records map[string]map[string]Record `json:"records,omitempty"`
+}
+
+// Clone returns a deep copy of s.
+func (s State) Clone() State {
+	out := State{
+		Active:  make(map[string]Active, len(s.Active)),
+		Records: make(map[string]map[string]Record, len(s.Records)),
+	}
+	for id, a := range s.Active {
+		out.Active[id] = a
+	}
+	for id, recs := range s.Records {
+		out.Records[id] = make(map[string]Record, len(recs))
+		for run, r := range recs {
+			out.Records[id][run] = r
+		}
+	}
+	return out
+}
+
+// Running returns the run in progress in worldID and its definition.
+func (s State) Running(worldID string) (Definition, Active, bool) {
+	a, ok := s.Active[worldID]
+	if !ok {
+		return Definition{}, Active{}, false
+	}
+	d, ok := Get(a.ID)
+	return d, a, ok
+}
+
+// Restrictions returns the restrictions in force in worldID: those of its
+// run in progress, if any.
+func (s State) Restrictions(worldID string) Restrictions {
+	d, _, _ := s.Running(worldID)
+	return d.Restrictions
+}
+
+// Record returns worldID's record with the run id.
+func (s State) Record(worldID, id string) Record {
+	return s.Records[worldID][id]
+}
+
+// Multiplier returns the CPS multiplier worldID has earned: 1 plus the
+// bonus of every run completed there.
+func (s State) Multiplier(worldID string) float64 {
+	m := 1.0
+	for _, d := range Definitions {
+		if s.Records[worldID][d.ID].Completed {
+			m += d.Bonus
+		}
+	}
+	return m
+}
+
+// SetRecord stores worldID's record with the run id.
+func (s *State) SetRecord(worldID, id string, r Record) {
+	if s.Records == nil {
+		s.Records = make(map[string]map[string]Record)
+	}
+	if s.Records[worldID] == nil {
+		s.Records[worldID] = make(map[string]Record)
+	}
+	s.Records[worldID][id] = r
+}
@@ -0,0 +1,46 @@
+package runs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestrictions_Scaling(t *testing.T) {
+	assert.Equal(t, 1.15, Restrictions{}.Scaling(1.15))
+	assert.InDelta(t, 1.15*1.3, Restrictions{CostScaling: 1.3}.Scaling(1.15), 1e-9)
+}
+
+func TestRestrictions_AllowsBuyOn(t *testing.T) {
+	assert.True(t, Restrictions{}.AllowsBuyOn(7))
+	r := Restrictions{MaxBuyOns: 2}
+	assert.True(t, r.AllowsBuyOn(1))
+	assert.False(t, r.AllowsBuyOn(2))
+	assert.False(t, r.AllowsBuyOn(-1), "unknown buy-ons are barred")
+}
+
+func TestState_MultiplierAndClone(t *testing.T) {
+	var s State
+	assert.Equal(t, 1.0, s.Multiplier("terra"))
+
+	s.SetRecord("terra", "no_clicks", Record{Attempts: 2, Completed: true, BestSeconds: 90})
+	s.SetRecord("terra", "speedrun", Record{Attempts: 1})
+	d, ok := Get("no_clicks")
+	require.True(t, ok)
+	assert.InDelta(t, 1+d.Bonus, s.Multiplier("terra"), 1e-9, "only completed runs count")
+	assert.Equal(t, 1.0, s.Multiplier("aqua"), "bonuses are per world")
+
+	c := s.Clone()
+	c.SetRecord("terra", "speedrun", Record{Attempts: 5})
+	assert.Equal(t, 1, s.Record("terra", "speedrun").Attempts)
+}
+
+func TestDefinitions_UniqueIDs(t *testing.T) {
+	seen := map[string]bool{}
+	for _, d := range Definitions {
+		assert.False(t, seen[d.ID], d.ID)
+		seen[d.ID] = true
+		assert.Positive(t, d.Bonus, d.ID)
+	}
+}
@@ -0,0 +1,109 @@
+package save
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackupCount is the number of rotating backups kept next to the save file
+// (save.json.1 is the newest, save.json.<BackupCount> the oldest).
+const BackupCount = 3
+
+// BackupInterval is the least time between two backup rotations. Saves made
+// sooner after the last rotation leave the backups alone, so that they
+// reach back further than the last few autosaves.
+const BackupInterval = 10 * time.Minute
+
+// BackupPath returns the path of the n-th rotating backup of path (1 = newest).
+func BackupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// writeFileAtomic writes data to path so that a crash at any point leaves
+// either the old or the new file intact: the data goes to a temp file in the
+// same directory, is fsynced, and then renamed over path.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	cleanup := func() { _ = os.Remove(tmpName) }
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		cleanup()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		cleanup()
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir fsyncs a directory so a preceding rename is durable. Errors are
+// ignored: not every platform supports syncing directories.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+}
+
+// rotateBackups shifts save.json.N-1 → save.json.N … save.json → save.json.1,
+// dropping the oldest backup, unless save.json.1 was written less than
+// BackupInterval ago. Missing files are skipped. The current save is copied
+// rather than moved so that path stays valid until the new save is renamed
+// over it.
+func rotateBackups(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if fi, err := os.Stat(BackupPath(path, 1)); err == nil && time.Since(fi.ModTime()) < BackupInterval {
+		return nil
+	}
+	for n := BackupCount - 1; n >= 1; n-- {
+		src := BackupPath(path, n)
+		if _, err := os.Stat(src); err != nil {
+			continue
+		}
+		if err := os.Rename(src, BackupPath(path, n+1)); err != nil {
+			return err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(BackupPath(path, 1), data, 0o600)
+}
+
+// quarantine moves a file that failed to load aside as
+// <path>.corrupt-<timestamp> so it is never silently discarded, and returns
+// the new path.
+func quarantine(path string, now time.Time) (string, error) {
+	dest := fmt.Sprintf("%s.corrupt-%s", path, now.UTC().Format("20060102T150405Z"))
+	if err := os.Rename(path, dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
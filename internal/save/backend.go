@@ -0,0 +1,177 @@
+package save
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNoRemoteSave is returned by Backend.Get when nothing has been stored yet.
+var ErrNoRemoteSave = errors.New("save: no save stored in backend")
+
+// Revision identifies one stored version of a save in a Backend. Numbers
+// start at 1 and increase by one on every successful Put; 0 means "nothing
+// stored". Writer and Time record who made the revision, so a conflict can
+// tell the player which machine saved last.
+type Revision struct {
+	Number int64     `json:"number"`
+	Writer string    `json:"writer"`
+	Time   time.Time `json:"time"`
+}
+
+// Blob is a signed save file as stored in a Backend, with its revision.
+type Blob struct {
+	Data []byte
+	Rev  Revision
+}
+
+// ConflictError is returned by Backend.Put when the stored revision is not
+// the one the caller based its write on. Current is what is stored now.
+type ConflictError struct {
+	Current Blob
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("save: backend has revision %d by %s", e.Current.Rev.Number, e.Current.Rev.Writer)
+}
+
+// Backend stores the signed bytes of one profile's save file somewhere other
+// than the local save path. Backends do not verify or decode what they store;
+// that is left to the sync layer, so a backend never needs the signing key.
+type Backend interface {
+	// Get returns the stored save and its revision, or ErrNoRemoteSave.
+	Get(ctx context.Context) (Blob, error)
+	// Put stores data as the revision after base. If the stored revision is
+	// not base.Number, nothing is written and a *ConflictError is returned.
+	Put(ctx context.Context, data []byte, base int64, writer string) (Revision, error)
+	// String describes the backend for messages, e.g. its path or URL.
+	String() string
+}
+
+// OpenBackend returns the Backend described by spec: an http:// or https://
+// URL for an HTTPBackend (see NewSyncHandler), or a directory — optionally
+// written as a file:// URL — for a DirBackend.
+func OpenBackend(spec string) (Backend, error) {
+	switch {
+	case spec == "":
+		return nil, fmt.Errorf("save: empty backend")
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewHTTPBackend(spec), nil
+	case strings.HasPrefix(spec, "file://"):
+		return NewDirBackend(strings.TrimPrefix(spec, "file://")), nil
+	case strings.Contains(spec, "://"):
+		return nil, fmt.Errorf("save: unsupported backend %q", spec)
+	}
+	return NewDirBackend(spec), nil
+}
+
+// FileBackend stores a save at Path with its revision in a <Path>.rev file
+// beside it. Only clicker processes on the same machine should write to a
+// FileBackend directly: the revision check and the write are not atomic
+// across machines. Use DirBackend for a shared folder.
+type FileBackend struct {
+	Path string
+}
+
+// RevisionPath returns the revision file kept beside the save at path.
+func RevisionPath(path string) string {
+	return path + ".rev"
+}
+
+func (b FileBackend) String() string { return b.Path }
+
+func (b FileBackend) Get(ctx context.Context) (Blob, error) {
+	data, err := os.ReadFile(b.Path)
+	if os.IsNotExist(err) {
+		return Blob{}, ErrNoRemoteSave
+	}
+	if err != nil {
+		return Blob{}, fmt.Errorf("save: backend read: %w", err)
+	}
+	rev, err := b.revision()
+	if err != nil {
+		return Blob{}, err
+	}
+	// A save copied in by hand has no revision file; it counts as revision 1
+	// so the first sync treats it as existing remote data.
+	rev.Number = max(rev.Number, 1)
+	return Blob{Data: data, Rev: rev}, nil
+}
+
+func (b FileBackend) Put(ctx context.Context, data []byte, base int64, writer string) (Revision, error) {
+	current, err := b.Get(ctx)
+	switch {
+	case errors.Is(err, ErrNoRemoteSave):
+	case err != nil:
+		return Revision{}, err
+	case current.Rev.Number != base:
+		return Revision{}, &ConflictError{Current: current}
+	}
+
+	rev := Revision{Number: base + 1, Writer: writer, Time: time.Now().UTC()}
+	revData, err := json.Marshal(rev)
+	if err != nil {
+		return Revision{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.Path), 0o755); err != nil {
+		return Revision{}, fmt.Errorf("save: backend mkdir: %w", err)
+	}
+	if err := writeFileAtomic(b.Path, data, 0o600); err != nil {
+		return Revision{}, fmt.Errorf("save: backend write: %w", err)
+	}
+	if err := writeFileAtomic(RevisionPath(b.Path), revData, 0o600); err != nil {
+		return Revision{}, fmt.Errorf("save: backend write revision: %w", err)
+	}
+	return rev, nil
+}
+
+func (b FileBackend) revision() (Revision, error) {
+	data, err := os.ReadFile(RevisionPath(b.Path))
+	if os.IsNotExist(err) {
+		return Revision{}, nil
+	}
+	if err != nil {
+		return Revision{}, fmt.Errorf("save: backend read revision: %w", err)
+	}
+	var rev Revision
+	if err := json.Unmarshal(data, &rev); err != nil {
+		return Revision{}, fmt.Errorf("save: backend revision file %q: %w", RevisionPath(b.Path), err)
+	}
+	return rev, nil
+}
+
+// DirBackend mirrors a save into a directory, typically one kept in step
+// between machines by a file-sync tool or a network share. It guards Put
+// with the same advisory lock the game uses for local saves, so two machines
+// syncing at the same moment cannot interleave their writes.
+type DirBackend struct {
+	file FileBackend
+}
+
+// NewDirBackend returns a backend storing save.json in dir.
+func NewDirBackend(dir string) DirBackend {
+	return DirBackend{file: FileBackend{Path: filepath.Join(dir, saveFileName)}}
+}
+
+func (b DirBackend) String() string { return filepath.Dir(b.file.Path) }
+
+func (b DirBackend) Get(ctx context.Context) (Blob, error) {
+	return b.file.Get(ctx)
+}
+
+func (b DirBackend) Put(ctx context.Context, data []byte, base int64, writer string) (Revision, error) {
+	if err := os.MkdirAll(filepath.Dir(b.file.Path), 0o755); err != nil {
+		return Revision{}, fmt.Errorf("save: backend mkdir: %w", err)
+	}
+	lock, err := AcquireLock(b.file.Path)
+	if err != nil {
+		return Revision{}, err
+	}
+	defer lock.Release()
+	return b.file.Put(ctx, data, base, writer)
+}
@@ -0,0 +1,221 @@
+package save
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers of the sync protocol spoken between HTTPBackend and NewSyncHandler.
+//
+//	GET  <url>  200 body=save, Revision headers set; 404 if nothing stored.
+//	PUT  <url>  Base-Revision header names the revision the write is based on.
+//	            200 with the new Revision headers, or 409 with the current
+//	            save and its Revision headers if Base-Revision is stale.
+const (
+	headerRevision     = "X-Clicker-Revision"
+	headerWriter       = "X-Clicker-Writer"
+	headerRevisionTime = "X-Clicker-Revision-Time"
+	headerBaseRevision = "X-Clicker-Base-Revision"
+)
+
+// SyncTokenEnv names the environment variable holding the shared secret sent
+// by HTTPBackend and required by NewSyncHandler, if set.
+const SyncTokenEnv = "CLICKER_SYNC_TOKEN"
+
+// maxSyncBody bounds the size of a save accepted over HTTP.
+const maxSyncBody = 8 << 20
+
+// HTTPBackend stores a save on a server speaking the sync protocol, such as
+// `clicker sync-server`.
+type HTTPBackend struct {
+	URL string
+	// Token, if set, is sent as a bearer token.
+	Token  string
+	Client *http.Client
+}
+
+// NewHTTPBackend returns a backend for url using the token from SyncTokenEnv.
+func NewHTTPBackend(url string) *HTTPBackend {
+	return &HTTPBackend{
+		URL:    url,
+		Token:  strings.TrimSpace(os.Getenv(SyncTokenEnv)),
+		Client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (b *HTTPBackend) String() string { return b.URL }
+
+func (b *HTTPBackend) Get(ctx context.Context) (Blob, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.URL, nil)
+	if err != nil {
+		return Blob{}, err
+	}
+	resp, err := b.do(req)
+	if err != nil {
+		return Blob{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return readBlob(resp)
+	case http.StatusNotFound:
+		return Blob{}, ErrNoRemoteSave
+	}
+	return Blob{}, httpError(resp)
+}
+
+func (b *HTTPBackend) Put(ctx context.Context, data []byte, base int64, writer string) (Revision, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, b.URL, bytes.NewReader(data))
+	if err != nil {
+		return Revision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerBaseRevision, strconv.FormatInt(base, 10))
+	req.Header.Set(headerWriter, writer)
+	resp, err := b.do(req)
+	if err != nil {
+		return Revision{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return readRevision(resp.Header)
+	case http.StatusConflict:
+		current, err := readBlob(resp)
+		if err != nil {
+			return Revision{}, err
+		}
+		return Revision{}, &ConflictError{Current: current}
+	}
+	return Revision{}, httpError(resp)
+}
+
+func (b *HTTPBackend) do(req *http.Request) (*http.Response, error) {
+	if b.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.Token)
+	}
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("save: sync %s: %w", b.URL, err)
+	}
+	return resp, nil
+}
+
+func readBlob(resp *http.Response) (Blob, error) {
+	rev, err := readRevision(resp.Header)
+	if err != nil {
+		return Blob{}, err
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSyncBody))
+	if err != nil {
+		return Blob{}, fmt.Errorf("save: sync read: %w", err)
+	}
+	return Blob{Data: data, Rev: rev}, nil
+}
+
+func readRevision(h http.Header) (Revision, error) {
+	n, err := strconv.ParseInt(h.Get(headerRevision), 10, 64)
+	if err != nil {
+		return Revision{}, fmt.Errorf("save: sync: bad %s header %q", headerRevision, h.Get(headerRevision))
+	}
+	rev := Revision{Number: n, Writer: h.Get(headerWriter)}
+	rev.Time, _ = time.Parse(time.RFC3339, h.Get(headerRevisionTime))
+	return rev, nil
+}
+
+func writeRevision(h http.Header, rev Revision) {
+	h.Set(headerRevision, strconv.FormatInt(rev.Number, 10))
+	h.Set(headerWriter, rev.Writer)
+	h.Set(headerRevisionTime, rev.Time.UTC().Format(time.RFC3339))
+}
+
+func httpError(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("save: sync: server returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// NewSyncHandler serves backend over HTTP for HTTPBackend clients. Writes
+// are serialized, so the revision check in Put is atomic for every client of
+// this handler. If token is non-empty, requests must carry it as a bearer
+// token.
+func NewSyncHandler(backend Backend, token string) http.Handler {
+	return &syncHandler{backend: backend, token: token}
+}
+
+type syncHandler struct {
+	mu      sync.Mutex
+	backend Backend
+	token   string
+}
+
+func (h *syncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// compared in constant time, so response timing gives nothing away.
+	if h.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+h.token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		blob, err := h.backend.Get(r.Context())
+		if errors.Is(err, ErrNoRemoteSave) {
+			http.Error(w, "no save stored", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeRevision(w.Header(), blob.Rev)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(blob.Data)
+
+	case http.MethodPut:
+		base, err := strconv.ParseInt(r.Header.Get(headerBaseRevision), 10, 64)
+		if err != nil {
+			http.Error(w, "missing or bad "+headerBaseRevision, http.StatusBadRequest)
+			return
+		}
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSyncBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.mu.Lock()
+		rev, err := h.backend.Put(r.Context(), data, base, r.Header.Get(headerWriter))
+		h.mu.Unlock()
+		var conflict *ConflictError
+		if errors.As(err, &conflict) {
+			writeRevision(w.Header(), conflict.Current.Rev)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			w.Write(conflict.Current.Data)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeRevision(w.Header(), rev)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,119 @@
+package save
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// FieldDiff is one field that differs between two saves. A and B are the
+// field's values as JSON, or "" where the save has no such field.
+type FieldDiff struct {
+	Path string // dotted JSON path, e.g. "worlds.terra.coins"
+	A, B string
+}
+
+// BookkeepingFields are the top-level SaveFile fields that do not describe
+// gameplay: when and where the save was made, clock bookkeeping, and
+// preferences. Pass them as DiffOptions.Ignore to compare progress only.
+var BookkeepingFields = []string{"version", "saved_at", "last_screen", "last_world_id", "settings", "clock"}
+
+// DiffOptions tune Diff.
+type DiffOptions struct {
+	// Ignore lists top-level fields to leave out, e.g. "saved_at".
+	Ignore []string
+	// Tolerance is the relative difference under which two numbers count as
+	// equal; 0 compares exactly.
+	Tolerance float64
+}
+
+// Diff compares every field of a and b and returns the differences sorted by
+// path. Absent values, zeros and empty collections count as equal, so a map
+// omitted in one save and empty in the other is not a difference.
+func Diff(a, b SaveFile, opts DiffOptions) []FieldDiff {
+	var diffs []FieldDiff
+	diffValues("", diffTree(a, opts.Ignore), diffTree(b, opts.Ignore), opts.Tolerance, &diffs)
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].Path < diffs[j].Path })
+	return diffs
+}
+
+// diffTree converts sf to generic JSON values for comparison.
+func diffTree(sf SaveFile, ignore []string) map[string]any {
+	var tree map[string]any
+	data, _ := json.Marshal(sf)
+	_ = json.Unmarshal(data, &tree)
+	for _, f := range ignore {
+		delete(tree, f)
+	}
+	return tree
+}
+
+func diffValues(path string, a, b any, tolerance float64, diffs *[]FieldDiff) {
+	am, aMap := a.(map[string]any)
+	bm, bMap := b.(map[string]any)
+	if (aMap || a == nil) && (bMap || b == nil) && (aMap || bMap) {
+		// descend, so that a map missing on one side lists its fields.
+		keys := make(map[string]bool)
+		for k := range am {
+			keys[k] = true
+		}
+		for k := range bm {
+			keys[k] = true
+		}
+		for k := range keys {
+			diffValues(joinPath(path, k), am[k], bm[k], tolerance, diffs)
+		}
+		return
+	}
+	if af, ok := a.(float64); ok {
+		if bf, ok := b.(float64); ok && closeEnough(af, bf, tolerance) {
+			return
+		}
+	}
+	if isZero(a) && isZero(b) {
+		return
+	}
+	aj, bj := jsonText(a), jsonText(b)
+	if aj == bj {
+		return
+	}
+	*diffs = append(*diffs, FieldDiff{Path: path, A: aj, B: bj})
+}
+
+func closeEnough(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+// isZero treats absent values and empty collections alike.
+func isZero(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func jsonText(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
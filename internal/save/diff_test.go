@@ -0,0 +1,29 @@
+package save
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff_FieldLevel(t *testing.T) {
+	a := DefaultSaveFile()
+	a.Worlds = map[string]WorldSaveData{"terra": {WorldID: "terra", Coins: 10, BuyOnCounts: map[string]int{}}}
+	b := DefaultSaveFile()
+	b.SavedAt = a.SavedAt
+	b.Player.Level = 3
+	b.Worlds = map[string]WorldSaveData{
+		"terra": {WorldID: "terra", Coins: 10 + 1e-12, BuyOnCounts: map[string]int{"auto_miner": 2}},
+		"aqua":  {WorldID: "aqua", Coins: 5},
+	}
+
+	diffs := Diff(a, b, DiffOptions{Ignore: BookkeepingFields, Tolerance: 1e-9})
+	assert.Equal(t, []FieldDiff{
+		{Path: "player.level", A: "1", B: "3"},
+		{Path: "worlds.aqua.coins", A: "", B: "5"},
+		{Path: "worlds.aqua.world_id", A: "", B: `"aqua"`},
+		{Path: "worlds.terra.buy_on_counts.auto_miner", A: "", B: "2"},
+	}, diffs)
+
+	assert.Len(t, Diff(a, b, DiffOptions{Ignore: BookkeepingFields}), 5, "without tolerance the coin noise shows")
+}
@@ -0,0 +1,154 @@
+package save
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// sealEnvelope signs a SaveFile's JSON payload with the install key and
+// returns the envelope as written to disk. If the install key cannot be
+// loaded the legacy key is used, so a read-only config directory never stops
+// the game from saving.
+func sealEnvelope(payload []byte) ([]byte, error) {
+	encoded := base64.StdEncoding.EncodeToString(payload)
+	envelope := signedEnvelope{Format: EnvelopeFormat, Alg: AlgHMACSHA256, Data: encoded}
+	if key, err := currentInstallKey(); err == nil {
+		envelope.KeyID = key.ID
+		envelope.Sig = signWith(key.Secret, []byte(encoded))
+	} else {
+		log.Printf("save: no install key, signing with the legacy key: %v", err)
+		envelope.KeyID = LegacyKeyID
+		envelope.Sig = sign([]byte(encoded))
+	}
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("save: marshal envelope: %w", err)
+	}
+	return data, nil
+}
+
+// openEnvelope parses a signed save file and returns its JSON payload. The
+// signature is checked unless trusted is set. Envelope formats and
+// algorithms this build does not know return ErrNewerVersion, and a key it
+// does not have returns ErrUnknownKey.
+func openEnvelope(data []byte, trusted bool) ([]byte, signedEnvelope, error) {
+	var envelope signedEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Data == "" {
+		return nil, envelope, fmt.Errorf("unrecognised save format")
+	}
+	if envelope.Format > EnvelopeFormat {
+		return nil, envelope, fmt.Errorf("%w: envelope format %d", ErrNewerVersion, envelope.Format)
+	}
+	if envelope.Format >= 2 && envelope.Alg != AlgHMACSHA256 {
+		return nil, envelope, fmt.Errorf("%w: signature algorithm %q", ErrNewerVersion, envelope.Alg)
+	}
+
+	if !trusted {
+		key, err := envelopeKey(envelope.KeyID)
+		if err != nil {
+			return nil, envelope, err
+		}
+		if !verifyWith(key, []byte(envelope.Data), envelope.Sig) {
+			return nil, envelope, fmt.Errorf("signature mismatch — file may have been tampered with")
+		}
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(envelope.Data)
+	if err != nil {
+		return nil, envelope, fmt.Errorf("base64 decode failed: %w", err)
+	}
+	return payload, envelope, nil
+}
+
+// envelopeKey returns the secret for a key ID found in an envelope.
+func envelopeKey(id string) ([]byte, error) {
+	if id == "" || id == LegacyKeyID {
+		return []byte(hmacKey), nil
+	}
+	if key, err := currentInstallKey(); err == nil && key.ID == id {
+		return key.Secret, nil
+	}
+	return nil, fmt.Errorf("%w (key %s)", ErrUnknownKey, id)
+}
+
+// SignatureInfo describes how a save file is signed.
+type SignatureInfo struct {
+	// Format is the envelope format; 1 for saves from before key IDs.
+	Format int
+	KeyID  string
+	Alg    string
+	// Legacy is true if the save is signed with the key embedded in every
+	// build rather than an install key. It is re-signed on the next save.
+	Legacy bool
+	// Err is nil if the signature verified with a key this install has.
+	Err error
+}
+
+// InspectSignature reports how the save file data is signed and whether
+// this install can verify it.
+func InspectSignature(data []byte) SignatureInfo {
+	_, envelope, err := openEnvelope(data, false)
+	info := SignatureInfo{
+		Format: max(envelope.Format, 1),
+		KeyID:  envelope.KeyID,
+		Alg:    envelope.Alg,
+		Legacy: envelope.KeyID == "" || envelope.KeyID == LegacyKeyID,
+		Err:    err,
+	}
+	if info.Format == 1 {
+		info.KeyID, info.Alg = LegacyKeyID, AlgHMACSHA256
+	}
+	return info
+}
+
+// DecodeTrusted decodes and migrates a save file without checking its
+// signature. It is the explicit "trust this save" path for saves moved from
+// another install; writing the result with WriteSaveFile re-signs it with
+// this install's key.
+func DecodeTrusted(data []byte) (SaveFile, error) {
+	sf, _, err := decodeWith(data, true)
+	return sf, err
+}
+
+// Inspection is everything that can be read from a save file's bytes, for
+// diagnosing a save that will not load.
+type Inspection struct {
+	Signature SignatureInfo
+	// Version is the schema version the payload was written with.
+	Version int
+	// Save is the payload migrated to CurrentVersion, decoded without
+	// checking the signature. It is only valid if Err is nil.
+	Save SaveFile
+	// Err says why the payload could not be decoded.
+	Err error
+}
+
+// Inspect decodes data as far as it can, whether or not the signature
+// verifies.
+func Inspect(data []byte) Inspection {
+	sf, from, err := decodeWith(data, true)
+	return Inspection{Signature: InspectSignature(data), Version: from, Save: sf, Err: err}
+}
+
+// decodeAdopted decodes a save file from outside this install, such as one
+// pulled from a sync remote. A save signed by another install is read only
+// if trust, given the key ID and the decoded save, vouches for it; otherwise
+// ErrUnknownKey is returned. A signature that fails under a key this install
+// has still means tampering and is rejected.
+func decodeAdopted(data []byte, trust func(keyID string, sf SaveFile) bool) (SaveFile, error) {
+	sf, _, err := decodeWith(data, false)
+	if !errors.Is(err, ErrUnknownKey) {
+		return sf, err
+	}
+	trusted, terr := DecodeTrusted(data)
+	if terr != nil {
+		return SaveFile{}, terr
+	}
+	if trust == nil || !trust(InspectSignature(data).KeyID, trusted) {
+		return SaveFile{}, err
+	}
+	return trusted, nil
+}
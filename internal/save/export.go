@@ -0,0 +1,182 @@
+package save
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/clicker-org/clicker/internal/economy"
+)
+
+// ExportPrefix starts every export code. The trailing digit is the code
+// format version, independent of the SaveFile schema version.
+const ExportPrefix = "CLICKER1-"
+
+// maxExportPayload bounds the decompressed size of an export code so that a
+// malicious code cannot exhaust memory.
+const maxExportPayload = 4 << 20
+
+// ErrInvalidExportCode is returned by ParseExport for codes that are not
+// well-formed (truncated, mistyped or not an export code at all).
+var ErrInvalidExportCode = errors.New("save: invalid export code")
+
+// exportPayload is the JSON carried inside an export code. Data is the
+// compact JSON of a SaveFile and Sig its HMAC-SHA256 under the legacy key
+// rather than the install key, so that any install can verify the code.
+type exportPayload struct {
+	Version int             `json:"v"`
+	Data    json.RawMessage `json:"data"`
+	Sig     string          `json:"sig"`
+}
+
+// Export encodes sf as a compact text code that survives copy-paste: the
+// signed payload is deflate-compressed and base64url-encoded, so the code is a
+// single line of [A-Za-z0-9_-] after ExportPrefix.
+func Export(sf SaveFile) (string, error) {
+	data, err := json.Marshal(sf)
+	if err != nil {
+		return "", fmt.Errorf("save: export marshal: %w", err)
+	}
+	return encodeExport(exportPayload{Version: sf.Version, Data: data, Sig: sign(data)})
+}
+
+// encodeExport compresses and encodes a payload as an export code.
+func encodeExport(p exportPayload) (string, error) {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("save: export marshal: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", fmt.Errorf("save: export compress: %w", err)
+	}
+	if _, err := zw.Write(payload); err != nil {
+		return "", fmt.Errorf("save: export compress: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return "", fmt.Errorf("save: export compress: %w", err)
+	}
+	return ExportPrefix + base64.RawURLEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ParseExport decodes an export code produced by Export, verifies its
+// signature and migrates it to CurrentVersion. Whitespace anywhere in the code
+// is ignored, so codes wrapped by a terminal or mail client still import.
+func ParseExport(code string) (SaveFile, error) {
+	code = strings.Join(strings.Fields(code), "")
+	if !strings.HasPrefix(code, ExportPrefix) {
+		return SaveFile{}, fmt.Errorf("%w: missing %q prefix", ErrInvalidExportCode, ExportPrefix)
+	}
+	compressed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, ExportPrefix))
+	if err != nil {
+		return SaveFile{}, fmt.Errorf("%w: %v", ErrInvalidExportCode, err)
+	}
+	zr := flate.NewReader(bytes.NewReader(compressed))
+	defer zr.Close()
+	raw, err := io.ReadAll(io.LimitReader(zr, maxExportPayload+1))
+	if err != nil {
+		return SaveFile{}, fmt.Errorf("%w: %v", ErrInvalidExportCode, err)
+	}
+	if len(raw) > maxExportPayload {
+		return SaveFile{}, fmt.Errorf("%w: payload too large", ErrInvalidExportCode)
+	}
+
+	var payload exportPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || len(payload.Data) == 0 {
+		return SaveFile{}, fmt.Errorf("%w: unrecognised payload", ErrInvalidExportCode)
+	}
+	if !verify(payload.Data, payload.Sig) {
+		return SaveFile{}, fmt.Errorf("save: export code signature mismatch — code may have been edited")
+	}
+	migrated, from, err := MigrateRaw(payload.Data)
+	if err != nil {
+		return SaveFile{}, err
+	}
+	if from != max(payload.Version, 1) {
+		return SaveFile{}, fmt.Errorf("%w: version mismatch", ErrInvalidExportCode)
+	}
+	var sf SaveFile
+	if err := json.Unmarshal(migrated, &sf); err != nil {
+		return SaveFile{}, fmt.Errorf("%w: corrupt save data: %v", ErrInvalidExportCode, err)
+	}
+	return sf, nil
+}
+
+// Import writes an imported save to path, replacing the save there (which
+// is kept in the rotating backups). The local settings are kept, and the save
+// and clock times are reset to now: time between export and import is not
+// credited as offline progress, and the other install's clock is not trusted.
+// It returns the SaveFile as written.
+func Import(sf SaveFile, settings Settings, path string) (SaveFile, error) {
+	now := time.Now()
+	sf.Settings = settings
+	sf.SavedAt = now
+	sf.Clock.LastWall = now
+	if err := WriteSaveFile(sf, path); err != nil {
+		return SaveFile{}, err
+	}
+	return sf, nil
+}
+
+// Change is one line of an import preview: a field and its value in the
+// current save and in the save being imported.
+type Change struct {
+	Field string
+	From  string
+	To    string
+}
+
+// Changed reports whether the value differs between the two saves.
+func (c Change) Changed() bool { return c.From != c.To }
+
+// PreviewImport compares the player level, general coins and every world's
+// coins and prestige count between current and incoming, so the player can
+// see what an import would overwrite. Worlds are listed in ID order.
+func PreviewImport(current, incoming SaveFile) []Change {
+	changes := []Change{
+		{"Level", fmt.Sprint(current.Player.Level), fmt.Sprint(incoming.Player.Level)},
+		{"General Coins", economy.FormatCoinsBare(current.Player.GeneralCoins), economy.FormatCoinsBare(incoming.Player.GeneralCoins)},
+		{"Achievements", fmt.Sprint(countTrue(current.Achievements)), fmt.Sprint(countTrue(incoming.Achievements))},
+	}
+
+	ids := make(map[string]bool)
+	for id := range current.Worlds {
+		ids[id] = true
+	}
+	for id := range incoming.Worlds {
+		ids[id] = true
+	}
+	sorted := make([]string, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Strings(sorted)
+
+	for _, id := range sorted {
+		cur, inc := current.Worlds[id], incoming.Worlds[id]
+		changes = append(changes,
+			Change{id + " coins", economy.FormatCoinsBare(cur.Coins), economy.FormatCoinsBare(inc.Coins)},
+			Change{id + " prestiges", fmt.Sprint(cur.PrestigeCount), fmt.Sprint(inc.PrestigeCount)},
+		)
+	}
+	return changes
+}
+
+func countTrue(m map[string]bool) int {
+	n := 0
+	for _, v := range m {
+		if v {
+			n++
+		}
+	}
+	return n
+}
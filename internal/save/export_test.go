@@ -0,0 +1,129 @@
+package save
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportFixture() SaveFile {
+	sf := DefaultSaveFile()
+	sf.Player.Level = 5
+	sf.Player.GeneralCoins = 1500
+	sf.Achievements["first_click"] = true
+	sf.Worlds["terra"] = WorldSaveData{WorldID: "terra", Coins: 2e6, PrestigeCount: 2}
+	return sf
+}
+
+func TestExport_Roundtrip(t *testing.T) {
+	code, err := Export(exportFixture())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(code, ExportPrefix))
+	assert.NotContains(t, code, " ")
+
+	sf, err := ParseExport(code)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, sf.Version)
+	assert.Equal(t, 5, sf.Player.Level)
+	assert.InDelta(t, 1500, sf.Player.GeneralCoins, 0.001)
+	assert.Equal(t, 2, sf.Worlds["terra"].PrestigeCount)
+	assert.True(t, sf.Achievements["first_click"])
+}
+
+func TestParseExport_IgnoresWhitespace(t *testing.T) {
+	code, err := Export(exportFixture())
+	require.NoError(t, err)
+
+	var wrapped strings.Builder
+	for i := 0; i < len(code); i += 40 {
+		wrapped.WriteString(code[i:min(i+40, len(code))] + "\n  ")
+	}
+	sf, err := ParseExport(wrapped.String())
+	require.NoError(t, err)
+	assert.Equal(t, 5, sf.Player.Level)
+}
+
+func TestParseExport_Rejects(t *testing.T) {
+	code, err := Export(exportFixture())
+	require.NoError(t, err)
+
+	_, err = ParseExport("hello")
+	assert.ErrorIs(t, err, ErrInvalidExportCode)
+
+	_, err = ParseExport(code[:len(code)/2])
+	assert.ErrorIs(t, err, ErrInvalidExportCode)
+
+	_, err = ParseExport(ExportPrefix + base64.RawURLEncoding.EncodeToString([]byte("not deflate")))
+	assert.ErrorIs(t, err, ErrInvalidExportCode)
+}
+
+func TestParseExport_RejectsEditedSave(t *testing.T) {
+	data := []byte(`{"version":1,"player":{"level":99}}`)
+	code, err := encodeExport(exportPayload{Version: 1, Data: data, Sig: sign([]byte(`{"version":1}`))})
+	require.NoError(t, err)
+
+	_, err = ParseExport(code)
+	assert.ErrorContains(t, err, "signature mismatch")
+
+	code, err = encodeExport(exportPayload{Version: 1, Data: data, Sig: sign(data)})
+	require.NoError(t, err)
+	sf, err := ParseExport(code)
+	require.NoError(t, err)
+	assert.Equal(t, 99, sf.Player.Level)
+}
+
+func TestParseExport_RejectsNewerVersion(t *testing.T) {
+	sf := exportFixture()
+	sf.Version = CurrentVersion + 1
+	code, err := Export(sf)
+	require.NoError(t, err)
+	_, err = ParseExport(code)
+	assert.ErrorIs(t, err, ErrNewerVersion)
+}
+
+func TestImport_ResetsTimesAndKeepsLocalSettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	saveWithXP(t, path, 1)
+
+	incoming := exportFixture()
+	incoming.SavedAt = time.Now().Add(-72 * time.Hour)
+	incoming.Settings.ActiveTheme = "other"
+	local := Settings{AnimationsEnabled: false, ActiveTheme: "space"}
+
+	written, err := Import(incoming, local, path)
+	require.NoError(t, err)
+	assert.Equal(t, local, written.Settings)
+	assert.WithinDuration(t, time.Now(), written.SavedAt, time.Minute)
+
+	sf, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 5, sf.Player.Level)
+	assert.Equal(t, "space", sf.Settings.ActiveTheme)
+
+	backup, err := Load(BackupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, backup.Player.XP)
+}
+
+func TestPreviewImport(t *testing.T) {
+	current := DefaultSaveFile()
+	current.Player.Level = 2
+	current.Worlds["aqua"] = WorldSaveData{WorldID: "aqua", Coins: 10}
+
+	changes := PreviewImport(current, exportFixture())
+	byField := make(map[string]Change)
+	for _, c := range changes {
+		byField[c.Field] = c
+	}
+	assert.Equal(t, Change{"Level", "2", "5"}, byField["Level"])
+	assert.True(t, byField["General Coins"].Changed())
+	assert.Equal(t, "10", byField["aqua coins"].From)
+	assert.Equal(t, "0", byField["aqua coins"].To)
+	assert.Equal(t, "2.00M", byField["terra coins"].To)
+	assert.False(t, Change{"x", "1", "1"}.Changed())
+}
@@ -10,23 +10,38 @@ import (
 // This deters casual tampering; it is not a cryptographic secret — a
 // determined user could extract it from the binary. Server-side key
 // management would be required for stronger guarantees (e.g. leaderboards).
+//
+// Saves are now signed with the per-install key (see InstallKey); hmacKey is
+// kept as LegacyKeyID to verify older saves and to sign export codes, which
+// must be readable by any install.
 const hmacKey = "clicker-save-hmac-v1-5f3a8c2d9e1b7f4a6c0e2d8b"
 
-// sign computes HMAC-SHA256 over data and returns the result as a lowercase hex string.
+// sign computes HMAC-SHA256 over data with the legacy key and returns the
+// result as a lowercase hex string.
 func sign(data []byte) string {
-	mac := hmac.New(sha256.New, []byte(hmacKey))
+	return signWith([]byte(hmacKey), data)
+}
+
+// verify reports whether sig is a valid legacy-key signature of data.
+func verify(data []byte, sig string) bool {
+	return verifyWith([]byte(hmacKey), data, sig)
+}
+
+// signWith computes HMAC-SHA256 over data with key as a lowercase hex string.
+func signWith(key, data []byte) string {
+	mac := hmac.New(sha256.New, key)
 	mac.Write(data)
 	return hex.EncodeToString(mac.Sum(nil))
 }
 
-// verify reports whether sig is a valid HMAC-SHA256 signature of data.
-// Uses constant-time comparison to prevent timing attacks.
-func verify(data []byte, sig string) bool {
+// verifyWith reports whether sig is a valid HMAC-SHA256 signature of data
+// under key. Uses constant-time comparison to prevent timing attacks.
+func verifyWith(key, data []byte, sig string) bool {
 	sigBytes, err := hex.DecodeString(sig)
 	if err != nil {
 		return false
 	}
-	mac := hmac.New(sha256.New, []byte(hmacKey))
+	mac := hmac.New(sha256.New, key)
 	mac.Write(data)
 	expected := mac.Sum(nil)
 	return hmac.Equal(expected, sigBytes)
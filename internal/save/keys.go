@@ -0,0 +1,129 @@
+package save
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// EnvelopeFormat is the current version of the signed envelope itself,
+// independent of the SaveFile schema version. Envelopes without a format
+// field are format 1: legacy key, HMAC-SHA256, no key ID.
+const EnvelopeFormat = 2
+
+// AlgHMACSHA256 is the only signature algorithm so far.
+const AlgHMACSHA256 = "HS256"
+
+// LegacyKeyID names the key embedded in every build (hmacKey). Saves signed
+// with it are still accepted, and are re-signed with the install key on the
+// next save.
+const LegacyKeyID = "legacy"
+
+// ErrUnknownKey is returned for a save signed by a key this install does not
+// have, typically a save copied from another machine. Such saves are left
+// untouched; DecodeTrusted reads them when the player vouches for them.
+var ErrUnknownKey = errors.New("save: signed by another install")
+
+// InstallKey is the secret this install signs its saves with. It is generated
+// on first run and never leaves the machine; saves move between installs
+// through export codes, sync, or an explicit trust.
+type InstallKey struct {
+	ID     string
+	Secret []byte
+}
+
+// installKeyFile is the JSON form of an InstallKey on disk.
+type installKeyFile struct {
+	ID      string    `json:"id"`
+	Secret  string    `json:"secret"`
+	Created time.Time `json:"created"`
+}
+
+// InstallKeyPath returns where the install key is stored.
+func InstallKeyPath() string {
+	return filepath.Join(configDir(), "install.key")
+}
+
+// LoadInstallKey reads the install key at path, generating and storing a new
+// one if none exists yet.
+func LoadInstallKey(path string) (InstallKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		var f installKeyFile
+		if err := json.Unmarshal(data, &f); err != nil {
+			return InstallKey{}, fmt.Errorf("save: install key %q: %w", path, err)
+		}
+		secret, err := hex.DecodeString(f.Secret)
+		if err != nil || len(secret) < 16 || f.ID == "" {
+			return InstallKey{}, fmt.Errorf("save: install key %q is malformed", path)
+		}
+		return InstallKey{ID: f.ID, Secret: secret}, nil
+	}
+	if !os.IsNotExist(err) {
+		return InstallKey{}, fmt.Errorf("save: read install key: %w", err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return InstallKey{}, fmt.Errorf("save: generate install key: %w", err)
+	}
+	key := InstallKey{ID: keyID(secret), Secret: secret}
+	data, err = json.MarshalIndent(installKeyFile{ID: key.ID, Secret: hex.EncodeToString(secret), Created: time.Now().UTC()}, "", "  ")
+	if err != nil {
+		return InstallKey{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return InstallKey{}, fmt.Errorf("save: mkdir: %w", err)
+	}
+	// O_EXCL, so two processes racing on first run agree on one key.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if os.IsExist(err) {
+		return LoadInstallKey(path)
+	}
+	if err != nil {
+		return InstallKey{}, fmt.Errorf("save: write install key: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return InstallKey{}, fmt.Errorf("save: write install key: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return InstallKey{}, fmt.Errorf("save: write install key: %w", err)
+	}
+	return key, nil
+}
+
+// keyID derives a short public identifier from a secret.
+func keyID(secret []byte) string {
+	sum := sha256.Sum256(secret)
+	return "i-" + hex.EncodeToString(sum[:6])
+}
+
+var (
+	installKeyMu sync.Mutex
+	installKey   *InstallKey
+)
+
+// currentInstallKey returns the install key, loading it from InstallKeyPath
+// on first use. A failed load is retried on the next call.
+func currentInstallKey() (InstallKey, error) {
+	installKeyMu.Lock()
+	defer installKeyMu.Unlock()
+	if installKey != nil {
+		return *installKey, nil
+	}
+	key, err := LoadInstallKey(InstallKeyPath())
+	if err != nil {
+		return InstallKey{}, err
+	}
+	installKey = &key
+	return key, nil
+}
@@ -0,0 +1,146 @@
+package save
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testInstallKey is the install key every test in this package signs with,
+// so tests never touch the real config directory.
+var testInstallKey = InstallKey{ID: "i-test", Secret: []byte("test-install-secret-0123456789ab")}
+
+func TestMain(m *testing.M) {
+	installKey = &testInstallKey
+	os.Exit(m.Run())
+}
+
+// useInstallKey makes key the install key for the rest of the test.
+func useInstallKey(t *testing.T, key InstallKey) {
+	t.Helper()
+	installKeyMu.Lock()
+	prev := installKey
+	installKey = &key
+	installKeyMu.Unlock()
+	t.Cleanup(func() {
+		installKeyMu.Lock()
+		installKey = prev
+		installKeyMu.Unlock()
+	})
+}
+
+func readEnvelope(t *testing.T, path string) signedEnvelope {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var env signedEnvelope
+	require.NoError(t, json.Unmarshal(data, &env))
+	return env
+}
+
+func TestLoadInstallKey_GeneratedOnceAndStable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "install.key")
+	first, err := LoadInstallKey(path)
+	require.NoError(t, err)
+	assert.Len(t, first.Secret, 32)
+	assert.Equal(t, keyID(first.Secret), first.ID)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	second, err := LoadInstallKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestWriteSaveFile_SignsWithInstallKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	require.NoError(t, WriteSaveFile(DefaultSaveFile(), path))
+
+	env := readEnvelope(t, path)
+	assert.Equal(t, EnvelopeFormat, env.Format)
+	assert.Equal(t, testInstallKey.ID, env.KeyID)
+	assert.Equal(t, AlgHMACSHA256, env.Alg)
+
+	_, err := Load(path)
+	require.NoError(t, err)
+}
+
+func TestLoad_LegacyEnvelopeResignedOnNextSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	writeSignedPayload(t, path, readFixture(t, CurrentVersion))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	info := InspectSignature(data)
+	require.NoError(t, info.Err)
+	assert.True(t, info.Legacy)
+	assert.Equal(t, 1, info.Format)
+
+	sf, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, WriteSaveFile(sf, path))
+	assert.Equal(t, testInstallKey.ID, readEnvelope(t, path).KeyID)
+}
+
+func TestLoad_OtherInstallLeftUntouched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	useInstallKey(t, InstallKey{ID: "i-other", Secret: []byte("another-install-secret-abcdefgh")})
+	other := DefaultSaveFile()
+	other.Player.Level = 6
+	require.NoError(t, WriteSaveFile(other, path))
+	useInstallKey(t, testInstallKey)
+	original, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	_, rec, err := LoadWithRecovery(path)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	assert.False(t, rec.Recovered(), "a save from another install is not corrupt")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, data)
+
+	// trusting it explicitly reads it, and writing re-signs it for this install.
+	sf, err := DecodeTrusted(data)
+	require.NoError(t, err)
+	assert.Equal(t, 6, sf.Player.Level)
+	require.NoError(t, WriteSaveFile(sf, path))
+	_, err = Load(path)
+	require.NoError(t, err)
+}
+
+func TestLoad_TamperedInstallSignature(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	require.NoError(t, WriteSaveFile(DefaultSaveFile(), path))
+	env := readEnvelope(t, path)
+	env.Sig = signWith([]byte("wrong key"), []byte(env.Data))
+	data, err := json.Marshal(env)
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, InspectSignature(data).Err, "signature mismatch")
+	_, err = decodeAdopted(data, func(string, SaveFile) bool { return true })
+	assert.Error(t, err, "a known key that fails to verify is never adopted")
+}
+
+func TestLoad_NewerEnvelopeLeftUntouched(t *testing.T) {
+	for name, env := range map[string]signedEnvelope{
+		"format":    {Format: EnvelopeFormat + 1, Alg: AlgHMACSHA256, Data: "e30=", Sig: "00"},
+		"algorithm": {Format: EnvelopeFormat, Alg: "Ed25519", Data: "e30=", Sig: "00"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "save.json")
+			data, err := json.Marshal(env)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, data, 0o600))
+
+			_, rec, err := LoadWithRecovery(path)
+			assert.ErrorIs(t, err, ErrNewerVersion)
+			assert.False(t, rec.Recovered())
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package save
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LockStaleAfter is how long a lock held from another host may go without a
+// Refresh before it is considered abandoned. Running games refresh the lock
+// on every autosave, well within this window.
+const LockStaleAfter = 2 * time.Minute
+
+// ErrLocked is matched (via errors.Is) by the *LockedError AcquireLock
+// returns when another live process holds the lock.
+var ErrLocked = errors.New("save: profile is in use by another clicker")
+
+// LockInfo identifies the process holding a save lock.
+type LockInfo struct {
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+func (i LockInfo) String() string {
+	return fmt.Sprintf("pid %d on %s since %s", i.PID, i.Host, i.Started.Local().Format("15:04"))
+}
+
+// LockedError reports who holds a save lock.
+type LockedError struct {
+	Holder LockInfo
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%v (%s)", ErrLocked, e.Holder)
+}
+
+func (e *LockedError) Is(target error) bool { return target == ErrLocked }
+
+// Lock is an advisory lock on a save file, held as a <save>.lock file naming
+// the owning process. It only guards against other clicker processes that
+// also use it; it is not an OS-level file lock.
+type Lock struct {
+	path string
+	info LockInfo
+}
+
+// LockPath returns the lock file path for the save at savePath.
+func LockPath(savePath string) string {
+	return savePath + ".lock"
+}
+
+func currentLockInfo() LockInfo {
+	host, _ := os.Hostname()
+	return LockInfo{PID: os.Getpid(), Host: host, Started: time.Now()}
+}
+
+// AcquireLock takes the lock for the save at savePath. If another live
+// process holds it, a *LockedError is returned. Locks left behind by crashed
+// processes (see ReadLock) are removed and taken over.
+func AcquireLock(savePath string) (*Lock, error) {
+	path := LockPath(savePath)
+	info := currentLockInfo()
+	for attempt := 0; attempt < 2; attempt++ {
+		err := writeLockExclusive(path, info)
+		if err == nil {
+			return &Lock{path: path, info: info}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("save: lock: %w", err)
+		}
+		holder, live, err := ReadLock(savePath)
+		if err != nil {
+			return nil, err
+		}
+		if live {
+			return nil, &LockedError{Holder: holder}
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("save: remove stale lock: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("save: lock: could not acquire %q", path)
+}
+
+// ForceLock takes the lock for the save at savePath regardless of who holds
+// it. The previous holder notices on its next Held check and stops saving.
+func ForceLock(savePath string) (*Lock, error) {
+	path := LockPath(savePath)
+	info := currentLockInfo()
+	data, err := json.Marshal(info)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(path, data, 0o600); err != nil {
+		return nil, fmt.Errorf("save: lock: %w", err)
+	}
+	return &Lock{path: path, info: info}, nil
+}
+
+// ReadLock returns the current holder of the lock for the save at savePath
+// and whether that holder is still live. A lock is stale if its process no
+// longer exists (same host), if it has not been refreshed for LockStaleAfter
+// (other hosts), or if the lock file is unreadable. A missing lock returns
+// live == false and a zero LockInfo.
+func ReadLock(savePath string) (LockInfo, bool, error) {
+	path := LockPath(savePath)
+	st, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return LockInfo{}, false, nil
+	}
+	if err != nil {
+		return LockInfo{}, false, fmt.Errorf("save: lock: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return LockInfo{}, false, fmt.Errorf("save: lock: %w", err)
+	}
+	var info LockInfo
+	if err := json.Unmarshal(data, &info); err != nil || info.PID <= 0 {
+		return LockInfo{}, false, nil
+	}
+
+	host, _ := os.Hostname()
+	if info.Host == host {
+		return info, info.PID == os.Getpid() || processAlive(info.PID), nil
+	}
+	return info, time.Since(st.ModTime()) < LockStaleAfter, nil
+}
+
+// Held reports whether this process still owns the lock. It is false after
+// another process took over with ForceLock.
+func (l *Lock) Held() bool {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		return false
+	}
+	var info LockInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false
+	}
+	return info.PID == l.info.PID && info.Host == l.info.Host && info.Started.Equal(l.info.Started)
+}
+
+// Refresh marks the lock as live, so instances on other hosts sharing the
+// save directory do not consider it stale.
+func (l *Lock) Refresh() error {
+	now := time.Now()
+	return os.Chtimes(l.path, now, now)
+}
+
+// Release removes the lock file if this process still owns it.
+func (l *Lock) Release() error {
+	if !l.Held() {
+		return nil
+	}
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// writeLockExclusive creates path with info, failing if it already exists.
+// The lock is written to a temporary file and linked into place, so other
+// processes never see it empty and mistake it for a stale one.
+func writeLockExclusive(path string, info LockInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Link(tmp.Name(), path)
+}
@@ -0,0 +1,149 @@
+package save
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLockFile plants a lock file as if written by another process.
+func writeLockFile(t *testing.T, savePath string, info LockInfo) {
+	t.Helper()
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(LockPath(savePath), data, 0o600))
+}
+
+// deadPID returns a PID that is not running on this host.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	for pid := 999_999; pid > 900_000; pid-- {
+		if !processAlive(pid) {
+			return pid
+		}
+	}
+	t.Skip("no free PID found")
+	return 0
+}
+
+func TestAcquireLock_ExclusiveAndRelease(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+
+	lock, err := AcquireLock(path)
+	require.NoError(t, err)
+	assert.True(t, lock.Held())
+
+	// A lock naming this process is live, so a second acquire must fail.
+	holder, live, err := ReadLock(path)
+	require.NoError(t, err)
+	assert.True(t, live)
+	assert.Equal(t, os.Getpid(), holder.PID)
+
+	_, err = AcquireLock(path)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, lock.Release())
+	_, err = os.Stat(LockPath(path))
+	assert.True(t, os.IsNotExist(err))
+
+	lock, err = AcquireLock(path)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release())
+}
+
+func TestAcquireLock_ConcurrentTakersGetOne(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	var wg sync.WaitGroup
+	var acquired atomic.Int32
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := AcquireLock(path); err == nil {
+				acquired.Add(1)
+			} else {
+				assert.ErrorIs(t, err, ErrLocked, "a lock being written is never taken for a stale one")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), acquired.Load())
+	matches, err := filepath.Glob(LockPath(path) + ".tmp-*")
+	require.NoError(t, err)
+	assert.Empty(t, matches, "temporary lock files are cleaned up")
+}
+
+func TestAcquireLock_TakesOverStaleLocks(t *testing.T) {
+	host, _ := os.Hostname()
+
+	t.Run("dead process on this host", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "save.json")
+		writeLockFile(t, path, LockInfo{PID: deadPID(t), Host: host, Started: time.Now()})
+		lock, err := AcquireLock(path)
+		require.NoError(t, err)
+		assert.True(t, lock.Held())
+	})
+
+	t.Run("other host not refreshed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "save.json")
+		writeLockFile(t, path, LockInfo{PID: 42, Host: "elsewhere", Started: time.Now()})
+		old := time.Now().Add(-2 * LockStaleAfter)
+		require.NoError(t, os.Chtimes(LockPath(path), old, old))
+		_, err := AcquireLock(path)
+		require.NoError(t, err)
+	})
+
+	t.Run("garbage lock file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "save.json")
+		require.NoError(t, os.WriteFile(LockPath(path), []byte("???"), 0o600))
+		_, err := AcquireLock(path)
+		require.NoError(t, err)
+	})
+}
+
+func TestAcquireLock_LiveOtherHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	writeLockFile(t, path, LockInfo{PID: 42, Host: "elsewhere", Started: time.Now()})
+
+	_, err := AcquireLock(path)
+	var locked *LockedError
+	require.ErrorAs(t, err, &locked)
+	assert.Equal(t, "elsewhere", locked.Holder.Host)
+	assert.Equal(t, 42, locked.Holder.PID)
+}
+
+func TestForceLock_PreviousHolderLosesLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	first, err := AcquireLock(path)
+	require.NoError(t, err)
+
+	// Started differs, so the second lock is distinguishable even in-process.
+	time.Sleep(time.Millisecond)
+	second, err := ForceLock(path)
+	require.NoError(t, err)
+
+	assert.False(t, first.Held())
+	assert.True(t, second.Held())
+
+	// Releasing the lost lock must not remove the new holder's lock file.
+	require.NoError(t, first.Release())
+	assert.True(t, second.Held())
+}
+
+func TestProfileStore_RefusesToDeleteProfileInUse(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.Create("busy"))
+	writeLockFile(t, s.SavePath("busy"), LockInfo{PID: 42, Host: "elsewhere", Started: time.Now()})
+
+	assert.ErrorIs(t, s.Delete("busy"), ErrLocked)
+	assert.ErrorIs(t, s.Rename("busy", "other"), ErrLocked)
+	assert.True(t, s.Summary("busy").InUse)
+	assert.True(t, s.Exists("busy"))
+}
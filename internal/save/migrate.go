@@ -0,0 +1,294 @@
+package save
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+)
+
+// ErrNewerVersion is returned when a save was written by a newer build with a
+// schema version this build does not know. Such saves are left untouched.
+var ErrNewerVersion = errors.New("save: file is from a newer version of clicker")
+
+// migrationStep upgrades the raw JSON object of a save from version From to
+// From+1. Steps work on raw JSON rather than SaveFile so they can read fields
+// that no longer exist in the current schema.
+type migrationStep struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]any) error
+}
+
+// migrations lists every schema upgrade, oldest first. To change the schema:
+// bump CurrentVersion, append a step from the previous version, and add a
+// golden fixture testdata/migrations/v<CurrentVersion>.json.
+var migrations = []migrationStep{
+	{From: 1, Description: "add clock record and per-world milestones", Apply: migrateV1toV2},
+	{From: 2, Description: "add auto-buyer settings", Apply: migrateV2toV3},
+	{From: 3, Description: "add challenges", Apply: migrateV3toV4},
+	{From: 4, Description: "add login streak", Apply: migrateV4toV5},
+	{From: 5, Description: "add challenge runs", Apply: migrateV5toV6},
+	{From: 6, Description: "add prestige points and trees", Apply: migrateV6toV7},
+	{From: 7, Description: "add account perks", Apply: migrateV7toV8},
+	{From: 8, Description: "add gameplay XP sources", Apply: migrateV8toV9},
+	{From: 9, Description: "add play time since each world's reset", Apply: migrateV9toV10},
+	{From: 10, Description: "add the exchange market", Apply: migrateV10toV11},
+}
+
+// MigrateRaw upgrades the JSON payload of a SaveFile to CurrentVersion by
+// applying each migration step in order. It returns the migrated JSON and the
+// version the payload started at; data is returned unchanged if it is already
+// current. Saves without a version field are treated as version 1.
+func MigrateRaw(data []byte) ([]byte, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // keep int64 counters exact through the round trip
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, 0, fmt.Errorf("save: migrate: %w", err)
+	}
+
+	version := 1
+	if n, ok := doc["version"].(json.Number); ok {
+		v, err := n.Int64()
+		if err != nil {
+			return nil, 0, fmt.Errorf("save: migrate: bad version %q", n)
+		}
+		version = max(int(v), 1)
+	}
+	if version > CurrentVersion {
+		return nil, version, fmt.Errorf("%w: file version %d, current version %d", ErrNewerVersion, version, CurrentVersion)
+	}
+	from := version
+	if version == CurrentVersion {
+		return data, from, nil
+	}
+
+	for _, step := range migrations {
+		if step.From < version {
+			continue
+		}
+		if step.From != version {
+			return nil, from, fmt.Errorf("save: migrate: no migration from version %d", version)
+		}
+		if err := step.Apply(doc); err != nil {
+			return nil, from, fmt.Errorf("save: migrate v%d→v%d (%s): %w", step.From, step.From+1, step.Description, err)
+		}
+		version++
+		doc["version"] = version
+	}
+	if version != CurrentVersion {
+		return nil, from, fmt.Errorf("save: migrate: no migration from version %d", version)
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, from, fmt.Errorf("save: migrate: %w", err)
+	}
+	return out, from, nil
+}
+
+// Migrate upgrades an already-decoded SaveFile to CurrentVersion through
+// MigrateRaw. Prefer MigrateRaw on the undecoded payload where possible:
+// fields removed from SaveFile are invisible here.
+func Migrate(sf *SaveFile) error {
+	if sf.Version > CurrentVersion {
+		return fmt.Errorf("%w: file version %d, current version %d", ErrNewerVersion, sf.Version, CurrentVersion)
+	}
+	if sf.Version == CurrentVersion {
+		return nil
+	}
+	data, err := json.Marshal(sf)
+	if err != nil {
+		return fmt.Errorf("save: migrate: %w", err)
+	}
+	out, _, err := MigrateRaw(data)
+	if err != nil {
+		return err
+	}
+	var migrated SaveFile
+	if err := json.Unmarshal(out, &migrated); err != nil {
+		return fmt.Errorf("save: migrate: %w", err)
+	}
+	*sf = migrated
+	return nil
+}
+
+// PreMigrationPath returns where the original bytes of a save are kept before
+// it is migrated from version from.
+func PreMigrationPath(path string, from int) string {
+	return fmt.Sprintf("%s.v%d", path, from)
+}
+
+// keepPreMigrationBackup copies the original save file aside before its
+// migrated form replaces it on the next save. An existing backup for the same
+// version is kept, since it is the closest to the original.
+func keepPreMigrationBackup(path string, data []byte, from int) error {
+	dest := PreMigrationPath(path, from)
+	if _, err := os.Stat(dest); err == nil {
+		return nil
+	}
+	return writeFileAtomic(dest, data, 0o600)
+}
+
+// migrateV1toV2 seeds the clock high-water mark (see clock.Record) from the
+// save time, so clock tampering is detected from the first launch after the
+// upgrade, and gives every world an explicit milestones map.
+func migrateV1toV2(doc map[string]any) error {
+	clockRec, _ := doc["clock"].(map[string]any)
+	if clockRec == nil {
+		clockRec = make(map[string]any)
+		doc["clock"] = clockRec
+	}
+	if lw, _ := clockRec["last_wall"].(string); lw == "" || lw == "0001-01-01T00:00:00Z" {
+		if savedAt, ok := doc["saved_at"].(string); ok {
+			clockRec["last_wall"] = savedAt
+		}
+	}
+	if _, ok := clockRec["play_seconds"]; !ok {
+		if p, ok := doc["player"].(map[string]any); ok && p["total_play_seconds"] != nil {
+			clockRec["play_seconds"] = p["total_play_seconds"]
+		}
+	}
+
+	worlds, _ := doc["worlds"].(map[string]any)
+	for id, raw := range worlds {
+		w, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("world %q is not an object", id)
+		}
+		if _, ok := w["milestones"].(map[string]any); !ok {
+			w["milestones"] = map[string]any{}
+		}
+	}
+
+	settings, _ := doc["settings"].(map[string]any)
+	if settings != nil {
+		if p, _ := settings["clock_policy"].(string); p == "" {
+			settings["clock_policy"] = "clamp"
+		}
+	}
+	return nil
+}
+
+// migrateV2toV3 adds the auto-buyer's settings: locked, switched off in
+// every world, and keeping the default share of each balance.
+func migrateV2toV3(doc map[string]any) error {
+	if _, ok := doc["auto_buyer"].(map[string]any); !ok {
+		doc["auto_buyer"] = map[string]any{
+			"unlocked": false,
+			"reserve":  autobuy.DefaultReserve,
+		}
+	}
+	return nil
+}
+
+// migrateV3toV4 adds the challenge state, empty: the first session after the
+// upgrade generates the day's challenges.
+func migrateV3toV4(doc map[string]any) error {
+	if _, ok := doc["challenges"].(map[string]any); !ok {
+		doc["challenges"] = map[string]any{}
+	}
+	return nil
+}
+
+// migrateV4toV5 adds the login streak, not started: the first launch after
+// the upgrade offers the first day's reward.
+func migrateV4toV5(doc map[string]any) error {
+	if _, ok := doc["streak"].(map[string]any); !ok {
+		doc["streak"] = map[string]any{"days": 0, "best": 0}
+	}
+	return nil
+}
+
+// migrateV5toV6 adds the challenge run state, empty: no run in progress and
+// none completed.
+func migrateV5toV6(doc map[string]any) error {
+	if _, ok := doc["runs"].(map[string]any); !ok {
+		doc["runs"] = map[string]any{}
+	}
+	return nil
+}
+
+// migrateV6toV7 adds each world's prestige points and tree. Worlds already
+// prestiged get the point every prestige now earns at least, so nothing is
+// owed for the prestiges made before points existed.
+func migrateV6toV7(doc map[string]any) error {
+	worlds, _ := doc["worlds"].(map[string]any)
+	for id, raw := range worlds {
+		w, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("world %q is not an object", id)
+		}
+		if _, ok := w["prestige_points"]; !ok {
+			w["prestige_points"] = w["prestige_count"]
+			if w["prestige_points"] == nil {
+				w["prestige_points"] = 0
+			}
+		}
+	}
+	return nil
+}
+
+// migrateV7toV8 adds the player's perk ranks, empty. Perk points are earned
+// per level rather than stored, so levels reached before perks existed
+// already count.
+func migrateV7toV8(doc map[string]any) error {
+	p, ok := doc["player"].(map[string]any)
+	if !ok {
+		// no player to add them to: loading starts a fresh one.
+		return nil
+	}
+	if _, ok := p["perks"].(map[string]any); !ok {
+		p["perks"] = map[string]any{}
+	}
+	return nil
+}
+
+// migrateV8toV9 adds the count of XP grants made by each gameplay source,
+// empty: the coins earned before the sources existed are granted their XP
+// on the first tick.
+func migrateV8toV9(doc map[string]any) error {
+	p, ok := doc["player"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	if _, ok := p["xp_sources"].(map[string]any); !ok {
+		p["xp_sources"] = map[string]any{}
+	}
+	return nil
+}
+
+// migrateV9toV10 adds each world's play time since its last reset. It was
+// not tracked before, so every world starts from the total play time: an
+// upper bound that is exact for worlds never reset.
+func migrateV9toV10(doc map[string]any) error {
+	var played any = 0
+	if c, ok := doc["clock"].(map[string]any); ok && c["play_seconds"] != nil {
+		played = c["play_seconds"]
+	}
+	worlds, _ := doc["worlds"].(map[string]any)
+	for id, raw := range worlds {
+		w, ok := raw.(map[string]any)
+		if !ok {
+			return fmt.Errorf("world %q is not an object", id)
+		}
+		if _, ok := w["since_reset_seconds"]; !ok {
+			w["since_reset_seconds"] = played
+		}
+	}
+	return nil
+}
+
+// migrateV10toV11 adds the exchange market, unseeded and with no prices:
+// the first session after the upgrade seeds it, and every coin starts at
+// its world's exchange rate.
+func migrateV10toV11(doc map[string]any) error {
+	if _, ok := doc["market"].(map[string]any); !ok {
+		doc["market"] = map[string]any{"step": 0, "elapsed": 0}
+	}
+	return nil
+}
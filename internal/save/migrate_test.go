@@ -0,0 +1,141 @@
+package save
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/autobuy"
+)
+
+// writeSignedPayload writes payload to path wrapped in a validly signed envelope.
+func writeSignedPayload(t *testing.T, path string, payload []byte) []byte {
+	t.Helper()
+	encoded := base64.StdEncoding.EncodeToString(payload)
+	data, err := json.Marshal(signedEnvelope{Data: encoded, Sig: sign([]byte(encoded))})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return data
+}
+
+func readFixture(t *testing.T, version int) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "migrations", fmt.Sprintf("v%d.json", version)))
+	require.NoError(t, err, "every schema version needs a golden fixture")
+	return data
+}
+
+func TestMigrations_AreContiguous(t *testing.T) {
+	require.Len(t, migrations, CurrentVersion-1)
+	for i, step := range migrations {
+		assert.Equal(t, i+1, step.From)
+		assert.NotEmpty(t, step.Description)
+	}
+}
+
+// TestMigrations_GoldenFixtures loads the fixture for every historical
+// version through the full Load path and checks that nothing the player
+// earned is lost on the way to CurrentVersion.
+func TestMigrations_GoldenFixtures(t *testing.T) {
+	for v := 1; v <= CurrentVersion; v++ {
+		t.Run(fmt.Sprintf("v%d", v), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "save.json")
+			writeSignedPayload(t, path, readFixture(t, v))
+
+			sf, rec, err := LoadWithRecovery(path)
+			require.NoError(t, err)
+			require.False(t, rec.Recovered(), rec.Reason)
+
+			assert.Equal(t, CurrentVersion, sf.Version)
+			assert.Equal(t, 420, sf.Player.XP)
+			assert.Equal(t, 4, sf.Player.Level)
+			assert.Equal(t, int64(1234), sf.Player.TotalClicks)
+			assert.InDelta(t, 37.5, sf.Player.GeneralCoins, 1e-9)
+			assert.True(t, sf.Achievements["first_click"])
+
+			terra := sf.Worlds["terra"]
+			assert.InDelta(t, 1800, terra.Coins, 1e-9)
+			assert.Equal(t, 10, terra.BuyOnCounts["auto_miner"])
+			assert.Equal(t, 1, terra.PrestigeCount)
+			assert.InDelta(t, 1.5, terra.PrestigeMultiplier, 1e-9)
+			assert.Equal(t, 1, terra.PrestigePoints, "one point per past prestige")
+			assert.InDelta(t, 5400, terra.SinceResetSeconds, 1e-9, "seeded from the play time")
+
+			savedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+			assert.True(t, savedAt.Equal(sf.SavedAt))
+			assert.True(t, savedAt.Equal(sf.Clock.LastWall), "clock high-water mark seeded from saved_at")
+			assert.InDelta(t, 5400, sf.Clock.PlaySeconds, 1e-9)
+			assert.Equal(t, "clamp", sf.Settings.ClockPolicy)
+			assert.False(t, sf.AutoBuyer.Unlocked)
+			assert.InDelta(t, autobuy.DefaultReserve, sf.AutoBuyer.Reserve, 1e-9)
+			assert.Empty(t, sf.Challenges.Daily)
+			assert.Zero(t, sf.Challenges.CompletedTotal)
+			assert.Empty(t, sf.Streak.LastDay)
+			assert.Zero(t, sf.Streak.Days)
+			assert.Empty(t, sf.Runs.Active)
+			assert.Empty(t, sf.Runs.Records)
+			assert.Empty(t, sf.Player.Perks)
+			assert.Empty(t, sf.Player.XPSources)
+			assert.Empty(t, sf.Market.Seed)
+			assert.Empty(t, sf.Market.Quotes)
+		})
+	}
+}
+
+func TestLoad_KeepsPreMigrationBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	original := writeSignedPayload(t, path, readFixture(t, 1))
+
+	_, err := Load(path)
+	require.NoError(t, err)
+
+	kept, err := os.ReadFile(PreMigrationPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, original, kept)
+
+	// Saves already at CurrentVersion are not copied.
+	current := filepath.Join(t.TempDir(), "save.json")
+	writeSignedPayload(t, current, readFixture(t, CurrentVersion))
+	_, err = Load(current)
+	require.NoError(t, err)
+	_, err = os.Stat(PreMigrationPath(current, CurrentVersion))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoad_NewerVersionLeftUntouched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	original := writeSignedPayload(t, path, []byte(fmt.Sprintf(`{"version":%d}`, CurrentVersion+1)))
+
+	_, rec, err := LoadWithRecovery(path)
+	assert.ErrorIs(t, err, ErrNewerVersion)
+	assert.False(t, rec.Recovered())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, original, data, "newer saves must not be quarantined")
+}
+
+func TestMigrateRaw_CurrentUnchanged(t *testing.T) {
+	in := readFixture(t, CurrentVersion)
+	out, from, err := MigrateRaw(in)
+	require.NoError(t, err)
+	assert.Equal(t, CurrentVersion, from)
+	assert.Equal(t, in, out)
+}
+
+func TestMigrateRaw_MissingVersionIsV1(t *testing.T) {
+	out, from, err := MigrateRaw([]byte(`{"saved_at":"2025-01-01T00:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, 1, from)
+
+	var sf SaveFile
+	require.NoError(t, json.Unmarshal(out, &sf))
+	assert.Equal(t, CurrentVersion, sf.Version)
+}
@@ -0,0 +1,293 @@
+package save
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultProfileName is the profile used when none is chosen, and the one a
+// pre-profiles save.json is imported into.
+const DefaultProfileName = "default"
+
+// saveFileName is the name of the save file inside a profile directory.
+const saveFileName = "save.json"
+
+// lastProfileFile records the most recently played profile, relative to the
+// store's parent directory.
+const lastProfileFile = "last_profile"
+
+var validProfileNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// ErrProfileExists is returned when creating or renaming onto a profile name
+// that is already taken.
+var ErrProfileExists = errors.New("save: profile already exists")
+
+// ErrProfileNotFound is returned when operating on a profile that does not exist.
+var ErrProfileNotFound = errors.New("save: profile not found")
+
+// ValidateProfileName returns an error if name cannot be used as a profile
+// name. Names are 1–32 characters of letters, digits, '-' and '_', so they are
+// always safe to use as a directory name.
+func ValidateProfileName(name string) error {
+	if !validProfileNameRe.MatchString(name) {
+		return fmt.Errorf("save: invalid profile name %q: use 1-32 letters, digits, '-' or '_'", name)
+	}
+	return nil
+}
+
+// ProfileSummary is a lightweight description of a profile read directly
+// from its SaveFile, without building game state.
+type ProfileSummary struct {
+	Name string
+	// Empty is true if the profile has never been saved.
+	Empty        bool
+	Level        int
+	GeneralCoins float64
+	LastPlayed   time.Time
+	// InUse is true if a running clicker holds the profile's save lock.
+	InUse bool
+	// Err is set if the save exists but could not be read.
+	Err error
+}
+
+// ProfileStore manages named save profiles stored as
+// <Root>/<name>/save.json.
+type ProfileStore struct {
+	Root string
+}
+
+// DefaultProfileStore returns the store under the OS config directory
+// (~/.config/clicker/profiles).
+func DefaultProfileStore() ProfileStore {
+	return ProfileStore{Root: filepath.Join(configDir(), "profiles")}
+}
+
+// Dir returns the directory holding the named profile.
+func (s ProfileStore) Dir(name string) string {
+	return filepath.Join(s.Root, name)
+}
+
+// SavePath returns the save file path for the named profile.
+func (s ProfileStore) SavePath(name string) string {
+	return filepath.Join(s.Dir(name), saveFileName)
+}
+
+// Exists reports whether the named profile exists.
+func (s ProfileStore) Exists(name string) bool {
+	info, err := os.Stat(s.Dir(name))
+	return err == nil && info.IsDir()
+}
+
+// Names returns all profile names in alphabetical order.
+func (s ProfileStore) Names() ([]string, error) {
+	entries, err := os.ReadDir(s.Root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("save: list profiles: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && ValidateProfileName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// List returns a summary of every profile in alphabetical order.
+func (s ProfileStore) List() ([]ProfileSummary, error) {
+	names, err := s.Names()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ProfileSummary, 0, len(names))
+	for _, name := range names {
+		out = append(out, s.Summary(name))
+	}
+	return out, nil
+}
+
+// Summary reads the named profile's save file and summarizes it.
+func (s ProfileStore) Summary(name string) ProfileSummary {
+	sum := ProfileSummary{Name: name}
+	_, sum.InUse = s.InUse(name)
+	data, err := os.ReadFile(s.SavePath(name))
+	if os.IsNotExist(err) {
+		sum.Empty = true
+		return sum
+	}
+	if err != nil {
+		sum.Err = err
+		return sum
+	}
+	sf, _, err := decode(data)
+	summarize(&sum, sf, err)
+	return sum
+}
+
+// summarize fills sum from a decoded save file, or records why decoding failed.
+func summarize(sum *ProfileSummary, sf SaveFile, err error) {
+	if err != nil {
+		sum.Err = err
+		return
+	}
+	sum.Level = sf.Player.Level
+	sum.GeneralCoins = sf.Player.GeneralCoins
+	sum.LastPlayed = sf.SavedAt
+}
+
+// InUse returns the holder of the named profile's save lock if a live
+// clicker process holds it.
+func (s ProfileStore) InUse(name string) (LockInfo, bool) {
+	holder, live, err := ReadLock(s.SavePath(name))
+	return holder, err == nil && live
+}
+
+// Create makes a new, empty profile.
+func (s ProfileStore) Create(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if s.Exists(name) {
+		return fmt.Errorf("%w: %q", ErrProfileExists, name)
+	}
+	if err := os.MkdirAll(s.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("save: create profile %q: %w", name, err)
+	}
+	return nil
+}
+
+// Rename renames a profile, keeping its saves and backups. Profiles in use by
+// a running clicker cannot be renamed.
+func (s ProfileStore) Rename(oldName, newName string) error {
+	if err := ValidateProfileName(newName); err != nil {
+		return err
+	}
+	if !s.Exists(oldName) {
+		return fmt.Errorf("%w: %q", ErrProfileNotFound, oldName)
+	}
+	if s.Exists(newName) {
+		return fmt.Errorf("%w: %q", ErrProfileExists, newName)
+	}
+	if holder, ok := s.InUse(oldName); ok {
+		return &LockedError{Holder: holder}
+	}
+	wasLast := s.LastUsed() == oldName
+	if err := os.Rename(s.Dir(oldName), s.Dir(newName)); err != nil {
+		return fmt.Errorf("save: rename profile %q: %w", oldName, err)
+	}
+	if wasLast {
+		_ = s.SetLastUsed(newName)
+	}
+	return nil
+}
+
+// Duplicate copies a profile's save file (not its backups) into a new profile.
+func (s ProfileStore) Duplicate(srcName, dstName string) error {
+	if !s.Exists(srcName) {
+		return fmt.Errorf("%w: %q", ErrProfileNotFound, srcName)
+	}
+	if err := s.Create(dstName); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(s.SavePath(srcName))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("save: duplicate profile %q: %w", srcName, err)
+	}
+	if err := writeFileAtomic(s.SavePath(dstName), data, 0o600); err != nil {
+		return fmt.Errorf("save: duplicate profile %q: %w", srcName, err)
+	}
+	return nil
+}
+
+// Delete removes a profile and everything in it, including backups. Profiles
+// in use by a running clicker cannot be deleted.
+func (s ProfileStore) Delete(name string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if !s.Exists(name) {
+		return fmt.Errorf("%w: %q", ErrProfileNotFound, name)
+	}
+	if holder, ok := s.InUse(name); ok {
+		return &LockedError{Holder: holder}
+	}
+	wasLast := s.LastUsed() == name
+	if err := os.RemoveAll(s.Dir(name)); err != nil {
+		return fmt.Errorf("save: delete profile %q: %w", name, err)
+	}
+	if wasLast {
+		_ = os.Remove(s.lastProfilePath())
+	}
+	return nil
+}
+
+func (s ProfileStore) lastProfilePath() string {
+	return filepath.Join(filepath.Dir(s.Root), lastProfileFile)
+}
+
+// LastUsed returns the most recently played profile, or "" if unknown or if
+// that profile no longer exists.
+func (s ProfileStore) LastUsed() string {
+	data, err := os.ReadFile(s.lastProfilePath())
+	if err != nil {
+		return ""
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateProfileName(name) != nil || !s.Exists(name) {
+		return ""
+	}
+	return name
+}
+
+// SetLastUsed records name as the most recently played profile.
+func (s ProfileStore) SetLastUsed(name string) error {
+	if err := os.MkdirAll(filepath.Dir(s.Root), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.lastProfilePath(), []byte(name+"\n"), 0o600)
+}
+
+// ImportLegacy moves a pre-profiles save file (and its backups and any
+// quarantined copies) into the default profile. It does nothing if the legacy
+// file does not exist or if any profile already exists.
+func (s ProfileStore) ImportLegacy(legacyPath string) error {
+	if _, err := os.Stat(legacyPath); err != nil {
+		return nil
+	}
+	names, err := s.Names()
+	if err != nil {
+		return err
+	}
+	if len(names) > 0 {
+		return nil
+	}
+	if err := s.Create(DefaultProfileName); err != nil {
+		return err
+	}
+	matches, err := filepath.Glob(legacyPath + "*")
+	if err != nil {
+		return err
+	}
+	base := filepath.Base(legacyPath)
+	for _, m := range matches {
+		suffix := strings.TrimPrefix(filepath.Base(m), base)
+		dest := s.SavePath(DefaultProfileName) + suffix
+		if err := os.Rename(m, dest); err != nil {
+			return fmt.Errorf("save: import legacy save: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package save
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/player"
+)
+
+func newTestStore(t *testing.T) ProfileStore {
+	return ProfileStore{Root: filepath.Join(t.TempDir(), "profiles")}
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"default", "Alt_2", "speed-run"} {
+		assert.NoError(t, ValidateProfileName(name), name)
+	}
+	for _, name := range []string{"", "../x", "a b", "with/slash", ".hidden"} {
+		assert.Error(t, ValidateProfileName(name), name)
+	}
+}
+
+func TestProfileStore_CreateRenameDuplicateDelete(t *testing.T) {
+	s := newTestStore(t)
+
+	require.NoError(t, s.Create("main"))
+	assert.ErrorIs(t, s.Create("main"), ErrProfileExists)
+	saveWithXP(t, s.SavePath("main"), 10)
+
+	require.NoError(t, s.Duplicate("main", "copy"))
+	require.NoError(t, s.Rename("main", "renamed"))
+	assert.False(t, s.Exists("main"))
+	assert.ErrorIs(t, s.Rename("missing", "x"), ErrProfileNotFound)
+	assert.ErrorIs(t, s.Rename("copy", "renamed"), ErrProfileExists)
+
+	names, err := s.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"copy", "renamed"}, names)
+
+	sf, err := Load(s.SavePath("copy"))
+	require.NoError(t, err)
+	assert.Equal(t, 10, sf.Player.XP)
+
+	require.NoError(t, s.Delete("copy"))
+	assert.False(t, s.Exists("copy"))
+	assert.ErrorIs(t, s.Delete("copy"), ErrProfileNotFound)
+}
+
+func TestProfileStore_List(t *testing.T) {
+	s := newTestStore(t)
+	require.NoError(t, s.Create("played"))
+	require.NoError(t, s.Create("fresh"))
+	require.NoError(t, s.Create("broken"))
+
+	gs := gamestate.NewGameState()
+	gs.Player = player.NewPlayer()
+	gs.Player.Level = 7
+	gs.Player.GeneralCoins = 123.5
+	require.NoError(t, Save(gs, nil, DefaultSaveFile().Settings, s.SavePath("played")))
+	require.NoError(t, os.WriteFile(s.SavePath("broken"), []byte("garbage"), 0o600))
+
+	list, err := s.List()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	assert.Equal(t, "broken", list[0].Name)
+	assert.Error(t, list[0].Err)
+
+	assert.Equal(t, "fresh", list[1].Name)
+	assert.True(t, list[1].Empty)
+
+	assert.Equal(t, "played", list[2].Name)
+	assert.Equal(t, 7, list[2].Level)
+	assert.InDelta(t, 123.5, list[2].GeneralCoins, 0.001)
+	assert.False(t, list[2].LastPlayed.IsZero())
+
+	// Reading summaries must not quarantine or otherwise touch broken saves.
+	_, err = os.Stat(s.SavePath("broken"))
+	assert.NoError(t, err)
+}
+
+func TestProfileStore_LastUsed(t *testing.T) {
+	s := newTestStore(t)
+	assert.Empty(t, s.LastUsed())
+
+	require.NoError(t, s.Create("a"))
+	require.NoError(t, s.SetLastUsed("a"))
+	assert.Equal(t, "a", s.LastUsed())
+
+	require.NoError(t, s.Rename("a", "b"))
+	assert.Equal(t, "b", s.LastUsed())
+
+	require.NoError(t, s.Delete("b"))
+	assert.Empty(t, s.LastUsed())
+}
+
+func TestProfileStore_ImportLegacy(t *testing.T) {
+	s := newTestStore(t)
+	legacy := filepath.Join(filepath.Dir(s.Root), "save.json")
+	saveWithXP(t, legacy, 1)
+	saveWithXP(t, legacy, 2) // creates save.json.1
+
+	require.NoError(t, s.ImportLegacy(legacy))
+
+	sf, err := Load(s.SavePath(DefaultProfileName))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sf.Player.XP)
+	_, err = os.Stat(BackupPath(s.SavePath(DefaultProfileName), 1))
+	assert.NoError(t, err)
+	_, err = os.Stat(legacy)
+	assert.True(t, os.IsNotExist(err))
+
+	// A second call is a no-op once profiles exist.
+	saveWithXP(t, legacy, 3)
+	require.NoError(t, s.ImportLegacy(legacy))
+	sf, err = Load(s.SavePath(DefaultProfileName))
+	require.NoError(t, err)
+	assert.Equal(t, 2, sf.Player.XP)
+}
@@ -1,12 +1,14 @@
 package save
 
 import (
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/clicker-org/clicker/internal/gamestate"
 	"github.com/clicker-org/clicker/internal/world"
@@ -26,9 +28,16 @@ func configDir() string {
 	return filepath.Join(configHome, "clicker")
 }
 
-// SavePath returns the OS-appropriate path for the save file.
+// SavePath returns the OS-appropriate path for the default profile's save
+// file. Use ProfileStore.SavePath for other profiles.
 func SavePath() string {
-	return filepath.Join(configDir(), "save.json")
+	return DefaultProfileStore().SavePath(DefaultProfileName)
+}
+
+// LegacySavePath returns where the save file lived before profiles were
+// introduced. See ProfileStore.ImportLegacy.
+func LegacySavePath() string {
+	return filepath.Join(configDir(), saveFileName)
 }
 
 // LogPath returns the OS-appropriate path for the log file.
@@ -38,71 +47,173 @@ func LogPath() string {
 
 // Save writes the current game state to path as a signed envelope.
 // The save file contains a base64-encoded JSON payload and its HMAC-SHA256
-// signature. Load will reject files whose signature does not match.
+// signature under this install's key. Load will reject files whose signature
+// does not match.
 func Save(gs gamestate.GameState, earned map[string]bool, settings Settings, path string) error {
-	sf := SaveFileFromGameState(gs, earned, settings)
-	payload, err := json.MarshalIndent(sf, "", "  ")
+	return WriteSaveFile(SaveFileFromGameState(gs, earned, settings), path)
+}
+
+// WriteSaveFile signs sf and writes it to path with the same crash-safe
+// rotation as Save. It is used to store a SaveFile that did not come from a
+// running game, such as an imported export code.
+func WriteSaveFile(sf SaveFile, path string) error {
+	data, err := encodeSaveFile(sf)
 	if err != nil {
-		return fmt.Errorf("save: marshal: %w", err)
-	}
-	encoded := base64.StdEncoding.EncodeToString(payload)
-	envelope := signedEnvelope{
-		Data: encoded,
-		Sig:  sign([]byte(encoded)),
+		return err
 	}
-	data, err := json.MarshalIndent(envelope, "", "  ")
+	return writeSaveBytes(path, data)
+}
+
+// encodeSaveFile returns the signed on-disk form of sf.
+func encodeSaveFile(sf SaveFile) ([]byte, error) {
+	payload, err := json.MarshalIndent(sf, "", "  ")
 	if err != nil {
-		return fmt.Errorf("save: marshal envelope: %w", err)
+		return nil, fmt.Errorf("save: marshal: %w", err)
 	}
+	return sealEnvelope(payload)
+}
+
+// writeSaveBytes writes an already signed save file to path, rotating the
+// previous save into the backups first.
+func writeSaveBytes(path string, data []byte) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return fmt.Errorf("save: mkdir: %w", err)
 	}
-	if err := os.WriteFile(path, data, 0o600); err != nil {
+	if err := rotateBackups(path); err != nil {
+		return fmt.Errorf("save: rotate backups: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0o600); err != nil {
 		return fmt.Errorf("save: write: %w", err)
 	}
 	return nil
 }
 
-// Load reads a SaveFile from path. If the file does not exist, returns
-// DefaultSaveFile with no error. If the file is corrupt or its HMAC signature
-// does not match, logs a warning and returns DefaultSaveFile. Applies
-// migrations after a successful load.
+// Recovery describes what Load had to do when the save file could not be used.
+// The zero value means the save loaded normally (or did not exist).
+type Recovery struct {
+	// Reason describes why the save file was rejected.
+	Reason string
+	// CorruptPath is where the rejected save file was moved. It is never deleted.
+	CorruptPath string
+	// RestoredFrom is the backup loaded in its place. Empty if no valid backup
+	// was found and the game starts fresh.
+	RestoredFrom string
+}
+
+// Recovered reports whether the save file was rejected and set aside.
+func (r Recovery) Recovered() bool { return r.Reason != "" }
+
+// Load reads a SaveFile from path. See LoadWithRecovery.
 func Load(path string) (SaveFile, error) {
+	sf, _, err := LoadWithRecovery(path)
+	return sf, err
+}
+
+// Peek reads a SaveFile from path without changing anything on disk: unlike
+// LoadWithRecovery it keeps no pre-migration copy, never moves a rejected save
+// aside and does not fall back to backups. A missing file reads as
+// DefaultSaveFile. It is meant for readers that do not hold the save lock.
+func Peek(path string) (SaveFile, error) {
 	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
 		return DefaultSaveFile(), nil
 	}
 	if err != nil {
-		return DefaultSaveFile(), fmt.Errorf("save: read %q: %w", path, err)
+		return SaveFile{}, fmt.Errorf("save: read %q: %w", path, err)
 	}
+	sf, _, err := decode(data)
+	if err != nil {
+		return SaveFile{}, fmt.Errorf("save: %q: %w", path, err)
+	}
+	return sf, nil
+}
 
-	var envelope signedEnvelope
-	if err := json.Unmarshal(data, &envelope); err != nil || envelope.Data == "" {
-		log.Printf("save: unrecognised save format at %q, starting fresh", path)
-		return DefaultSaveFile(), nil
+// LoadWithRecovery reads a SaveFile from path. If the file does not exist,
+// returns DefaultSaveFile with no error. If the file is corrupt or its HMAC
+// signature does not match, it is moved aside as <path>.corrupt-<timestamp>
+// and the newest valid backup is loaded instead; if no backup is usable,
+// DefaultSaveFile is returned. The Recovery result tells the caller which of
+// these happened so the player can be informed.
+//
+// Older saves are migrated to CurrentVersion (see MigrateRaw); the original
+// file is first copied to PreMigrationPath. A save from a newer build returns
+// ErrNewerVersion, and one signed by another install ErrUnknownKey; both are
+// left untouched.
+func LoadWithRecovery(path string) (SaveFile, Recovery, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return DefaultSaveFile(), Recovery{}, nil
+	}
+	if err != nil {
+		return DefaultSaveFile(), Recovery{}, fmt.Errorf("save: read %q: %w", path, err)
 	}
 
-	if !verify([]byte(envelope.Data), envelope.Sig) {
-		log.Printf("save: signature mismatch at %q — file may have been tampered with, starting fresh", path)
-		return DefaultSaveFile(), nil
+	sf, from, decodeErr := decode(data)
+	if errors.Is(decodeErr, ErrNewerVersion) || errors.Is(decodeErr, ErrUnknownKey) {
+		return DefaultSaveFile(), Recovery{}, decodeErr
+	}
+	if decodeErr == nil {
+		if from < CurrentVersion {
+			if err := keepPreMigrationBackup(path, data, from); err != nil {
+				log.Printf("save: could not keep pre-migration backup: %v", err)
+			} else {
+				log.Printf("save: migrated %q from version %d; original kept at %q", path, from, PreMigrationPath(path, from))
+			}
+		}
+		return sf, Recovery{}, nil
 	}
 
-	payload, err := base64.StdEncoding.DecodeString(envelope.Data)
-	if err != nil {
-		log.Printf("save: base64 decode failed at %q, starting fresh: %v", path, err)
-		return DefaultSaveFile(), nil
+	rec := Recovery{Reason: decodeErr.Error()}
+	log.Printf("save: rejected save at %q: %v", path, decodeErr)
+	if dest, err := quarantine(path, time.Now()); err != nil {
+		log.Printf("save: could not move rejected save aside: %v", err)
+	} else {
+		rec.CorruptPath = dest
+		log.Printf("save: rejected save kept at %q", dest)
 	}
 
-	var sf SaveFile
-	if err := json.Unmarshal(payload, &sf); err != nil {
-		log.Printf("save: corrupt save payload at %q, starting fresh: %v", path, err)
-		return DefaultSaveFile(), nil
+	for n := 1; n <= BackupCount; n++ {
+		backup := BackupPath(path, n)
+		data, err := os.ReadFile(backup)
+		if err != nil {
+			continue
+		}
+		sf, _, err := decode(data)
+		if err != nil {
+			log.Printf("save: backup %q unusable: %v", backup, err)
+			continue
+		}
+		rec.RestoredFrom = backup
+		log.Printf("save: restored from backup %q", backup)
+		return sf, rec, nil
 	}
 
-	if err := Migrate(&sf); err != nil {
-		return DefaultSaveFile(), err
+	log.Printf("save: no usable backup for %q, starting fresh", path)
+	return DefaultSaveFile(), rec, nil
+}
+
+// decode verifies the raw bytes of a signed save file, migrates the payload
+// to CurrentVersion and decodes it. It also returns the version the payload
+// was written with.
+func decode(data []byte) (SaveFile, int, error) {
+	return decodeWith(data, false)
+}
+
+// decodeWith is decode, skipping the signature check if trusted is set.
+func decodeWith(data []byte, trusted bool) (SaveFile, int, error) {
+	payload, _, err := openEnvelope(data, trusted)
+	if err != nil {
+		return SaveFile{}, 0, err
 	}
-	return sf, nil
+	migrated, from, err := MigrateRaw(payload)
+	if err != nil {
+		return SaveFile{}, from, err
+	}
+	var sf SaveFile
+	if err := json.Unmarshal(migrated, &sf); err != nil {
+		return SaveFile{}, from, fmt.Errorf("corrupt save payload: %w", err)
+	}
+	return sf, from, nil
 }
 
 // GameStateFromSave reconstructs a GameState from a SaveFile.
@@ -116,9 +227,23 @@ func GameStateFromSave(sf SaveFile, worldReg *world.WorldRegistry) gamestate.Gam
 	if gs.Player.WorldTotalCoinsEarned == nil {
 		gs.Player.WorldTotalCoinsEarned = make(map[string]float64)
 	}
+	gs.Player.Perks = maps.Clone(gs.Player.Perks)
+	if gs.Player.Perks == nil {
+		gs.Player.Perks = make(map[string]int)
+	}
+	gs.Player.XPSources = maps.Clone(gs.Player.XPSources)
+	if gs.Player.XPSources == nil {
+		gs.Player.XPSources = make(map[string]int)
+	}
 	gs.LastScreen = sf.LastScreen
 	gs.LastWorldID = sf.LastWorldID
 	gs.ActiveWorldID = sf.LastWorldID
+	gs.Clock = sf.Clock
+	gs.AutoBuyer = sf.AutoBuyer.Clone()
+	gs.Challenges = sf.Challenges.Clone()
+	gs.Streak = sf.Streak
+	gs.Runs = sf.Runs.Clone()
+	gs.Market = sf.Market.Clone()
 
 	// Reconstruct worlds — use saved data where available, otherwise fresh state.
 	for _, id := range worldReg.IDs() {
@@ -127,15 +252,21 @@ func GameStateFromSave(sf SaveFile, worldReg *world.WorldRegistry) gamestate.Gam
 				WorldID:                data.WorldID,
 				Coins:                  data.Coins,
 				TotalCoinsEarned:       data.TotalCoinsEarned,
+				BoughtCoins:            data.BoughtCoins,
 				CPS:                    data.CPS,
 				BuyOnCounts:            data.BuyOnCounts,
 				PurchasedUpgrades:      data.PurchasedUpgrades,
 				PrestigeCount:          data.PrestigeCount,
 				PrestigeMultiplier:     data.PrestigeMultiplier,
+				PrestigePoints:         data.PrestigePoints,
+				PrestigeTree:           data.PrestigeTree,
 				ExchangeRate:           data.ExchangeRate,
+				BoostCooldown:          data.BoostCooldown,
 				OfflineCapUpgradeLevel: data.OfflineCapUpgradeLevel,
 				CompletionPercent:      data.CompletionPercent,
+				Milestones:             data.Milestones,
 				TotalClicks:            data.TotalClicks,
+				SinceResetSeconds:      data.SinceResetSeconds,
 			}
 			if ws.BuyOnCounts == nil {
 				ws.BuyOnCounts = make(map[string]int)
@@ -143,6 +274,12 @@ func GameStateFromSave(sf SaveFile, worldReg *world.WorldRegistry) gamestate.Gam
 			if ws.PurchasedUpgrades == nil {
 				ws.PurchasedUpgrades = make(map[string]bool)
 			}
+			if ws.Milestones == nil {
+				ws.Milestones = make(map[string]bool)
+			}
+			if ws.PrestigeTree == nil {
+				ws.PrestigeTree = make(map[string]bool)
+			}
 			gs.Worlds[id] = ws
 		} else {
 			baseRate := 0.0
@@ -160,8 +297,16 @@ func GameStateFromSave(sf SaveFile, worldReg *world.WorldRegistry) gamestate.Gam
 func SaveFileFromGameState(gs gamestate.GameState, earned map[string]bool, settings Settings) SaveFile {
 	sf := DefaultSaveFile()
 	sf.Player = gs.Player
+	sf.Player.Perks = maps.Clone(gs.Player.Perks)
+	sf.Player.XPSources = maps.Clone(gs.Player.XPSources)
 	sf.LastScreen = gs.LastScreen
 	sf.LastWorldID = gs.LastWorldID
+	sf.Clock = gs.Clock
+	sf.AutoBuyer = gs.AutoBuyer.Clone()
+	sf.Challenges = gs.Challenges.Clone()
+	sf.Streak = gs.Streak
+	sf.Runs = gs.Runs.Clone()
+	sf.Market = gs.Market.Clone()
 
 	achCopy := make(map[string]bool, len(earned))
 	for k, v := range earned {
@@ -179,19 +324,33 @@ func SaveFileFromGameState(gs gamestate.GameState, earned map[string]bool, setti
 		for k, v := range ws.PurchasedUpgrades {
 			upgCopy[k] = v
 		}
+		milestoneCopy := make(map[string]bool, len(ws.Milestones))
+		for k, v := range ws.Milestones {
+			milestoneCopy[k] = v
+		}
+		treeCopy := make(map[string]bool, len(ws.PrestigeTree))
+		for k, v := range ws.PrestigeTree {
+			treeCopy[k] = v
+		}
 		sf.Worlds[id] = WorldSaveData{
 			WorldID:                ws.WorldID,
 			Coins:                  ws.Coins,
 			TotalCoinsEarned:       ws.TotalCoinsEarned,
+			BoughtCoins:            ws.BoughtCoins,
 			CPS:                    ws.CPS,
 			BuyOnCounts:            buyOnCopy,
 			PurchasedUpgrades:      upgCopy,
 			PrestigeCount:          ws.PrestigeCount,
 			PrestigeMultiplier:     ws.PrestigeMultiplier,
+			PrestigePoints:         ws.PrestigePoints,
+			PrestigeTree:           treeCopy,
 			ExchangeRate:           ws.ExchangeRate,
+			BoostCooldown:          ws.BoostCooldown,
 			OfflineCapUpgradeLevel: ws.OfflineCapUpgradeLevel,
 			CompletionPercent:      ws.CompletionPercent,
+			Milestones:             milestoneCopy,
 			TotalClicks:            ws.TotalClicks,
+			SinceResetSeconds:      ws.SinceResetSeconds,
 		}
 	}
 
@@ -162,3 +162,137 @@ func TestGameStateFromSave_MissingWorldUsesBaseExchangeRate(t *testing.T) {
 		assert.InDelta(t, w.BaseExchangeRate(), ws.ExchangeRate, 0.0000001)
 	}
 }
+
+// -- Crash safety and recovery tests --
+
+func saveWithXP(t *testing.T, path string, xp int) {
+	t.Helper()
+	gs := gamestate.NewGameState()
+	gs.Player.XP = xp
+	require.NoError(t, Save(gs, map[string]bool{}, Settings{AnimationsEnabled: true, ActiveTheme: "space"}, path))
+}
+
+// ageBackups dates path's backups back by BackupInterval, as if the last
+// rotation were that long ago.
+func ageBackups(t *testing.T, path string) {
+	t.Helper()
+	old := time.Now().Add(-BackupInterval)
+	for n := 1; n <= BackupCount; n++ {
+		if err := os.Chtimes(BackupPath(path, n), old, old); err != nil && !os.IsNotExist(err) {
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestSave_RotatesBackupsAtMostOncePerInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	for xp := 1; xp <= 5; xp++ {
+		saveWithXP(t, path, xp)
+	}
+	b, err := Load(BackupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, b.Player.XP, "autosaves soon after a rotation keep the backup")
+	_, err = os.Stat(BackupPath(path, 2))
+	assert.True(t, os.IsNotExist(err))
+
+	ageBackups(t, path)
+	saveWithXP(t, path, 6)
+	b, err = Load(BackupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 5, b.Player.XP)
+}
+
+func TestSave_RotatesBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "save.json")
+
+	for xp := 1; xp <= BackupCount+2; xp++ {
+		ageBackups(t, path)
+		saveWithXP(t, path, xp)
+	}
+
+	sf, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, BackupCount+2, sf.Player.XP)
+	for n := 1; n <= BackupCount; n++ {
+		b, err := Load(BackupPath(path, n))
+		require.NoError(t, err)
+		assert.Equal(t, BackupCount+2-n, b.Player.XP, "backup %d", n)
+	}
+	_, err = os.Stat(BackupPath(path, BackupCount+1))
+	assert.True(t, os.IsNotExist(err), "only BackupCount backups are kept")
+
+	// No temp files are left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, BackupCount+1)
+}
+
+func TestLoadWithRecovery_RestoresNewestValidBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "save.json")
+	saveWithXP(t, path, 10)
+	saveWithXP(t, path, 20)
+	ageBackups(t, path)
+	saveWithXP(t, path, 30)
+
+	// Corrupt the main save and the newest backup.
+	require.NoError(t, os.WriteFile(path, []byte("truncated {"), 0o600))
+	require.NoError(t, os.WriteFile(BackupPath(path, 1), []byte(""), 0o600))
+
+	sf, rec, err := LoadWithRecovery(path)
+	require.NoError(t, err)
+	assert.True(t, rec.Recovered())
+	assert.Equal(t, BackupPath(path, 2), rec.RestoredFrom)
+	assert.Equal(t, 10, sf.Player.XP)
+
+	// The damaged file is moved aside, never discarded.
+	require.NotEmpty(t, rec.CorruptPath)
+	kept, err := os.ReadFile(rec.CorruptPath)
+	require.NoError(t, err)
+	assert.Equal(t, "truncated {", string(kept))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoadWithRecovery_NoBackupStartsFresh(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "save.json")
+	require.NoError(t, os.WriteFile(path, []byte("not valid json {{{"), 0o600))
+
+	sf, rec, err := LoadWithRecovery(path)
+	require.NoError(t, err)
+	assert.True(t, rec.Recovered())
+	assert.Empty(t, rec.RestoredFrom)
+	assert.FileExists(t, rec.CorruptPath)
+	assert.Equal(t, 0, sf.Player.XP)
+}
+
+func TestLoadWithRecovery_ValidSaveNoRecovery(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "save.json")
+	saveWithXP(t, path, 5)
+
+	sf, rec, err := LoadWithRecovery(path)
+	require.NoError(t, err)
+	assert.False(t, rec.Recovered())
+	assert.Equal(t, 5, sf.Player.XP)
+}
+
+func TestPeek_LeavesFilesAlone(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "save.json")
+	saveWithXP(t, path, 7)
+
+	sf, err := Peek(path)
+	require.NoError(t, err)
+	assert.Equal(t, 7, sf.Player.XP)
+
+	require.NoError(t, os.WriteFile(path, []byte("not valid json {{{"), 0o600))
+	_, err = Peek(path)
+	assert.Error(t, err)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "a rejected save is not moved aside")
+	assert.FileExists(t, path)
+}
@@ -1,20 +1,31 @@
 package save
 
 import (
-	"fmt"
 	"time"
 
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/clock"
+	"github.com/clicker-org/clicker/internal/market"
 	"github.com/clicker-org/clicker/internal/player"
+	"github.com/clicker-org/clicker/internal/runs"
+	"github.com/clicker-org/clicker/internal/streak"
 )
 
-// CurrentVersion is the current save file schema version.
-const CurrentVersion = 1
+// CurrentVersion is the current save file schema version. See migrations
+// for how older versions are upgraded.
+const CurrentVersion = 11
 
 // signedEnvelope is the on-disk format for save files.
-// Data holds the base64-encoded JSON of a SaveFile; Sig is its HMAC-SHA256.
+// Data holds the base64-encoded JSON of a SaveFile; Sig is its signature
+// under the algorithm Alg and the key named by KeyID. Format 1 envelopes
+// predate the other fields and carry only Data and Sig.
 type signedEnvelope struct {
-	Data string `json:"data"`
-	Sig  string `json:"sig"`
+	Format int    `json:"format,omitempty"`
+	KeyID  string `json:"kid,omitempty"`
+	Alg    string `json:"alg,omitempty"`
+	Data   string `json:"data"`
+	Sig    string `json:"sig"`
 }
 
 // WorldSaveData holds all persisted data for a single world.
@@ -22,21 +33,30 @@ type WorldSaveData struct {
 	WorldID                string             `json:"world_id"`
 	Coins                  float64            `json:"coins"`
 	TotalCoinsEarned       float64            `json:"total_coins_earned"`
+	BoughtCoins            float64            `json:"bought_coins,omitempty"`
 	CPS                    float64            `json:"cps"`
 	BuyOnCounts            map[string]int     `json:"buy_on_counts"`
 	PurchasedUpgrades      map[string]bool    `json:"purchased_upgrades"`
 	PrestigeCount          int                `json:"prestige_count"`
 	PrestigeMultiplier     float64            `json:"prestige_multiplier"`
+	PrestigePoints         int                `json:"prestige_points"`
+	PrestigeTree           map[string]bool    `json:"prestige_tree,omitempty"`
 	ExchangeRate           float64            `json:"exchange_rate"`
+	BoostCooldown          float64            `json:"boost_cooldown,omitempty"`
 	OfflineCapUpgradeLevel int                `json:"offline_cap_upgrade_level"`
 	CompletionPercent      float64            `json:"completion_percent"`
+	Milestones             map[string]bool    `json:"milestones,omitempty"`
 	TotalClicks            int64              `json:"total_clicks"`
+	SinceResetSeconds      float64            `json:"since_reset_seconds"`
 }
 
 // Settings holds user-configurable preferences.
 type Settings struct {
 	AnimationsEnabled bool   `json:"animations_enabled"`
 	ActiveTheme       string `json:"active_theme"`
+	// ClockPolicy is the offline.ClockPolicy applied when the system clock
+	// looks wrong on launch ("clamp", "ignore" or "flag").
+	ClockPolicy string `json:"clock_policy,omitempty"`
 }
 
 // SaveFile is the top-level save file structure.
@@ -49,6 +69,12 @@ type SaveFile struct {
 	Worlds       map[string]WorldSaveData  `json:"worlds"`
 	Achievements map[string]bool           `json:"achievements"`
 	Settings     Settings                  `json:"settings"`
+	Clock        clock.Record              `json:"clock"`
+	AutoBuyer    autobuy.Settings          `json:"auto_buyer"`
+	Challenges   challenge.State           `json:"challenges"`
+	Streak       streak.State              `json:"streak"`
+	Runs         runs.State                `json:"runs"`
+	Market       market.State              `json:"market"`
 }
 
 // DefaultSaveFile returns a fresh SaveFile with sensible defaults.
@@ -64,19 +90,8 @@ func DefaultSaveFile() SaveFile {
 		Settings: Settings{
 			AnimationsEnabled: true,
 			ActiveTheme:       "space",
+			ClockPolicy:       "clamp",
 		},
+		AutoBuyer: autobuy.DefaultSettings(),
 	}
 }
-
-// Migrate upgrades a SaveFile to the current schema version, applying any
-// required migration functions in sequence.
-func Migrate(sf *SaveFile) error {
-	if sf.Version > CurrentVersion {
-		return fmt.Errorf("save: file version %d is newer than current version %d", sf.Version, CurrentVersion)
-	}
-	// Each migration block below handles one version step.
-	// Example for future migrations:
-	// if sf.Version < 2 { migrateV1toV2(sf); sf.Version = 2 }
-	sf.Version = CurrentVersion
-	return nil
-}
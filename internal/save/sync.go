@@ -0,0 +1,284 @@
+package save
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// syncConfigFile holds a profile's SyncConfig, beside its save file.
+const syncConfigFile = "sync.json"
+
+// SyncConfig is a profile's sync settings together with what was true at
+// its last successful sync.
+type SyncConfig struct {
+	// Remote is the backend spec passed to OpenBackend. Empty disables sync.
+	Remote string `json:"remote"`
+	// Revision is the backend revision the local save matched at the last sync.
+	Revision int64 `json:"revision"`
+	// LocalHash is the SHA-256 of the local save file at the last sync, used
+	// to tell whether the local save changed since.
+	LocalHash string `json:"local_hash"`
+	// TrustedKeys are the key IDs of other installs whose saves the player
+	// agreed to pull from Remote.
+	TrustedKeys []string `json:"trusted_keys,omitempty"`
+}
+
+// SyncConfigPath returns where the SyncConfig for the save at savePath lives.
+func SyncConfigPath(savePath string) string {
+	return filepath.Join(filepath.Dir(savePath), syncConfigFile)
+}
+
+// LoadSyncConfig reads the SyncConfig for the save at savePath. A missing
+// file returns the zero SyncConfig (sync disabled).
+func LoadSyncConfig(savePath string) (SyncConfig, error) {
+	var cfg SyncConfig
+	data, err := os.ReadFile(SyncConfigPath(savePath))
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("save: read sync config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("save: sync config %q: %w", SyncConfigPath(savePath), err)
+	}
+	return cfg, nil
+}
+
+// WriteSyncConfig stores cfg for the save at savePath.
+func WriteSyncConfig(savePath string, cfg SyncConfig) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(savePath), 0o755); err != nil {
+		return fmt.Errorf("save: mkdir: %w", err)
+	}
+	if err := writeFileAtomic(SyncConfigPath(savePath), data, 0o600); err != nil {
+		return fmt.Errorf("save: write sync config: %w", err)
+	}
+	return nil
+}
+
+// SyncOutcome says what a sync did.
+type SyncOutcome int
+
+const (
+	SyncUpToDate SyncOutcome = iota
+	SyncPushed
+	SyncPulled
+	SyncConflict
+)
+
+func (o SyncOutcome) String() string {
+	switch o {
+	case SyncPushed:
+		return "pushed"
+	case SyncPulled:
+		return "pulled"
+	case SyncConflict:
+		return "conflict"
+	}
+	return "up to date"
+}
+
+// SyncResult reports the outcome of Syncer.Sync or Syncer.Resolve.
+type SyncResult struct {
+	Outcome SyncOutcome
+	// Rev is the backend revision the local save now matches. For
+	// SyncPulled, Rev.Writer names the machine that wrote the pulled save.
+	Rev Revision
+	// Conflict is set when Outcome is SyncConflict.
+	Conflict *Conflict
+}
+
+// Conflict describes a local save and a backend save that both changed since
+// the last sync. Neither is touched until the player picks one with
+// Syncer.Resolve.
+type Conflict struct {
+	Local     ProfileSummary
+	Remote    ProfileSummary
+	RemoteRev Revision
+
+	local  []byte
+	remote Blob
+}
+
+// Syncer keeps the save at SavePath in step with Backend. It assumes the
+// caller holds the save's Lock, so the local file does not change under it.
+type Syncer struct {
+	SavePath string
+	Backend  Backend
+	// Writer identifies this machine in backend revisions. Defaults to the
+	// host name.
+	Writer string
+	// Trust is asked before pulling a save signed by another install whose
+	// key is not in the SyncConfig's TrustedKeys yet, as `clicker trust`
+	// asks; a yes is recorded there. If it is nil such saves are refused.
+	Trust func(keyID string, remote SaveFile) bool
+}
+
+// ProfileSyncer returns a Syncer for the save at savePath using the remote
+// configured in its SyncConfig, or nil if sync is not configured.
+func ProfileSyncer(savePath string) (*Syncer, error) {
+	cfg, err := LoadSyncConfig(savePath)
+	if err != nil || cfg.Remote == "" {
+		return nil, err
+	}
+	backend, err := OpenBackend(cfg.Remote)
+	if err != nil {
+		return nil, err
+	}
+	return &Syncer{SavePath: savePath, Backend: backend}, nil
+}
+
+// Sync compares the local save and the backend against the state recorded at
+// the last sync. If only one side changed, the other is brought up to date:
+// local changes are pushed, remote changes pulled (replacing the local save
+// through the usual backup rotation). If both changed, nothing is written
+// and the result carries a Conflict for the player to resolve.
+func (s Syncer) Sync(ctx context.Context) (SyncResult, error) {
+	cfg, err := LoadSyncConfig(s.SavePath)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	local, err := os.ReadFile(s.SavePath)
+	if err != nil && !os.IsNotExist(err) {
+		return SyncResult{}, fmt.Errorf("save: sync read %q: %w", s.SavePath, err)
+	}
+	hasLocal := err == nil
+	remote, err := s.Backend.Get(ctx)
+	if err != nil && !errors.Is(err, ErrNoRemoteSave) {
+		return SyncResult{}, err
+	}
+	hasRemote := err == nil
+
+	switch {
+	case !hasLocal && !hasRemote:
+		return SyncResult{Outcome: SyncUpToDate}, nil
+	case !hasRemote:
+		return s.push(ctx, cfg, local, 0)
+	case !hasLocal:
+		return s.pull(cfg, remote)
+	case bytes.Equal(local, remote.Data):
+		return s.record(cfg, SyncUpToDate, remote.Rev, local)
+	}
+
+	localChanged := hashSave(local) != cfg.LocalHash
+	remoteChanged := remote.Rev.Number != cfg.Revision
+	switch {
+	case localChanged && remoteChanged:
+		return SyncResult{Outcome: SyncConflict, Conflict: newConflict(local, remote)}, nil
+	case remoteChanged:
+		return s.pull(cfg, remote)
+	case localChanged:
+		return s.push(ctx, cfg, local, remote.Rev.Number)
+	}
+	return SyncResult{Outcome: SyncUpToDate, Rev: remote.Rev}, nil
+}
+
+// Resolve settles c by keeping either the local save (pushed over the
+// backend) or the backend's save (pulled over the local one). If the backend
+// changed again since c was reported, a new conflict is returned.
+func (s Syncer) Resolve(ctx context.Context, c *Conflict, keepLocal bool) (SyncResult, error) {
+	cfg, err := LoadSyncConfig(s.SavePath)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	if keepLocal {
+		return s.push(ctx, cfg, c.local, c.remote.Rev.Number)
+	}
+	return s.pull(cfg, c.remote)
+}
+
+func (s Syncer) push(ctx context.Context, cfg SyncConfig, local []byte, base int64) (SyncResult, error) {
+	if _, _, err := decode(local); err != nil {
+		return SyncResult{}, fmt.Errorf("save: not syncing unreadable local save: %w", err)
+	}
+	rev, err := s.Backend.Put(ctx, local, base, s.writer())
+	var conflict *ConflictError
+	if errors.As(err, &conflict) {
+		return SyncResult{Outcome: SyncConflict, Conflict: newConflict(local, conflict.Current)}, nil
+	}
+	if err != nil {
+		return SyncResult{}, err
+	}
+	return s.record(cfg, SyncPushed, rev, local)
+}
+
+// pull replaces the local save with remote. The remote save is usually
+// signed by the other machine's install key, which the player must have
+// trusted (see Syncer.Trust); the save is re-signed with this install's key.
+func (s Syncer) pull(cfg SyncConfig, remote Blob) (SyncResult, error) {
+	sf, err := decodeAdopted(remote.Data, func(keyID string, sf SaveFile) bool {
+		if slices.Contains(cfg.TrustedKeys, keyID) {
+			return true
+		}
+		if s.Trust == nil || !s.Trust(keyID, sf) {
+			return false
+		}
+		cfg.TrustedKeys = append(cfg.TrustedKeys, keyID)
+		return true
+	})
+	if errors.Is(err, ErrUnknownKey) {
+		return SyncResult{}, fmt.Errorf("save: not syncing untrusted save from %s: %w", s.Backend, err)
+	}
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("save: not syncing unreadable save from %s: %w", s.Backend, err)
+	}
+	data, err := encodeSaveFile(sf)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	if err := writeSaveBytes(s.SavePath, data); err != nil {
+		return SyncResult{}, err
+	}
+	return s.record(cfg, SyncPulled, remote.Rev, data)
+}
+
+// record stores that the local save, now equal to data, matches rev.
+func (s Syncer) record(cfg SyncConfig, outcome SyncOutcome, rev Revision, data []byte) (SyncResult, error) {
+	cfg.Revision = rev.Number
+	cfg.LocalHash = hashSave(data)
+	if err := WriteSyncConfig(s.SavePath, cfg); err != nil {
+		return SyncResult{}, err
+	}
+	return SyncResult{Outcome: outcome, Rev: rev}, nil
+}
+
+func (s Syncer) writer() string {
+	if s.Writer != "" {
+		return s.Writer
+	}
+	host, _ := os.Hostname()
+	return host
+}
+
+func newConflict(local []byte, remote Blob) *Conflict {
+	c := &Conflict{
+		Local:     ProfileSummary{Name: "local"},
+		Remote:    ProfileSummary{Name: "remote"},
+		RemoteRev: remote.Rev,
+		local:     local,
+		remote:    remote,
+	}
+	sf, _, err := decode(local)
+	summarize(&c.Local, sf, err)
+	// only shown: pulling the remote save still asks to trust its key.
+	sf, err = decodeAdopted(remote.Data, func(string, SaveFile) bool { return true })
+	summarize(&c.Remote, sf, err)
+	return c
+}
+
+func hashSave(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
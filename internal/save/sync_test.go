@@ -0,0 +1,264 @@
+package save
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves a DirBackend over HTTP in-process.
+func newTestServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(NewSyncHandler(NewDirBackend(t.TempDir()), token))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newMachine returns a Syncer for a fresh local profile named writer.
+func newMachine(t *testing.T, backend Backend, writer string) Syncer {
+	t.Helper()
+	return Syncer{SavePath: filepath.Join(t.TempDir(), "save.json"), Backend: backend, Writer: writer}
+}
+
+func writeLevel(t *testing.T, path string, level int) {
+	t.Helper()
+	sf := DefaultSaveFile()
+	sf.Player.Level = level
+	require.NoError(t, WriteSaveFile(sf, path))
+}
+
+func loadLevel(t *testing.T, path string) int {
+	t.Helper()
+	sf, err := Load(path)
+	require.NoError(t, err)
+	return sf.Player.Level
+}
+
+func TestSync_LaptopAndDesktopOverHTTP(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "")
+	laptop := newMachine(t, NewHTTPBackend(srv.URL), "laptop")
+	desktop := newMachine(t, NewHTTPBackend(srv.URL), "desktop")
+
+	// nothing anywhere yet.
+	res, err := laptop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncUpToDate, res.Outcome)
+
+	writeLevel(t, laptop.SavePath, 3)
+	res, err = laptop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPushed, res.Outcome)
+	assert.Equal(t, int64(1), res.Rev.Number)
+
+	res, err = desktop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPulled, res.Outcome)
+	assert.Equal(t, "laptop", res.Rev.Writer, "last writer is reported")
+	assert.Equal(t, 3, loadLevel(t, desktop.SavePath))
+
+	writeLevel(t, desktop.SavePath, 4)
+	res, err = desktop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPushed, res.Outcome)
+	assert.Equal(t, int64(2), res.Rev.Number)
+
+	res, err = laptop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPulled, res.Outcome)
+	assert.Equal(t, "desktop", res.Rev.Writer)
+	assert.Equal(t, 4, loadLevel(t, laptop.SavePath))
+
+	// the pulled save replaced the old one through the usual backups.
+	_, err = os.Stat(BackupPath(laptop.SavePath, 1))
+	assert.NoError(t, err)
+
+	res, err = laptop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncUpToDate, res.Outcome)
+}
+
+func TestSync_ConflictShowsBothSummaries(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "")
+	laptop := newMachine(t, NewHTTPBackend(srv.URL), "laptop")
+	desktop := newMachine(t, NewHTTPBackend(srv.URL), "desktop")
+
+	writeLevel(t, laptop.SavePath, 3)
+	_, err := laptop.Sync(ctx)
+	require.NoError(t, err)
+	_, err = desktop.Sync(ctx)
+	require.NoError(t, err)
+
+	// both play offline.
+	writeLevel(t, laptop.SavePath, 5)
+	writeLevel(t, desktop.SavePath, 7)
+	_, err = desktop.Sync(ctx)
+	require.NoError(t, err)
+
+	res, err := laptop.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, SyncConflict, res.Outcome)
+	c := res.Conflict
+	assert.Equal(t, 5, c.Local.Level)
+	assert.Equal(t, 7, c.Remote.Level)
+	assert.Equal(t, "desktop", c.RemoteRev.Writer)
+	assert.Equal(t, 5, loadLevel(t, laptop.SavePath), "nothing is written until resolved")
+
+	res, err = laptop.Resolve(ctx, c, true)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPushed, res.Outcome)
+	assert.Equal(t, int64(3), res.Rev.Number)
+
+	res, err = desktop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPulled, res.Outcome)
+	assert.Equal(t, 5, loadLevel(t, desktop.SavePath))
+}
+
+func TestSync_ResolveKeepRemote(t *testing.T) {
+	ctx := context.Background()
+	backend := NewDirBackend(t.TempDir())
+	laptop := newMachine(t, backend, "laptop")
+	desktop := newMachine(t, backend, "desktop")
+
+	// both start with their own save: the first sync cannot tell which is newer.
+	writeLevel(t, desktop.SavePath, 9)
+	_, err := desktop.Sync(ctx)
+	require.NoError(t, err)
+	writeLevel(t, laptop.SavePath, 2)
+
+	res, err := laptop.Sync(ctx)
+	require.NoError(t, err)
+	require.Equal(t, SyncConflict, res.Outcome)
+
+	res, err = laptop.Resolve(ctx, res.Conflict, false)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPulled, res.Outcome)
+	assert.Equal(t, 9, loadLevel(t, laptop.SavePath))
+}
+
+func TestHTTPBackend_StalePutConflicts(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "")
+	b := NewHTTPBackend(srv.URL)
+
+	_, err := b.Get(ctx)
+	assert.ErrorIs(t, err, ErrNoRemoteSave)
+
+	rev, err := b.Put(ctx, []byte("one"), 0, "laptop")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rev.Number)
+
+	_, err = b.Put(ctx, []byte("two"), 0, "desktop")
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, []byte("one"), conflict.Current.Data)
+	assert.Equal(t, "laptop", conflict.Current.Rev.Writer)
+}
+
+func TestHTTPBackend_Token(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, "s3cret")
+
+	_, err := (&HTTPBackend{URL: srv.URL}).Get(ctx)
+	assert.ErrorContains(t, err, "401")
+
+	_, err = (&HTTPBackend{URL: srv.URL, Token: "s3cret"}).Get(ctx)
+	assert.ErrorIs(t, err, ErrNoRemoteSave)
+}
+
+func TestSync_RefusesUnreadableRemote(t *testing.T) {
+	ctx := context.Background()
+	backend := NewDirBackend(t.TempDir())
+	_, err := backend.Put(ctx, []byte("not a save"), 0, "elsewhere")
+	require.NoError(t, err)
+
+	m := newMachine(t, backend, "laptop")
+	_, err = m.Sync(ctx)
+	assert.Error(t, err)
+	_, statErr := os.Stat(m.SavePath)
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestOpenBackend(t *testing.T) {
+	b, err := OpenBackend("https://example.com/default")
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPBackend{}, b)
+
+	b, err = OpenBackend("file:///mnt/share/clicker")
+	require.NoError(t, err)
+	assert.Equal(t, "/mnt/share/clicker", b.String())
+
+	_, err = OpenBackend("ftp://example.com")
+	assert.Error(t, err)
+}
+
+func TestSync_BetweenInstallsResigns(t *testing.T) {
+	ctx := context.Background()
+	backend := NewDirBackend(t.TempDir())
+	laptop := newMachine(t, backend, "laptop")
+	desktop := newMachine(t, backend, "desktop")
+
+	useInstallKey(t, InstallKey{ID: "i-laptop", Secret: []byte("laptop-install-secret-0123456789")})
+	writeLevel(t, laptop.SavePath, 8)
+	_, err := laptop.Sync(ctx)
+	require.NoError(t, err)
+
+	useInstallKey(t, InstallKey{ID: "i-desktop", Secret: []byte("desktop-install-secret-012345678")})
+	var asked []string
+	desktop.Trust = func(keyID string, remote SaveFile) bool {
+		asked = append(asked, keyID)
+		assert.Equal(t, 8, remote.Player.Level, "the save is shown before it is trusted")
+		return true
+	}
+	res, err := desktop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPulled, res.Outcome)
+	assert.Equal(t, "i-desktop", readEnvelope(t, desktop.SavePath).KeyID)
+	assert.Equal(t, 8, loadLevel(t, desktop.SavePath))
+	assert.Equal(t, []string{"i-laptop"}, asked)
+
+	res, err = desktop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncUpToDate, res.Outcome, "re-signing is not a local change")
+
+	useInstallKey(t, InstallKey{ID: "i-laptop", Secret: []byte("laptop-install-secret-0123456789")})
+	writeLevel(t, laptop.SavePath, 9)
+	_, err = laptop.Sync(ctx)
+	require.NoError(t, err)
+	useInstallKey(t, InstallKey{ID: "i-desktop", Secret: []byte("desktop-install-secret-012345678")})
+	res, err = desktop.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, SyncPulled, res.Outcome)
+	assert.Equal(t, []string{"i-laptop"}, asked, "a trusted key is asked about once")
+}
+
+func TestSync_RefusesUntrustedInstall(t *testing.T) {
+	ctx := context.Background()
+	backend := NewDirBackend(t.TempDir())
+	laptop := newMachine(t, backend, "laptop")
+	desktop := newMachine(t, backend, "desktop")
+
+	useInstallKey(t, InstallKey{ID: "i-laptop", Secret: []byte("laptop-install-secret-0123456789")})
+	writeLevel(t, laptop.SavePath, 8)
+	_, err := laptop.Sync(ctx)
+	require.NoError(t, err)
+
+	useInstallKey(t, InstallKey{ID: "i-desktop", Secret: []byte("desktop-install-secret-012345678")})
+	_, err = desktop.Sync(ctx)
+	assert.ErrorIs(t, err, ErrUnknownKey, "no Trust: never adopted silently")
+	desktop.Trust = func(string, SaveFile) bool { return false }
+	_, err = desktop.Sync(ctx)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, statErr := os.Stat(desktop.SavePath)
+	assert.True(t, os.IsNotExist(statErr), "nothing is written")
+	cfg, err := LoadSyncConfig(desktop.SavePath)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.TrustedKeys)
+}
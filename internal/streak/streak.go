@@ -0,0 +1,133 @@
+// Package streak tracks the daily login streak: one reward per calendar day
+// the player comes back, growing with each consecutive day. It only keeps
+// the books; the engine grants the rewards (see
+// engine.Engine.ClaimLoginReward). It has no Bubble Tea imports.
+//
+// Days are local calendar days, counted in the time zone the streak started
+// in, so travelling or changing the time zone never makes a day count
+// twice. Claims are also never earlier than the previous one, so setting the
+// clock back cannot claim a day again.
+package streak
+
+import "time"
+
+// GraceRecharge is how many consecutive days the streak must reach before a
+// used grace day is available again.
+const GraceRecharge = 7
+
+// Reward is what claiming one day of the streak grants.
+type Reward struct {
+	XP int     `json:"xp"`
+	GC float64 `json:"gc"`
+}
+
+// Rewards are the rewards of the first days of a streak; every later day
+// grants the last one.
+var Rewards = []Reward{
+	{XP: 20, GC: 1},
+	{XP: 30, GC: 1},
+	{XP: 40, GC: 2},
+	{XP: 50, GC: 2},
+	{XP: 60, GC: 3},
+	{XP: 80, GC: 4},
+	{XP: 120, GC: 10},
+}
+
+// RewardFor returns the reward for day n of a streak, counting from 1.
+func RewardFor(n int) Reward {
+	return Rewards[min(max(n, 1), len(Rewards))-1]
+}
+
+// State is the persisted login streak.
+type State struct {
+	// LastDay is the local date, as "2006-01-02", of the last claim.
+	LastDay string `json:"last_day,omitempty"`
+	// ClaimedAt is when the last claim was made.
+	ClaimedAt time.Time `json:"claimed_at,omitempty"`
+	// Zone is the UTC offset, in seconds, of the first claim of the streak;
+	// its days are counted in it.
+	Zone int `json:"zone,omitempty"`
+	// Days is the current streak length, including the last claim.
+	Days int `json:"days"`
+	// Best is the longest streak ever.
+	Best int `json:"best"`
+	// GraceUsed is set once a missed day has been forgiven, until the
+	// streak reaches the next multiple of GraceRecharge.
+	GraceUsed bool `json:"grace_used,omitempty"`
+}
+
+// Offer is the reward available to claim.
+type Offer struct {
+	// Day is the local date being claimed.
+	Day string `json:"day"`
+	// Streak is the streak length once claimed.
+	Streak int    `json:"streak"`
+	Reward Reward `json:"reward"`
+	// Grace is set if the claim forgives a missed day.
+	Grace bool `json:"grace,omitempty"`
+	// Broken is set if a streak was lost since the last claim.
+	Broken bool `json:"broken,omitempty"`
+}
+
+// Next returns the reward available at now, or false if the day now falls
+// on has already been claimed.
+func (s State) Next(now time.Time) (Offer, bool) {
+	if now.Before(s.ClaimedAt) {
+		now = s.ClaimedAt
+	}
+	if s.LastDay == "" {
+		return Offer{Day: now.Format(time.DateOnly), Streak: 1, Reward: RewardFor(1)}, true
+	}
+	last, err := time.Parse(time.DateOnly, s.LastDay)
+	if err != nil {
+		// an unreadable date cannot be compared; start over.
+		return Offer{Day: now.Format(time.DateOnly), Streak: 1, Reward: RewardFor(1), Broken: s.Days > 0}, true
+	}
+	today := now.In(time.FixedZone("", s.Zone)).Format(time.DateOnly)
+	t, _ := time.Parse(time.DateOnly, today)
+	gap := int(t.Sub(last).Hours()) / 24
+	o := Offer{Day: today}
+	switch {
+	case gap < 1:
+		return Offer{}, false
+	case gap == 1:
+		o.Streak = s.Days + 1
+	case gap == 2 && !s.GraceUsed:
+		o.Streak = s.Days + 1
+		o.Grace = true
+	default:
+		o.Streak = 1
+		o.Broken = s.Days > 0
+	}
+	o.Reward = RewardFor(o.Streak)
+	return o, true
+}
+
+// Claim claims the reward available at now, as Next returns it, and
+// records the claim.
+func (s *State) Claim(now time.Time) (Offer, bool) {
+	o, ok := s.Next(now)
+	if !ok {
+		return o, false
+	}
+	if now.Before(s.ClaimedAt) {
+		now = s.ClaimedAt
+	}
+	switch {
+	case o.Grace:
+		s.GraceUsed = true
+	case o.Streak == 1 || o.Streak%GraceRecharge == 0:
+		s.GraceUsed = false
+	}
+	if o.Streak == 1 {
+		// a new streak counts its days in now's time zone. If the date
+		// there is already later, that date is the one claimed.
+		_, s.Zone = now.Zone()
+		o.Day = max(o.Day, now.Format(time.DateOnly))
+	}
+	s.LastDay = o.Day
+	s.ClaimedAt = now
+	s.Days = o.Streak
+	s.Best = max(s.Best, s.Days)
+	return o, true
+}
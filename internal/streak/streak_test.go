@@ -0,0 +1,84 @@
+package streak
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cet = time.FixedZone("CET", 3600)
+
+func at(day, hour int) time.Time {
+	return time.Date(2026, 10, day, hour, 0, 0, 0, cet)
+}
+
+func TestClaim_ConsecutiveDays(t *testing.T) {
+	var s State
+	o, ok := s.Claim(at(1, 9))
+	require.True(t, ok)
+	assert.Equal(t, Offer{Day: "2026-10-01", Streak: 1, Reward: Rewards[0]}, o)
+
+	_, ok = s.Claim(at(1, 23))
+	assert.False(t, ok, "once a day")
+
+	o, ok = s.Claim(at(2, 0))
+	require.True(t, ok, "a new day starts at local midnight")
+	assert.Equal(t, 2, o.Streak)
+	assert.Equal(t, Rewards[1], o.Reward)
+	assert.Equal(t, 2, s.Best)
+}
+
+func TestClaim_GraceAndBreak(t *testing.T) {
+	var s State
+	s.Claim(at(1, 9))
+	s.Claim(at(2, 9))
+
+	o, ok := s.Claim(at(4, 9))
+	require.True(t, ok)
+	assert.True(t, o.Grace, "one missed day is forgiven")
+	assert.Equal(t, 3, o.Streak)
+
+	o, _ = s.Claim(at(6, 9))
+	assert.True(t, o.Broken, "the grace day is used up")
+	assert.Equal(t, 1, o.Streak)
+	assert.Equal(t, 3, s.Best)
+	assert.False(t, s.GraceUsed, "a new streak has its grace day back")
+}
+
+func TestClaim_ClockSetBack(t *testing.T) {
+	var s State
+	s.Claim(at(5, 9))
+	_, ok := s.Next(at(1, 9))
+	assert.False(t, ok, "an earlier clock never reaches a new day")
+	_, ok = s.Claim(at(4, 9))
+	assert.False(t, ok)
+	assert.Equal(t, 1, s.Days)
+}
+
+func TestClaim_TimeZoneChange(t *testing.T) {
+	nz := time.FixedZone("NZ", 13*3600)
+	var s State
+	s.Claim(at(1, 20))
+	// an hour later, far to the east, it is already the next day.
+	_, ok := s.Next(at(1, 21).In(nz))
+	assert.False(t, ok, "days are counted in the zone the streak started in")
+
+	o, ok := s.Claim(at(2, 9).In(nz))
+	require.True(t, ok)
+	assert.Equal(t, 2, o.Streak)
+	assert.Equal(t, "2026-10-02", o.Day)
+	_, ok = s.Next(at(2, 20).In(nz))
+	assert.False(t, ok, "2026-10-03 in New Zealand is still 2026-10-02 at home")
+
+	o, _ = s.Claim(at(10, 9).In(nz))
+	assert.True(t, o.Broken)
+	assert.Equal(t, "2026-10-10", o.Day)
+	assert.Equal(t, 13*3600, s.Zone, "a new streak counts in the new zone")
+}
+
+func TestRewardFor(t *testing.T) {
+	assert.Equal(t, Rewards[0], RewardFor(0))
+	assert.Equal(t, Rewards[len(Rewards)-1], RewardFor(100))
+}
@@ -14,6 +14,12 @@ func EffectiveMultiplier(buyOnID string, upgrades []config.UpgradeConfig, purcha
 	return mult
 }
 
+// MarginalCPS returns the CPS one more unit of b adds: its base CPS with the
+// purchased upgrades that target it and the world's prestige multiplier.
+func MarginalCPS(b BuyOn, upgrades []config.UpgradeConfig, purchased map[string]bool, worldPrestigeMult float64) float64 {
+	return b.BaseCPS() * EffectiveMultiplier(b.ID(), upgrades, purchased) * worldPrestigeMult
+}
+
 // CalculateWorldCPS aggregates the total coins-per-second for a world.
 //
 //   total_CPS = sum(buyOn.BaseCPS * count * upgradeMult) * worldPrestigeMult * globalCPSMult
@@ -0,0 +1,53 @@
+package world
+
+import "github.com/clicker-org/clicker/internal/config"
+
+// MilestoneMet reports whether the world state currently satisfies the
+// milestone's condition. Unknown milestone types are never met.
+func MilestoneMet(m config.CompletionMilestone, ws *WorldState) bool {
+	if ws == nil {
+		return false
+	}
+	switch m.Type {
+	case "clicks":
+		return float64(ws.TotalClicks) >= m.Value
+	case "coins_earned":
+		return ws.TotalCoinsEarned >= m.Value
+	case "buy_ons_owned":
+		total := 0
+		for _, count := range ws.BuyOnCounts {
+			total += count
+		}
+		return float64(total) >= m.Value
+	case "buy_ons_variety":
+		variety := 0
+		for _, count := range ws.BuyOnCounts {
+			if count > 0 {
+				variety++
+			}
+		}
+		return float64(variety) >= m.Value
+	case "prestige_count":
+		return float64(ws.PrestigeCount) >= m.Value
+	default:
+		return false
+	}
+}
+
+// CompletionFromMilestones returns the world completion fraction (0.0–1.0)
+// as the summed weight of the milestones recorded in ws.Milestones.
+func CompletionFromMilestones(milestones []config.CompletionMilestone, ws *WorldState) float64 {
+	if ws == nil {
+		return 0
+	}
+	total := 0.0
+	for _, m := range milestones {
+		if ws.Milestones[m.ID] {
+			total += m.Weight
+		}
+	}
+	if total > 1 {
+		total = 1
+	}
+	return total
+}
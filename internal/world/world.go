@@ -23,20 +23,38 @@ type WorldState struct {
 	Coins            float64 `json:"coins"`
 	TotalCoinsEarned float64 `json:"total_coins_earned"`
 	CPS              float64 `json:"cps"`
+	// BoughtCoins is the part of Coins bought on the exchange market. It is
+	// spent first and never goes into an exchange boost.
+	BoughtCoins float64 `json:"bought_coins,omitempty"`
 
 	BuyOnCounts       map[string]int  `json:"buy_on_counts"`
 	PurchasedUpgrades map[string]bool `json:"purchased_upgrades"`
 
 	PrestigeCount      int     `json:"prestige_count"`
 	PrestigeMultiplier float64 `json:"prestige_multiplier"`
+	// PrestigePoints are earned by prestiging and spent on the nodes of the
+	// world's prestige tree; PrestigeTree records the nodes bought. Both
+	// survive prestiges.
+	PrestigePoints int             `json:"prestige_points"`
+	PrestigeTree   map[string]bool `json:"prestige_tree"`
 
 	ExchangeRate float64 `json:"exchange_rate"`
+	// BoostCooldown is the play time, in seconds, left until the next
+	// exchange boost.
+	BoostCooldown float64 `json:"boost_cooldown,omitempty"`
 
 	OfflineCapUpgradeLevel int `json:"offline_cap_upgrade_level"`
 
 	CompletionPercent float64 `json:"completion_percent"`
+	// Milestones records completion milestones that have been reached. Once
+	// reached, a milestone stays reached even if a prestige resets its metric.
+	Milestones map[string]bool `json:"milestones"`
 
 	TotalClicks int64 `json:"total_clicks"`
+
+	// SinceResetSeconds is the play time since the world was last reset by a
+	// prestige or a challenge run.
+	SinceResetSeconds float64 `json:"since_reset_seconds"`
 }
 
 func NewWorldState(worldID string, baseExchangeRate float64) *WorldState {
@@ -49,13 +67,27 @@ func NewWorldState(worldID string, baseExchangeRate float64) *WorldState {
 		PurchasedUpgrades: make(map[string]bool),
 		PrestigeCount:     0,
 		PrestigeMultiplier: 1.0,
+		PrestigeTree:      make(map[string]bool),
 		ExchangeRate:      baseExchangeRate,
 		OfflineCapUpgradeLevel: 0,
 		CompletionPercent: 0,
+		Milestones:        make(map[string]bool),
 		TotalClicks:       0,
 	}
 }
 
+// Spend takes coins from the balance, market-bought coins first.
+func (ws *WorldState) Spend(coins float64) {
+	ws.Coins -= coins
+	ws.BoughtCoins = max(ws.BoughtCoins-coins, 0)
+}
+
+// BoostableCoins returns the balance an exchange boost draws on: the coins
+// not bought on the market.
+func (ws *WorldState) BoostableCoins() float64 {
+	return max(ws.Coins-ws.BoughtCoins, 0)
+}
+
 // EffectiveOfflineCapHours returns the total offline cap hours for a world,
 // accounting for purchased upgrades. This follows a linear function
 func EffectiveOfflineCapHours(ws *WorldState, baseCapHours float64) float64 {
@@ -0,0 +1,64 @@
+// Package xp works out the account XP granted by gameplay: the rates of the
+// sources in configs/xp.toml (config.XPConfig), their diminishing returns,
+// and the global multiplier applied to every XP grant. The engine counts the
+// grants made from each source and applies them (see engine.Engine.gainXP).
+// It has no Bubble Tea imports.
+package xp
+
+import (
+	"bytes"
+	"log"
+	"math"
+
+	"github.com/BurntSushi/toml"
+	"github.com/clicker-org/clicker/configs"
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/economy"
+)
+
+// Rates are the gameplay XP source rates, loaded from the embedded
+// configs/xp.toml.
+var Rates config.XPConfig
+
+func init() {
+	if _, err := toml.NewDecoder(bytes.NewReader(configs.XPToml)).Decode(&Rates); err != nil {
+		log.Panicf("xp: failed to load XP config: %v", err)
+	}
+}
+
+// Grant returns the XP of the n-th grant from src, counting from 0, before
+// the global multiplier. Once a source's returns have diminished below half
+// an XP it grants nothing, so no source pays out without end.
+func Grant(src config.XPSourceConfig, n int) int {
+	if src.Rate <= 0 {
+		return 0
+	}
+	return int(math.Round(src.Rate / (1 + src.Decay*float64(n))))
+}
+
+// Decades returns how many powers of ten, from 10 up, coins has reached:
+// the grants config.XPSourceCoinsEarned makes for that many coins.
+func Decades(coins float64) int {
+	if coins < 10 {
+		return 0
+	}
+	return int(math.Floor(math.Log10(coins)))
+}
+
+// Multiplier returns the global XP multiplier: the general shop items of
+// type economy.ItemTypeGlobalXPMultiplier among items, each multiplying it
+// by 1 + its Value, times 1 + the XP bonus of the player's perks.
+func Multiplier(items []economy.GeneralShopItem, perkBonus float64) float64 {
+	m := 1 + perkBonus
+	for _, it := range items {
+		if it.Type == economy.ItemTypeGlobalXPMultiplier {
+			m *= 1 + it.Value
+		}
+	}
+	return m
+}
+
+// Apply returns base XP under the multiplier mult, rounded.
+func Apply(base int, mult float64) int {
+	return int(math.Round(float64(base) * mult))
+}
@@ -0,0 +1,40 @@
+package xp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/economy"
+)
+
+func TestRates_AreValid(t *testing.T) {
+	assert.Len(t, Rates.Sources, len(config.XPSources))
+	assert.Empty(t, config.ValidateXP(Rates))
+}
+
+func TestGrant_Diminishes(t *testing.T) {
+	src := config.XPSourceConfig{Rate: 10, Decay: 0.5}
+	assert.Equal(t, 10, Grant(src, 0))
+	assert.Equal(t, 5, Grant(src, 2))
+	assert.Equal(t, 1, Grant(src, 20))
+	assert.Zero(t, Grant(src, 1000), "runs dry")
+	assert.Zero(t, Grant(config.XPSourceConfig{}, 0), "no rate, no XP")
+}
+
+func TestDecades(t *testing.T) {
+	assert.Equal(t, 0, Decades(9.99))
+	assert.Equal(t, 1, Decades(10))
+	assert.Equal(t, 6, Decades(2.5e6))
+}
+
+func TestMultiplier(t *testing.T) {
+	items := []economy.GeneralShopItem{
+		{Type: economy.ItemTypeGlobalXPMultiplier, Value: 0.5},
+		{Type: economy.ItemTypeGlobalCPSMultiplier, Value: 9},
+	}
+	assert.Equal(t, 1.0, Multiplier(nil, 0))
+	assert.InDelta(t, 1.5*1.2, Multiplier(items, 0.2), 1e-9)
+	assert.Equal(t, 18, Apply(10, 1.8))
+}
@@ -1,6 +1,7 @@
 package integration_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/clicker-org/clicker/internal/achievement"
@@ -10,6 +11,19 @@ import (
 	_ "github.com/clicker-org/clicker/internal/world/worlds" 
 )
 
+// TestMain points the config directory at a temporary one, so saving in
+// tests never creates an install key in the real one.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "clicker-integration-")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // newTestEngine builds an Engine backed by the DefaultRegistry (Terra world) and
 // an empty achievement registry. It is the shared starting point for all
 // integration tests that need a running engine.
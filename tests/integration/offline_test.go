@@ -8,6 +8,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/clock"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/gamestate"
 	"github.com/clicker-org/clicker/internal/offline"
 	"github.com/clicker-org/clicker/internal/save"
 	"github.com/clicker-org/clicker/internal/world"
@@ -64,6 +68,7 @@ func TestOfflineApply_OverviewScreen_EarnsGeneralCoins(t *testing.T) {
 	assert.InDelta(t, offline.OverviewOfflineGCCap, result.GeneralCoins, 0.001)
 	assert.InDelta(t, startGC+offline.OverviewOfflineGCCap, eng.State.Player.GeneralCoins, 0.001)
 	assert.Equal(t, float64(0), result.WorldCoins, "no world coins should be earned from overview session")
+	assert.Zero(t, eng.State.Player.LifetimeGeneralCoins, "the trickle is not counted as earned")
 }
 
 // TestOfflineApply_ZeroTimeAway_EarnsNothing verifies that a savedAt in the
@@ -155,6 +160,22 @@ func TestOfflineApply_WorldScreen_CapUpgradeAffectsCap(t *testing.T) {
 	assert.InDelta(t, expectedMax, result.WorldCoins, 0.001)
 }
 
+func TestOfflineApply_WorldScreen_PerksExtendCap(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Worlds["terra"].CPS = 100.0
+	eng.State.Player.Perks = map[string]int{"deep_pockets": 2} // +4h
+
+	savedAt := time.Now().Add(-24 * time.Hour)
+	result := offline.Apply("world", "terra", savedAt, &eng.State, world.DefaultRegistry)
+
+	w, ok := world.DefaultRegistry.Get("terra")
+	require.True(t, ok)
+	capHours := w.OfflineCapHours() + 4
+	require.Len(t, result.Worlds, 1)
+	assert.InDelta(t, capHours, result.Worlds[0].CapHours, 0.001)
+	assert.InDelta(t, 100.0*w.OfflinePercentage()*capHours*3600, result.WorldCoins, 0.001)
+}
+
 // TestEffectiveOfflineCapHours_ScalesWithUpgradeLevel verifies that purchasing
 // offline cap upgrades correctly extends the effective cap.
 func TestEffectiveOfflineCapHours_ScalesWithUpgradeLevel(t *testing.T) {
@@ -175,3 +196,123 @@ func TestEffectiveOfflineCapHours_ScalesWithUpgradeLevel(t *testing.T) {
 		assert.InDelta(t, tc.wantCap, got, 0.001, "upgrade level %d", tc.upgradeLevel)
 	}
 }
+
+// TestOfflineApply_PerWorldBreakdown verifies that only the world the player
+// quit from earns offline income, and that it is reported with the details of
+// its cap.
+func TestOfflineApply_PerWorldBreakdown(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Worlds["terra"].CPS = 100.0
+	eng.State.Worlds["aqua"].CPS = 10.0
+	eng.State.Worlds["aqua"].OfflineCapUpgradeLevel = 1 // 10h cap
+
+	savedAt := time.Now().Add(-9 * time.Hour)
+	result := offline.Apply("world", "terra", savedAt, &eng.State, world.DefaultRegistry)
+
+	require.Len(t, result.Worlds, 1)
+	terra := result.Worlds[0]
+	assert.Equal(t, "terra", terra.WorldID)
+	assert.True(t, terra.CapHit, "terra (8h cap) should hit its cap after 9h")
+	assert.Equal(t, 8*time.Hour, terra.CappedAfter)
+	assert.InDelta(t, time.Hour.Seconds(), terra.Wasted.Seconds(), 5)
+	assert.InDelta(t, terra.Coins, result.WorldCoins, 0.001, "WorldCoins reports the world the player quit from")
+
+	assert.Zero(t, eng.State.Worlds["aqua"].Coins, "only the world quit from earns")
+	assert.Equal(t, float64(0), result.GeneralCoins, "no GC trickle when quitting from a world")
+}
+
+// TestEngineApplyOffline_UnlocksImmediately verifies that achievements,
+// milestones and level-ups caused by offline earnings are evaluated as part of
+// ApplyOffline rather than on the first debounced tick.
+func TestEngineApplyOffline_UnlocksImmediately(t *testing.T) {
+	eng := newEngineWithAchievement(t, achievement.Achievement{
+		ID:      "test_offline_rich",
+		Name:    "Offline Rich",
+		XPGrant: 150,
+		Condition: func(gs gamestate.GameState) bool {
+			return gs.Worlds["terra"].TotalCoinsEarned >= 1000
+		},
+	})
+	eng.State.Worlds["terra"].CPS = 100.0
+
+	result := eng.ApplyOffline("world", "terra", time.Now().Add(-1*time.Hour), offline.ClockPolicyClamp)
+
+	assert.Equal(t, []string{"test_offline_rich"}, result.Achievements)
+	assert.True(t, eng.Earned["test_offline_rich"])
+	assert.True(t, result.LeveledUp())
+	assert.Equal(t, 2, result.NewLevel)
+
+	require.NotEmpty(t, result.Worlds)
+	assert.Contains(t, result.Worlds[0].Milestones, "coins_1k")
+	assert.Contains(t, result.Worlds[0].Milestones, "coins_10k")
+	assert.True(t, eng.State.Worlds["terra"].Milestones["coins_10k"])
+	assert.Greater(t, eng.State.Worlds["terra"].CompletionPercent, 0.0)
+}
+
+// TestEngineApplyOffline_PreexistingMilestonesNotReported verifies that
+// milestones already met before the offline session are not attributed to it.
+func TestEngineApplyOffline_PreexistingMilestonesNotReported(t *testing.T) {
+	eng := newTestEngine(t)
+	eng.State.Worlds["terra"].TotalClicks = 5
+	eng.State.Worlds["terra"].CPS = 100.0
+
+	result := eng.ApplyOffline("world", "terra", time.Now().Add(-1*time.Hour), offline.ClockPolicyClamp)
+
+	require.NotEmpty(t, result.Worlds)
+	assert.NotContains(t, result.Worlds[0].Milestones, "first_click")
+	assert.True(t, eng.State.Worlds["terra"].Milestones["first_click"])
+}
+
+// TestEngineApplyOffline_ClockSetBackAfterForwardJump simulates the classic
+// exploit: move the clock forward, collect a max-cap payout, move it back and
+// play, then relaunch. The high-water mark saved in the first session means
+// the second launch credits nothing until real time catches up.
+func TestEngineApplyOffline_ClockSetBackAfterForwardJump(t *testing.T) {
+	dir := t.TempDir()
+	savePath := filepath.Join(dir, "save.json")
+	realNow := time.Now().Round(0)
+
+	// Session 1: launched with the clock a day ahead.
+	eng := newTestEngine(t)
+	eng.Clock = clock.NewFake(realNow.Add(24 * time.Hour))
+	eng.State.Worlds["terra"].CPS = 100.0
+	eng.Tick(0.1)
+	require.NoError(t, save.Save(eng.State, eng.Earned, save.Settings{ActiveTheme: "space"}, savePath))
+
+	// Session 2: clock corrected, relaunched an hour later in real time.
+	sf, err := save.Load(savePath)
+	require.NoError(t, err)
+	gs := save.GameStateFromSave(sf, world.DefaultRegistry)
+	eng2 := engine.New(gs, world.DefaultRegistry, achievement.NewAchievementRegistry())
+	eng2.Clock = clock.NewFake(realNow.Add(time.Hour))
+
+	result := eng2.ApplyOffline("world", "terra", realNow, offline.ClockPolicyClamp)
+
+	assert.Equal(t, offline.ClockBackwardJump, result.ClockAnomaly)
+	assert.Equal(t, time.Duration(0), result.Duration)
+	assert.Equal(t, float64(0), result.WorldCoins)
+	assert.True(t, eng2.State.Clock.LastWall.After(realNow.Add(23*time.Hour)),
+		"high-water mark survives the load")
+}
+
+// TestEngineApplyOffline_DayForwardJumpIsFlagged moves the clock a day
+// forward between sessions. Nothing earns past terra's 8h cap, so the
+// session is reported as a forward jump and earns no more than the cap.
+func TestEngineApplyOffline_DayForwardJumpIsFlagged(t *testing.T) {
+	realNow := time.Now().Round(0)
+	eng := newTestEngine(t)
+	eng.Clock = clock.NewFake(realNow.Add(24 * time.Hour))
+	eng.State.Worlds["terra"].CPS = 100.0
+
+	result := eng.ApplyOffline("world", "terra", realNow, offline.ClockPolicyClamp)
+
+	w, ok := world.DefaultRegistry.Get("terra")
+	require.True(t, ok)
+	capHours := w.OfflineCapHours()
+	assert.Equal(t, offline.ClockForwardJump, result.ClockAnomaly)
+	assert.Equal(t, 24*time.Hour, result.Duration)
+	assert.InDelta(t, 100.0*w.OfflinePercentage()*capHours*3600, result.WorldCoins, 0.001)
+	require.Len(t, result.Worlds, 1)
+	assert.True(t, result.Worlds[0].CapHit)
+	assert.Equal(t, 24*time.Hour-time.Duration(capHours*float64(time.Hour)), result.Worlds[0].Wasted)
+}
@@ -0,0 +1,70 @@
+package integration_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/replay"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/internal/world"
+)
+
+var updateReplays = flag.Bool("update-replays", false, "rewrite the final state of every replay fixture")
+
+// TestReplays re-runs every recorded session in testdata/replays through the
+// engine and checks it still reaches the recorded final state. To add one,
+// play with `clicker --record testdata/replays/<name>.jsonl`. When a change
+// is meant to alter the outcome, run this test with -update-replays and
+// review the diff of the end lines.
+func TestReplays(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "replays", "*.jsonl"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	achReg := achievement.NewAchievementRegistry()
+	achievement.RegisterDefaults(achReg)
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			l, err := replay.ReadFile(path)
+			require.NoError(t, err)
+			require.NotNil(t, l.Final, "fixtures must be complete recordings")
+
+			eng, err := replay.Run(l, world.DefaultRegistry, achReg)
+			require.NoError(t, err)
+
+			if *updateReplays {
+				final := save.SaveFileFromGameState(eng.State, eng.Earned, l.Final.Settings)
+				final.SavedAt = l.Final.SavedAt // keep fixture diffs to gameplay
+				rewriteFinal(t, path, final)
+				return
+			}
+			assert.Empty(t, replay.Check(*l.Final, eng))
+		})
+	}
+}
+
+// rewriteFinal replaces the end line of the log at path with final.
+func rewriteFinal(t *testing.T, path string, final save.SaveFile) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	var end replay.Action
+	require.NoError(t, json.Unmarshal(lines[len(lines)-1], &end))
+	require.Equal(t, replay.KindEnd, end.Kind)
+
+	end.Final = &final
+	line, err := json.Marshal(end)
+	require.NoError(t, err)
+	lines[len(lines)-1] = line
+	require.NoError(t, os.WriteFile(path, append(bytes.Join(lines, []byte("\n")), '\n'), 0o644))
+}
@@ -1,11 +1,18 @@
 package ui
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/config"
+	"github.com/clicker-org/clicker/internal/daemon"
+	"github.com/clicker-org/clicker/internal/economy"
 	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/level"
+	"github.com/clicker-org/clicker/internal/runs"
 	"github.com/clicker-org/clicker/internal/save"
 	"github.com/clicker-org/clicker/ui/components"
 	"github.com/clicker-org/clicker/ui/components/background"
@@ -37,14 +44,37 @@ type App struct {
 	overview      screens.OverviewModel
 	dashboard     screens.DashboardModel
 	achievements  screens.AchievementsModel
+	perks         screens.PerksModel
+	market        screens.MarketModel
 	worldScreen   screens.WorldModel
 	offlineReport screens.OfflineReportModel
+	loginReward   screens.LoginRewardModel
+	settings      screens.SettingsModel
 	notification  components.Notification
 	statusBar     components.StatusBar
 
 	quit quitDialog
+
+	// startupNotice is shown as a notification once the game is interactive
+	// (after the offline report, if any). Empty when there is nothing to say.
+	startupNotice string
+
+	// lock is this instance's save lock; nil if the app runs without one.
+	lock *save.Lock
+	// readOnly disables every write to the save file, e.g. because another
+	// clicker holds the lock.
+	readOnly bool
+	// remote, if set, is the daemon this app is attached to. The daemon runs
+	// and saves the game; the app's engine only mirrors it.
+	remote *daemon.Mirror
 }
 
+// startupNoticeMsg triggers display of App.startupNotice.
+type startupNoticeMsg struct{}
+
+// startupNoticeDuration is how long the startup notice stays on screen.
+const startupNoticeDuration = 8 * time.Second
+
 // NewApp creates the root App model.
 func NewApp(
 	eng *engine.Engine,
@@ -55,11 +85,16 @@ func NewApp(
 	offlineReport screens.OfflineReportModel,
 	width, height int,
 ) App {
+	loginReward := screens.NewLoginRewardModel(t, eng, eng.Now())
 	initialScreen := engine.ScreenOverview
-	if offlineReport.IsVisible() {
+	switch {
+	case offlineReport.IsVisible():
 		initialScreen = engine.ScreenOfflineReport
+	case loginReward.IsVisible():
+		initialScreen = engine.ScreenLoginReward
 	}
 
+	notification := components.NewNotification(t)
 	app := App{
 		eng:           eng,
 		activeScreen:  initialScreen,
@@ -70,10 +105,14 @@ func NewApp(
 		savePath:      savePath,
 		saveSettings:  settings,
 		overview:      screens.NewOverviewModel(t, &eng.State, eng.WorldReg, width, height),
-		dashboard:     screens.NewDashboardModel(t, &eng.State, width, height),
+		dashboard:     screens.NewDashboardModel(t, eng, notification.History(), width, height),
 		achievements:  screens.NewAchievementsModel(t, eng, width, height),
+		perks:         screens.NewPerksModel(t, eng, width, height),
+		market:        screens.NewMarketModel(t, eng, width, height),
 		offlineReport: offlineReport,
-		notification:  components.NewNotification(t),
+		loginReward:   loginReward,
+		settings:      screens.NewSettingsModel(t, eng, settings, width, height),
+		notification:  notification,
 		statusBar:     components.NewStatusBar(t, width, eng.WorldReg),
 		quit:          newQuitDialog(t),
 	}
@@ -86,12 +125,67 @@ func NewApp(
 	return app
 }
 
+// WithStartupNotice returns a copy of the App that shows text as a
+// notification once the game becomes interactive, e.g. to tell the player
+// that their save was restored from a backup.
+func (a App) WithStartupNotice(text string) App {
+	a.startupNotice = text
+	return a
+}
+
+// WithLock returns a copy of the App that checks it still owns l before every
+// save and refreshes it afterwards. If another instance takes the lock over,
+// the app becomes read-only.
+func (a App) WithLock(l *save.Lock) App {
+	a.lock = l
+	return a
+}
+
+// WithReadOnly returns a copy of the App that never writes the save file.
+func (a App) WithReadOnly() App {
+	a.readOnly = true
+	a.quit.readOnly = true
+	return a
+}
+
+// WithRemote returns a copy of the App attached to a daemon through m: it
+// shows the daemon's game and never ticks or saves its own engine.
+func (a App) WithRemote(m *daemon.Mirror) App {
+	a.remote = m
+	a.quit.attached = true
+	return a
+}
+
+// persist writes the game to the save file unless the app is read-only or
+// attached to a daemon, which saves for itself. If
+// another instance has taken over the save lock, the app switches to
+// read-only instead and returns a notification command.
+func (a *App) persist() tea.Cmd {
+	if a.readOnly || a.remote != nil {
+		return nil
+	}
+	if a.lock != nil && !a.lock.Held() {
+		a.readOnly = true
+		a.quit.readOnly = true
+		return a.notification.Show("Another clicker took over this profile — progress is no longer saved here", 8*time.Second)
+	}
+	_ = save.Save(a.eng.State, a.eng.Earned, a.saveSettings, a.savePath)
+	if a.lock != nil {
+		_ = a.lock.Refresh()
+	}
+	return nil
+}
+
 func (a App) Init() tea.Cmd {
-	return tea.Batch(
+	cmds := []tea.Cmd{
 		tickCmd(),
 		a.overview.Init(),
 		a.offlineReport.Init(),
-	)
+	}
+	if a.startupNotice != "" && !a.offlineReport.IsVisible() && !a.loginReward.IsVisible() {
+		cmds = append(cmds, func() tea.Msg { return startupNoticeMsg{} })
+	}
+	return tea.Batch(cmds...)
 }
 
 func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -103,8 +197,11 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.overview, _ = a.overview.Update(msg)
 		a.dashboard, _ = a.dashboard.Update(msg)
 		a.achievements, _ = a.achievements.Update(msg)
+		a.perks, _ = a.perks.Update(msg)
+		a.market, _ = a.market.Update(msg)
 		a.worldScreen, _ = a.worldScreen.Update(msg)
 		a.offlineReport, _ = a.offlineReport.Update(msg)
+		a.settings, _ = a.settings.Update(msg)
 		return a, nil
 
 	case tea.KeyMsg:
@@ -116,6 +213,12 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case messages.AchievementUnlockedMsg:
 		return a, a.notification.Show("Achievement: "+msg.ID, 3*time.Second)
 
+	case messages.MilestoneReachedMsg:
+		return a, a.notification.Show(a.milestoneText(msg.WorldID, msg.MilestoneID), 3*time.Second)
+
+	case messages.LevelUpMsg:
+		return a, a.notification.Show(levelUpText(msg.PrevLevel, msg.NewLevel), 4*time.Second)
+
 	case messages.NavigateToOverviewMsg:
 		a.activeScreen = engine.ScreenOverview
 		a.eng.State.LastScreen = "overview"
@@ -129,6 +232,21 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.activeScreen = engine.ScreenAchievements
 		return a, nil
 
+	case messages.NavigateToSettingsMsg:
+		a.activeScreen = engine.ScreenSettings
+		return a, nil
+
+	case messages.NavigateToPerksMsg:
+		a.activeScreen = engine.ScreenPerks
+		return a, nil
+
+	case messages.NavigateToMarketMsg:
+		a.activeScreen = engine.ScreenMarket
+		return a, nil
+
+	case messages.ImportSaveMsg:
+		return a.importSave(msg.Save)
+
 	case messages.NavigateToWorldMsg:
 		a.eng.State.ActiveWorldID = msg.WorldID
 		a.eng.State.LastWorldID = msg.WorldID
@@ -139,9 +257,31 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case messages.OfflineReportDismissedMsg:
 		a.offlineReport, _ = a.offlineReport.Update(msg)
+		if a.loginReward.IsVisible() {
+			a.activeScreen = engine.ScreenLoginReward
+			return a, nil
+		}
+		a.activeScreen = engine.ScreenOverview
+		if a.startupNotice != "" {
+			return a, func() tea.Msg { return startupNoticeMsg{} }
+		}
+		return a, nil
+
+	case messages.LoginRewardDismissedMsg:
 		a.activeScreen = engine.ScreenOverview
+		if a.startupNotice != "" {
+			return a, func() tea.Msg { return startupNoticeMsg{} }
+		}
 		return a, nil
 
+	case startupNoticeMsg:
+		text := a.startupNotice
+		a.startupNotice = ""
+		if text == "" {
+			return a, nil
+		}
+		return a, a.notification.Show(text, startupNoticeDuration)
+
 	case components.NotificationDismissMsg:
 		var cmd tea.Cmd
 		a.notification, cmd = a.notification.Update(msg)
@@ -150,7 +290,7 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case components.ConfirmMsg:
 		a.quit = a.quit.close()
 		if msg.Confirmed {
-			_ = save.Save(a.eng.State, a.eng.Earned, a.saveSettings, a.savePath)
+			a.persist()
 			return a, tea.Quit
 		}
 		return a, nil
@@ -165,33 +305,188 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // handleTick advances the engine one tick, processes engine events, and
 // forwards the tick to the active screen for animation updates.
 func (a App) handleTick(msg TickMsg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
+	cmds := []tea.Cmd{tickCmd()}
+
+	var events []engine.EngineEvent
+	if a.remote != nil {
+		var err error
+		if events, err = a.remote.Pull(); err != nil {
+			// the daemon is gone; the caller reports why.
+			return a, tea.Quit
+		}
+	} else {
+		events = a.eng.Tick(float64(engine.TickIntervalMs) / 1000.0)
+	}
+	cmds = append(cmds, a.eventCmds(events)...)
+
+	var animCmd tea.Cmd
+	a, animCmd = a.routeToActiveScreen(msg)
+	if animCmd != nil {
+		cmds = append(cmds, animCmd)
+	}
 
-	events := a.eng.Tick(float64(engine.TickIntervalMs) / 1000.0)
-	cmds = append(cmds, tickCmd())
+	return a, tea.Batch(cmds...)
+}
 
+// eventCmds turns engine events into the commands that show them.
+func (a *App) eventCmds(events []engine.EngineEvent) []tea.Cmd {
+	var cmds []tea.Cmd
 	for _, ev := range events {
 		switch ev.Type {
 		case engine.EventAchievementUnlocked:
 			cmds = append(cmds, func() tea.Msg {
 				return messages.AchievementUnlockedMsg{ID: ev.AchievementID}
 			})
+		case engine.EventMilestoneReached:
+			cmds = append(cmds, func() tea.Msg {
+				return messages.MilestoneReachedMsg{WorldID: ev.WorldID, MilestoneID: ev.MilestoneID}
+			})
 		case engine.EventLevelUp:
 			cmds = append(cmds, func() tea.Msg {
-				return messages.LevelUpMsg{NewLevel: ev.NewLevel}
+				return messages.LevelUpMsg{PrevLevel: ev.PrevLevel, NewLevel: ev.NewLevel}
 			})
+		case engine.EventClockJump:
+			cmds = append(cmds, a.notification.Show("System clock changed — offline time is tracked from trusted play time", 5*time.Second))
+		case engine.EventAutoBuy:
+			// too frequent for a toast; the dashboard lists it.
+			a.notification.Log(a.autoBuyText(ev))
+		case engine.EventChallengeCompleted:
+			cmds = append(cmds, a.notification.Show(a.challengeText(ev.ChallengeID), 4*time.Second))
+		case engine.EventRunCompleted, engine.EventRunFailed:
+			cmds = append(cmds, a.notification.Show(a.runText(ev), 4*time.Second))
+		case engine.EventMarketShock:
+			cmds = append(cmds, a.notification.Show(a.marketShockText(ev), 4*time.Second))
+		case engine.EventXPGained:
+			// too frequent for a toast; the dashboard lists it.
+			a.notification.Log(xpText(ev))
 		case engine.EventAutoSave:
-			_ = save.Save(a.eng.State, a.eng.Earned, a.saveSettings, a.savePath)
+			if cmd := a.persist(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
 		}
 	}
+	return cmds
+}
 
-	var animCmd tea.Cmd
-	a, animCmd = a.routeToActiveScreen(msg)
-	if animCmd != nil {
-		cmds = append(cmds, animCmd)
+// importSave writes an imported save to disk (see save.Import) and replaces
+// the running game with it.
+func (a App) importSave(sf save.SaveFile) (tea.Model, tea.Cmd) {
+	if a.remote != nil {
+		return a, a.notification.Show("Import failed: stop the daemon to import into this profile", 5*time.Second)
+	}
+	if a.readOnly || (a.lock != nil && !a.lock.Held()) {
+		return a, a.notification.Show("Import failed: this profile is open read-only", 5*time.Second)
+	}
+	sf, err := save.Import(sf, a.saveSettings, a.savePath)
+	if err != nil {
+		return a, a.notification.Show("Import failed: "+err.Error(), 5*time.Second)
+	}
+	a.eng.Restore(save.GameStateFromSave(sf, a.eng.WorldReg), sf.Achievements)
+	worldIDs := a.eng.WorldReg.IDs()
+	if len(worldIDs) > 0 {
+		a.worldScreen = a.buildWorldScreen(worldIDs[0])
+	}
+	return a, a.notification.Show("Save imported", 3*time.Second)
+}
+
+// milestoneText returns the notification text for a reached milestone.
+func (a App) milestoneText(worldID, milestoneID string) string {
+	if w, ok := a.eng.WorldReg.Get(worldID); ok {
+		for _, m := range w.Config().CompletionMilestones {
+			if m.ID == milestoneID {
+				return "Milestone (" + w.Name() + "): " + m.Description
+			}
+		}
 	}
+	return "Milestone: " + milestoneID
+}
 
-	return a, tea.Batch(cmds...)
+// autoBuyText returns the notification history line for an auto-buyer run.
+func (a App) autoBuyText(ev engine.EngineEvent) string {
+	name, symbol := ev.WorldID, ""
+	if w, ok := a.eng.WorldReg.Get(ev.WorldID); ok {
+		name, symbol = w.Name(), " "+w.CoinSymbol()
+	}
+	items := make([]string, 0, len(ev.Purchases))
+	total := 0.0
+	for _, p := range ev.Purchases {
+		if p.Count > 1 {
+			items = append(items, fmt.Sprintf("%d× %s", p.Count, p.Name))
+		} else {
+			items = append(items, p.Name)
+		}
+		total += p.Cost
+	}
+	return fmt.Sprintf("Auto-buyer (%s): %s for %s%s", name, strings.Join(items, ", "), economy.FormatCoinsBare(total), symbol)
+}
+
+// levelUpText returns the notification text for leveling up from prev to
+// next, with the rewards granted on the way.
+func levelUpText(prev, next int) string {
+	text := fmt.Sprintf("Level up! Now level %d", next)
+	var rewards []string
+	for _, r := range level.Between(level.Rewards, prev, next) {
+		rewards = append(rewards, level.Describe(r))
+	}
+	if len(rewards) > 0 {
+		text += ": " + strings.Join(rewards, ", ")
+	}
+	return text
+}
+
+// xpText returns the notification history text for XP granted by gameplay.
+func xpText(ev engine.EngineEvent) string {
+	reason := ev.XPSource
+	switch ev.XPSource {
+	case config.XPSourceCoinsEarned:
+		reason = "coins earned passed another power of ten"
+	case config.XPSourceBuyOns:
+		reason = "bought a buy-on"
+	case config.XPSourceMilestones:
+		reason = "reached a milestone"
+	case config.XPSourceExchangeBoosts:
+		reason = "exchange boost"
+	}
+	return fmt.Sprintf("+%d XP: %s", ev.XP, reason)
+}
+
+// challengeText returns the notification text for a completed challenge.
+func (a App) challengeText(id string) string {
+	for _, c := range a.eng.State.Challenges.Active() {
+		if c.ID != id {
+			continue
+		}
+		name := c.World
+		if w, ok := a.eng.WorldReg.Get(c.World); ok {
+			name = w.Name()
+		}
+		return fmt.Sprintf("Challenge complete: %s (+%d XP)", c.Describe(name), c.RewardXP)
+	}
+	return "Challenge complete"
+}
+
+// marketShockText returns the notification for a shock on the market.
+func (a App) marketShockText(ev engine.EngineEvent) string {
+	name := ev.WorldID
+	if w, ok := a.eng.WorldReg.Get(ev.WorldID); ok {
+		name = w.CoinName()
+	}
+	return fmt.Sprintf("Market: %s moves %s ×%.2f — [M] on the overview to trade", ev.Shock, name, ev.Impact)
+}
+
+// runText returns the notification for a challenge run that ended.
+func (a App) runText(ev engine.EngineEvent) string {
+	d, _ := runs.Get(ev.RunID)
+	name := ev.WorldID
+	if w, ok := a.eng.WorldReg.Get(ev.WorldID); ok {
+		name = w.Name()
+	}
+	if ev.Type == engine.EventRunFailed {
+		return fmt.Sprintf("Challenge run failed: %s in %s ran out of time", d.Name, name)
+	}
+	best := a.eng.State.Runs.Record(ev.WorldID, ev.RunID).BestSeconds
+	return fmt.Sprintf("Challenge run complete: %s in %s (best %s, +%.0f%% CPS)",
+		d.Name, name, economy.FormatSeconds(best), d.Bonus*100)
 }
 
 // buildWorldScreen constructs a WorldModel for the given world ID.
@@ -211,6 +506,11 @@ func (a App) buildWorldScreen(worldID string) screens.WorldModel {
 //
 // routeKey never inspects dialog state — that is entirely the gate's concern.
 func (a App) routeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// Screens capturing text get raw keys, so typing "q" does not quit.
+	if a.activeScreen == engine.ScreenSettings && a.settings.Editing() {
+		return a.routeToActiveScreen(msg)
+	}
+
 	abstract := translateKey(msg)
 
 	var cmds []tea.Cmd
@@ -265,6 +565,14 @@ func (a App) routeToActiveScreen(msg tea.Msg) (App, tea.Cmd) {
 		a.worldScreen, cmd = a.worldScreen.Update(msg)
 	case engine.ScreenOfflineReport:
 		a.offlineReport, cmd = a.offlineReport.Update(msg)
+	case engine.ScreenLoginReward:
+		a.loginReward, cmd = a.loginReward.Update(msg)
+	case engine.ScreenSettings:
+		a.settings, cmd = a.settings.Update(msg)
+	case engine.ScreenPerks:
+		a.perks, cmd = a.perks.Update(msg)
+	case engine.ScreenMarket:
+		a.market, cmd = a.market.Update(msg)
 	}
 	return a, cmd
 }
@@ -280,6 +588,14 @@ func (a App) View() string {
 			lipgloss.WithWhitespaceBackground(bg),
 		)
 	}
+	if a.activeScreen == engine.ScreenLoginReward && a.loginReward.IsVisible() {
+		return lipgloss.Place(
+			a.width, a.height,
+			lipgloss.Center, lipgloss.Center,
+			a.loginReward.View(),
+			lipgloss.WithWhitespaceBackground(bg),
+		)
+	}
 
 	var content string
 	switch a.activeScreen {
@@ -291,6 +607,12 @@ func (a App) View() string {
 		content = a.achievements.View()
 	case engine.ScreenWorld:
 		content = a.worldScreen.View()
+	case engine.ScreenSettings:
+		content = a.settings.View()
+	case engine.ScreenPerks:
+		content = a.perks.View()
+	case engine.ScreenMarket:
+		content = a.market.View()
 	default:
 		content = a.overview.View()
 	}
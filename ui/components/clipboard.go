@@ -0,0 +1,36 @@
+package components
+
+import (
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ClipboardCopiedMsg reports the result of CopyToClipboard.
+type ClipboardCopiedMsg struct{ Err error }
+
+// CopyToClipboard returns a command that copies text to the system clipboard
+// (see WriteClipboard) and reports the result as a ClipboardCopiedMsg.
+func CopyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		return ClipboardCopiedMsg{Err: WriteClipboard(text)}
+	}
+}
+
+// WriteClipboard copies text to the system clipboard with an OSC 52 escape
+// sequence, which works over SSH and inside tmux/screen when the terminal
+// supports it. The sequence goes to stderr so it cannot be interleaved with a
+// frame the renderer is writing to stdout.
+func WriteClipboard(text string) error {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(os.Stderr)
+	return err
+}
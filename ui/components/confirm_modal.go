@@ -5,6 +5,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/clicker-org/clicker/ui/messages"
 	"github.com/clicker-org/clicker/ui/theme"
 )
@@ -76,16 +77,29 @@ func (m ConfirmModal) Update(msg tea.Msg) (ConfirmModal, tea.Cmd) {
 // View renders the confirm dialog centered over bgContent.
 // bgContent must be a pre-rendered string of exactly outerWidth×outerHeight cells.
 func (m ConfirmModal) View(title, question, bgContent string, outerWidth, outerHeight int) string {
+	return m.ViewWithDetails(title, question, nil, bgContent, outerWidth, outerHeight)
+}
+
+// ViewWithDetails renders the confirm dialog like View, with the lines of
+// details left-aligned between the question and the buttons. The dialog
+// widens to three quarters of the outer width and grows a row per line,
+// dropping the lines that do not fit in outerHeight.
+func (m ConfirmModal) ViewWithDetails(title, question string, details []string, bgContent string, outerWidth, outerHeight int) string {
 	boxWidth := min(max(outerWidth/2, 44), outerWidth-4)
-	const boxHeight = 7
+	boxHeight := 7
+	if len(details) > 0 {
+		boxWidth = min(max(outerWidth*3/4, 44), outerWidth-4)
+		details = details[:max(min(len(details), outerHeight-boxHeight-1), 0)]
+		boxHeight += len(details) + 1
+	}
 	innerWidth := boxWidth - 2
-	content := m.renderContent(question, innerWidth)
+	content := m.renderContent(question, details, innerWidth)
 	return m.inner.View(title, content, bgContent, outerWidth, outerHeight, boxWidth, boxHeight)
 }
 
-// renderContent builds the question + button rows for the interior of the box.
-// innerWidth is the usable width between the side borders.
-func (m ConfirmModal) renderContent(question string, innerWidth int) string {
+// renderContent builds the question, detail and button rows for the interior
+// of the box. innerWidth is the usable width between the side borders.
+func (m ConfirmModal) renderContent(question string, details []string, innerWidth int) string {
 	questionW := lipgloss.Width(question)
 	qPad := max(innerWidth-questionW, 0)
 	qLeft := qPad / 2
@@ -123,11 +137,13 @@ func (m ConfirmModal) renderContent(question string, innerWidth int) string {
 
 	blank := strings.Repeat(" ", innerWidth)
 
-	return strings.Join([]string{
-		blank,
-		questionLine,
-		blank,
-		buttonLine,
-		blank,
-	}, "\n")
+	rows := []string{blank, questionLine, blank}
+	for _, d := range details {
+		d = ansi.Truncate("  "+d, innerWidth, "…")
+		rows = append(rows, d+strings.Repeat(" ", max(innerWidth-lipgloss.Width(d), 0)))
+	}
+	if len(details) > 0 {
+		rows = append(rows, blank)
+	}
+	return strings.Join(append(rows, buttonLine, blank), "\n")
 }
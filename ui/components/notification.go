@@ -11,11 +11,41 @@ import (
 // NotificationDismissMsg is sent when a notification's timer expires.
 type NotificationDismissMsg struct{}
 
-// Notification is a transient toast notification component.
+// historySize is how many past notifications a NotificationHistory keeps.
+const historySize = 50
+
+// NotificationEntry is one line of the notification history.
+type NotificationEntry struct {
+	At   time.Time
+	Text string
+}
+
+// NotificationHistory keeps the most recent notifications. It is shared by
+// pointer, so that screens can list what the toast has shown.
+type NotificationHistory struct {
+	entries []NotificationEntry
+}
+
+// Add appends text to the history, dropping the oldest entry when full.
+func (h *NotificationHistory) Add(text string) {
+	h.entries = append(h.entries, NotificationEntry{At: time.Now(), Text: text})
+	if len(h.entries) > historySize {
+		h.entries = h.entries[len(h.entries)-historySize:]
+	}
+}
+
+// Entries returns the history, oldest first.
+func (h *NotificationHistory) Entries() []NotificationEntry {
+	return h.entries
+}
+
+// Notification is a transient toast notification component. Everything it
+// shows is also kept in its history.
 type Notification struct {
 	text    string
 	visible bool
 	style   lipgloss.Style
+	history *NotificationHistory
 }
 
 // NewNotification creates a Notification with the given theme.
@@ -26,13 +56,22 @@ func NewNotification(t theme.Theme) Notification {
 			Background(lipgloss.Color(t.SecondaryAccent())).
 			Padding(0, 1).
 			Bold(true),
+		history: &NotificationHistory{},
 	}
 }
 
+// History returns the notification history.
+func (n Notification) History() *NotificationHistory { return n.history }
+
+// Log adds text to the history without showing it, for news too frequent
+// for a toast.
+func (n Notification) Log(text string) { n.history.Add(text) }
+
 // Show displays a notification and returns a Cmd that dismisses it after duration.
 func (n *Notification) Show(text string, duration time.Duration) tea.Cmd {
 	n.text = text
 	n.visible = true
+	n.history.Add(text)
 	return tea.Tick(duration, func(time.Time) tea.Msg {
 		return NotificationDismissMsg{}
 	})
@@ -0,0 +1,58 @@
+package components
+
+import (
+	"slices"
+	"strings"
+)
+
+// chartLevels are the block characters a chart cell is drawn with, from an
+// empty cell to a full one in eighths.
+var chartLevels = []rune(" ▁▂▃▄▅▆▇█")
+
+// Sparkline renders the last width values as a one-line chart, scaled
+// between their lowest and highest value. Fewer values are right-aligned.
+func Sparkline(values []float64, width int) string {
+	return strings.Join(PriceChart(values, width, 1), "")
+}
+
+// PriceChart renders the last width values as a bar chart height lines
+// tall, top line first, scaled between their lowest and highest value so
+// that small moves still show. Fewer values are right-aligned; a flat
+// series is drawn at half height.
+func PriceChart(values []float64, width, height int) []string {
+	width, height = max(width, 1), max(height, 1)
+	values = values[max(len(values)-width, 0):]
+	lines := make([][]rune, height)
+	for i := range lines {
+		lines[i] = []rune(strings.Repeat(" ", width))
+	}
+	if len(values) == 0 {
+		return chartStrings(lines)
+	}
+
+	lo, hi := slices.Min(values), slices.Max(values)
+	steps := height * (len(chartLevels) - 1)
+	offset := width - len(values)
+	for x, v := range values {
+		// the bar's height in eighths of a line, at least one so every
+		// value shows.
+		filled := steps / 2
+		if hi > lo {
+			filled = 1 + int((v-lo)/(hi-lo)*float64(steps-1))
+		}
+		for row := range height {
+			// row 0 is the bottom line.
+			cell := min(max(filled-row*(len(chartLevels)-1), 0), len(chartLevels)-1)
+			lines[height-1-row][offset+x] = chartLevels[cell]
+		}
+	}
+	return chartStrings(lines)
+}
+
+func chartStrings(lines [][]rune) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = string(l)
+	}
+	return out
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/clicker-org/clicker/internal/economy"
 	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/perk"
 	"github.com/clicker-org/clicker/internal/world"
 	"github.com/clicker-org/clicker/ui/theme"
 )
@@ -37,13 +38,19 @@ func (s *StatusBar) SetWidth(w int) {
 }
 
 // View renders the status bar. ws may be nil for overview/dashboard screens.
+// Perk points waiting to be spent are shown at the end.
 func (s StatusBar) View(gs gamestate.GameState, activeWorldID string, ws *world.WorldState) string {
+	pending := ""
+	if n := perk.Available(gs.Player.Level, gs.Player.Perks); n > 0 {
+		pending = fmt.Sprintf(" | PERK PTS: %d", n)
+	}
 	if ws == nil {
 		return s.style.Render(fmt.Sprintf(
-			"GC: %s  |  LVL: %d  |  XP: %d",
+			"GC: %s  |  LVL: %d  |  XP: %d%s",
 			economy.FormatCoinsBare(gs.Player.GeneralCoins),
 			gs.Player.Level,
 			gs.Player.XP,
+			pending,
 		))
 	}
 	coinName := activeWorldID
@@ -53,7 +60,7 @@ func (s StatusBar) View(gs gamestate.GameState, activeWorldID string, ws *world.
 		}
 	}
 	return s.style.Render(fmt.Sprintf(
-		"%s | %s: %s | CPS: %.1f | Prestige: %d | LVL: %d XP: %d",
+		"%s | %s: %s | CPS: %.1f | Prestige: %d | LVL: %d XP: %d%s",
 		activeWorldID,
 		coinName,
 		economy.FormatCoinsBare(ws.Coins),
@@ -61,5 +68,6 @@ func (s StatusBar) View(gs gamestate.GameState, activeWorldID string, ws *world.
 		ws.PrestigeCount,
 		gs.Player.Level,
 		gs.Player.XP,
+		pending,
 	))
 }
@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// LockChoice is the player's answer to the "profile in use" prompt.
+type LockChoice int
+
+const (
+	LockChoiceQuit LockChoice = iota
+	LockChoiceReadOnly
+	LockChoiceTakeOver
+)
+
+var lockChoices = []struct {
+	choice LockChoice
+	label  string
+	help   string
+}{
+	{LockChoiceReadOnly, "[R] Open read-only", "play, but nothing is saved"},
+	{LockChoiceTakeOver, "[T] Take over", "the other instance stops saving"},
+	{LockChoiceQuit, "[Q] Quit", ""},
+}
+
+// LockPrompt is the root Bubble Tea model shown on launch when another
+// clicker process holds the chosen profile's save lock. Like ProfilePicker it
+// runs as its own program before the engine is created.
+type LockPrompt struct {
+	t       theme.Theme
+	profile string
+	holder  save.LockInfo
+	cursor  int
+	choice  LockChoice
+	width   int
+	height  int
+}
+
+// NewLockPrompt returns a prompt for profile, currently held by holder.
+func NewLockPrompt(t theme.Theme, profile string, holder save.LockInfo, w, h int) LockPrompt {
+	return LockPrompt{t: t, profile: profile, holder: holder, width: w, height: h}
+}
+
+// Choice returns the option the player picked. LockChoiceQuit if they quit.
+func (p LockPrompt) Choice() LockChoice { return p.choice }
+
+func (p LockPrompt) Init() tea.Cmd { return nil }
+
+func (p LockPrompt) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+		return p, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			p.choice = LockChoiceQuit
+			return p, tea.Quit
+		case "r", "R":
+			p.choice = LockChoiceReadOnly
+			return p, tea.Quit
+		case "t", "T":
+			p.choice = LockChoiceTakeOver
+			return p, tea.Quit
+		}
+		switch translateKey(msg).(type) {
+		case quitRequestedMsg:
+			p.choice = LockChoiceQuit
+			return p, tea.Quit
+		case messages.NavUpMsg:
+			p.cursor = max(p.cursor-1, 0)
+		case messages.NavDownMsg:
+			p.cursor = min(p.cursor+1, len(lockChoices)-1)
+		case messages.NavConfirmMsg:
+			p.choice = lockChoices[p.cursor].choice
+			return p, tea.Quit
+		}
+	}
+	return p, nil
+}
+
+func (p LockPrompt) View() string {
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(p.t.AccentColor())).Bold(true)
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(p.t.DimText()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(p.t.WarningColor()))
+
+	var sb strings.Builder
+	sb.WriteString(warnSt.Render(fmt.Sprintf("  Profile %q is already open", p.profile)) + "\n\n")
+	sb.WriteString(dimSt.Render("  Held by "+p.holder.String()) + "\n")
+	sb.WriteString(dimSt.Render("  Two instances saving the same profile would lose progress.") + "\n\n")
+	for i, c := range lockChoices {
+		prefix, st := "   ", lipgloss.NewStyle()
+		if i == p.cursor {
+			prefix, st = " ▶ ", accentSt
+		}
+		line := prefix + st.Render(fmt.Sprintf("%-20s", c.label))
+		if c.help != "" {
+			line += " " + dimSt.Render(c.help)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color(p.t.WarningColor())).
+		Padding(1, 2).
+		Width(64).
+		Render(sb.String())
+	return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center, box,
+		lipgloss.WithWhitespaceBackground(lipgloss.Color(p.t.Background())))
+}
@@ -1,6 +1,9 @@
 package messages
 
-import "github.com/clicker-org/clicker/internal/engine"
+import (
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+)
 
 // NavigateToOverviewMsg navigates to the overview/galaxy map screen.
 type NavigateToOverviewMsg struct{}
@@ -17,11 +20,19 @@ type NavigateToWorldMsg struct{ WorldID string }
 // OfflineReportDismissedMsg is sent when the offline report is closed.
 type OfflineReportDismissedMsg struct{}
 
+// LoginRewardDismissedMsg is sent when the login reward panel is closed,
+// whether or not the reward was claimed.
+type LoginRewardDismissedMsg struct{}
+
 // AchievementUnlockedMsg is sent when an achievement is newly unlocked.
 type AchievementUnlockedMsg struct{ ID string }
 
-// LevelUpMsg is sent when the player gains a level.
-type LevelUpMsg struct{ NewLevel int }
+// MilestoneReachedMsg is sent when a world completion milestone is reached.
+type MilestoneReachedMsg struct{ WorldID, MilestoneID string }
+
+// LevelUpMsg is sent when the player gains one or more levels, from
+// PrevLevel to NewLevel.
+type LevelUpMsg struct{ PrevLevel, NewLevel int }
 
 // GameTickMsg carries engine events from a tick.
 type GameTickMsg struct{ Events []engine.EngineEvent }
@@ -34,6 +45,14 @@ type PrestigeConfirmRequestedMsg struct{}
 // user triggers an exchange boost that requires confirmation.
 type ExchangeBoostConfirmRequestedMsg struct{}
 
+// RunStartConfirmRequestedMsg is emitted by the prestige tab when the user
+// starts a challenge run, which resets the world.
+type RunStartConfirmRequestedMsg struct{ RunID string }
+
+// RunAbandonConfirmRequestedMsg is emitted by the prestige tab when the user
+// abandons the challenge run in progress.
+type RunAbandonConfirmRequestedMsg struct{}
+
 // Directional navigation messages — emitted by App for arrow/vim keys.
 // Screens respond to these instead of raw key strings so that new screens get
 // keyboard navigation without repeating key-string switch cases.
@@ -45,3 +64,20 @@ type NavDownMsg struct{}
 type NavLeftMsg struct{}
 type NavRightMsg struct{}
 type NavConfirmMsg struct{}
+
+// ProfileChosenMsg is sent by the profile picker when the player picks a
+// profile to play.
+type ProfileChosenMsg struct{ Name string }
+
+// NavigateToSettingsMsg navigates to the settings screen.
+type NavigateToSettingsMsg struct{}
+
+// NavigateToPerksMsg navigates to the account perks screen.
+type NavigateToPerksMsg struct{}
+
+// NavigateToMarketMsg navigates to the exchange market screen.
+type NavigateToMarketMsg struct{}
+
+// ImportSaveMsg is sent by the settings screen when the player confirms
+// importing a save from an export code. App replaces the running game with it.
+type ImportSaveMsg struct{ Save save.SaveFile }
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/screens"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// ProfilePicker is the root Bubble Tea model for the startup profile picker.
+// It runs as its own program before the engine is created, since the engine
+// is built from the chosen profile's save file.
+type ProfilePicker struct {
+	screen screens.ProfilesModel
+	chosen string
+}
+
+// NewProfilePicker returns a picker over store with the cursor on selected.
+func NewProfilePicker(t theme.Theme, store save.ProfileStore, selected string, w, h int) ProfilePicker {
+	return ProfilePicker{screen: screens.NewProfilesModel(t, store, selected, w, h)}
+}
+
+// Chosen returns the profile the player picked, or "" if they quit.
+func (p ProfilePicker) Chosen() string { return p.chosen }
+
+func (p ProfilePicker) Init() tea.Cmd { return nil }
+
+func (p ProfilePicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.screen = p.screen.SetSize(msg.Width, msg.Height)
+		return p, nil
+
+	case messages.ProfileChosenMsg:
+		p.chosen = msg.Name
+		return p, tea.Quit
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return p, tea.Quit
+		}
+		// Raw keys go straight through while a profile name is being typed.
+		if p.screen.Editing() {
+			break
+		}
+		translated := translateKey(msg)
+		if _, ok := translated.(quitRequestedMsg); ok {
+			return p, tea.Quit
+		}
+		var cmd tea.Cmd
+		p.screen, cmd = p.screen.Update(translated)
+		return p, cmd
+	}
+
+	var cmd tea.Cmd
+	p.screen, cmd = p.screen.Update(msg)
+	return p, cmd
+}
+
+func (p ProfilePicker) View() string { return p.screen.View() }
@@ -22,6 +22,10 @@ type quitDialog struct {
 	t     theme.Theme
 	open  bool
 	modal components.ConfirmModal
+	// readOnly changes the question to warn that progress will not be saved.
+	readOnly bool
+	// attached says the game keeps running in the daemon after quitting.
+	attached bool
 }
 
 func newQuitDialog(t theme.Theme) quitDialog {
@@ -77,5 +81,12 @@ func (d quitDialog) view(bgContent string, width, height int) string {
 	if !d.open {
 		return bgContent
 	}
-	return d.modal.View("Quit CLIcker?", "Your progress will be saved.", bgContent, width, height)
+	question := "Your progress will be saved."
+	switch {
+	case d.attached:
+		question = "The daemon keeps playing and saving."
+	case d.readOnly:
+		question = "Read-only — progress will NOT be saved."
+	}
+	return d.modal.View("Quit CLIcker?", question, bgContent, width, height)
 }
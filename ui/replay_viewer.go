@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/replay"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// replayFrame is how often the viewer redraws during playback.
+const replayFrame = 100 * time.Millisecond
+
+// replayMaxStepsPerFrame bounds the work done per frame at high speeds so
+// the viewer stays responsive to keys.
+const replayMaxStepsPerFrame = 100_000
+
+// replayFeedLen is how many recent actions the viewer lists.
+const replayFeedLen = 8
+
+// replaySpeeds are the playback speeds, as multiples of real time.
+var replaySpeeds = []float64{0.5, 1, 2, 5, 10, 25, 50, 100, 250, 1000}
+
+type replayFrameMsg struct{}
+
+func replayFrameCmd() tea.Cmd {
+	return tea.Tick(replayFrame, func(time.Time) tea.Msg { return replayFrameMsg{} })
+}
+
+// ReplayViewer is the root Bubble Tea model for `clicker replay --watch`. It
+// plays an action log back through the engine at an adjustable speed and
+// shows the state as it evolves. It never saves.
+type ReplayViewer struct {
+	t        theme.Theme
+	name     string
+	player   *replay.Player
+	speedIdx int
+	paused   bool
+	feed     []string
+	err      error
+	width    int
+	height   int
+}
+
+// NewReplayViewer returns a viewer playing p at the listed speed closest to
+// speed. name labels the log in the header.
+func NewReplayViewer(t theme.Theme, name string, p *replay.Player, speed float64, w, h int) ReplayViewer {
+	idx := 0
+	for i, s := range replaySpeeds {
+		if s <= speed {
+			idx = i
+		}
+	}
+	return ReplayViewer{t: t, name: name, player: p, speedIdx: idx, width: w, height: h}
+}
+
+// Err returns the divergence that stopped playback, if any.
+func (v ReplayViewer) Err() error { return v.err }
+
+func (v ReplayViewer) Init() tea.Cmd { return replayFrameCmd() }
+
+func (v ReplayViewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		v.width, v.height = msg.Width, msg.Height
+	case replayFrameMsg:
+		if v.finished() {
+			return v, nil
+		}
+		if !v.paused {
+			v.advance(replaySpeeds[v.speedIdx] * replayFrame.Seconds())
+		}
+		return v, replayFrameCmd()
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return v, tea.Quit
+		case " ", "p":
+			v.paused = !v.paused
+			return v, nil
+		case "+", "=":
+			v.speedIdx = min(v.speedIdx+1, len(replaySpeeds)-1)
+			return v, nil
+		case "-", "_":
+			v.speedIdx = max(v.speedIdx-1, 0)
+			return v, nil
+		}
+		switch translateKey(msg).(type) {
+		case quitRequestedMsg, messages.NavConfirmMsg:
+			return v, tea.Quit
+		case messages.NavRightMsg:
+			v.speedIdx = min(v.speedIdx+1, len(replaySpeeds)-1)
+		case messages.NavLeftMsg:
+			v.speedIdx = max(v.speedIdx-1, 0)
+		}
+	}
+	return v, nil
+}
+
+func (v ReplayViewer) finished() bool { return v.err != nil || v.player.Done() }
+
+// advance replays seconds of game time, or as much as fits in one frame.
+func (v *ReplayViewer) advance(seconds float64) {
+	target := v.player.Elapsed() + seconds
+	for steps := 0; steps < replayMaxStepsPerFrame && !v.player.Done() && v.player.Elapsed() < target; steps++ {
+		a, err := v.player.Step()
+		if err != nil {
+			v.err = err
+			return
+		}
+		if a.Kind != replay.KindTick {
+			v.feed = append(v.feed, fmt.Sprintf("%7.1fs  %s", v.player.Elapsed(), a))
+			if len(v.feed) > replayFeedLen {
+				v.feed = v.feed[len(v.feed)-replayFeedLen:]
+			}
+		}
+	}
+}
+
+func (v ReplayViewer) View() string {
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(v.t.AccentColor())).Bold(true)
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(v.t.DimText()))
+	coinSt := lipgloss.NewStyle().Foreground(lipgloss.Color(v.t.CoinColor()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(v.t.WarningColor()))
+	errSt := lipgloss.NewStyle().Foreground(lipgloss.Color(v.t.ErrorColor()))
+	okSt := lipgloss.NewStyle().Foreground(lipgloss.Color(v.t.SuccessColor()))
+
+	eng := v.player.Engine()
+	var sb strings.Builder
+	sb.WriteString(accentSt.Render("  Replay  ") + dimSt.Render(v.name) + "\n\n")
+
+	total := max(v.player.TotalTicks(), 1)
+	pct := float64(v.player.Ticks()) / float64(total)
+	status := fmt.Sprintf("×%g", replaySpeeds[v.speedIdx])
+	switch {
+	case v.err != nil:
+		status = errSt.Render("diverged")
+	case v.player.Done():
+		status = okSt.Render("finished")
+	case v.paused:
+		status = warnSt.Render("paused " + status)
+	}
+	sb.WriteString(fmt.Sprintf("  tick %d / %d  %s  %s\n", v.player.Ticks(), total, replayBar(pct, 30), status))
+	sb.WriteString(dimSt.Render(fmt.Sprintf("  %.0fs of play", v.player.Elapsed())) + "\n\n")
+
+	p := eng.State.Player
+	sb.WriteString(fmt.Sprintf("  LVL %-3d XP %-8d %s\n\n", p.Level, p.XP, coinSt.Render(economy.FormatCoins(p.GeneralCoins, "GC"))))
+	for _, w := range eng.WorldReg.List() {
+		ws, ok := eng.State.Worlds[w.ID()]
+		if !ok {
+			continue
+		}
+		owned := 0
+		for _, n := range ws.BuyOnCounts {
+			owned += n
+		}
+		sb.WriteString(fmt.Sprintf("  %-8s %s %s  %3d owned  prestige %d\n", w.Name(),
+			coinSt.Render(fmt.Sprintf("%-12s", economy.FormatCoins(ws.Coins, w.CoinSymbol()))),
+			dimSt.Render(fmt.Sprintf("%-10s", economy.FormatCPS(ws.CPS)+" CPS")),
+			owned, ws.PrestigeCount))
+	}
+
+	sb.WriteString("\n")
+	for _, line := range v.feed {
+		sb.WriteString(dimSt.Render("  "+line) + "\n")
+	}
+	for i := len(v.feed); i < replayFeedLen; i++ {
+		sb.WriteString("\n")
+	}
+	if v.err != nil {
+		sb.WriteString("\n" + errSt.Render("  "+v.err.Error()) + "\n")
+	}
+	sb.WriteString("\n" + dimSt.Render("  [space] pause  [+/-] speed  [q] quit"))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(v.t.BorderColor())).
+		Padding(1, 2).
+		Width(72).
+		Render(sb.String())
+	return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, box,
+		lipgloss.WithWhitespaceBackground(lipgloss.Color(v.t.Background())))
+}
+
+func replayBar(pct float64, width int) string {
+	filled := int(pct * float64(width))
+	filled = min(max(filled, 0), width)
+	return "[" + strings.Repeat("█", filled) + strings.Repeat("░", width-filled) + "]"
+}
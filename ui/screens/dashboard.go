@@ -6,34 +6,57 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/challenge"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/level"
+	"github.com/clicker-org/clicker/internal/player"
+	"github.com/clicker-org/clicker/ui/components"
 	"github.com/clicker-org/clicker/ui/messages"
 	"github.com/clicker-org/clicker/ui/theme"
 )
 
-// DashboardModel is the statistics dashboard screen.
+// DashboardModel is the statistics dashboard screen. Below the statistics it
+// shows the challenges, where the selected daily challenge can be rerolled,
+// and lists the most recent notifications.
 type DashboardModel struct {
-	t      theme.Theme
-	gs     *gamestate.GameState
-	width  int
-	height int
+	t       theme.Theme
+	eng     *engine.Engine
+	history *components.NotificationHistory
+	width   int
+	height  int
+	cursor  int // index of the selected daily challenge
 }
 
-// NewDashboardModel creates a DashboardModel.
-func NewDashboardModel(t theme.Theme, gs *gamestate.GameState, width, height int) DashboardModel {
-	return DashboardModel{t: t, gs: gs, width: width, height: height}
+// NewDashboardModel creates a DashboardModel. history may be nil.
+func NewDashboardModel(t theme.Theme, eng *engine.Engine, history *components.NotificationHistory, width, height int) DashboardModel {
+	return DashboardModel{t: t, eng: eng, history: history, width: width, height: height}
 }
 
 func (m DashboardModel) Init() tea.Cmd { return nil }
 
 func (m DashboardModel) Update(msg tea.Msg) (DashboardModel, tea.Cmd) {
 	switch msg := msg.(type) {
+	case messages.NavUpMsg:
+		m.cursor = max(m.cursor-1, 0)
+	case messages.NavDownMsg:
+		m.cursor = max(min(m.cursor+1, len(m.eng.State.Challenges.Daily)-1), 0)
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "r", "R":
+			if daily := m.eng.State.Challenges.Daily; m.cursor < len(daily) {
+				m.eng.RerollChallenge(daily[m.cursor].ID)
+			}
 		case "esc":
 			return m, func() tea.Msg { return messages.NavigateToOverviewMsg{} }
 		case "a", "A":
 			return m, func() tea.Msg { return messages.NavigateToAchievementsMsg{} }
+		case "s", "S":
+			return m, func() tea.Msg { return messages.NavigateToSettingsMsg{} }
+		case "p", "P":
+			return m, func() tea.Msg { return messages.NavigateToPerksMsg{} }
+		case "m", "M":
+			return m, func() tea.Msg { return messages.NavigateToMarketMsg{} }
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -54,13 +77,30 @@ func (m DashboardModel) View() string {
 	var sb strings.Builder
 	sb.WriteString("\n  DASHBOARD (Phase 4)\n")
 	sb.WriteString("  " + dividerStr[:min(18, len(dividerStr))] + "\n\n")
-	if m.gs != nil {
-		p := m.gs.Player
-		sb.WriteString(fmt.Sprintf("  Level:          %d\n", p.Level))
-		sb.WriteString(fmt.Sprintf("  XP:             %d\n", p.XP))
-		sb.WriteString(fmt.Sprintf("  General Coins:  %.2f GC\n", p.GeneralCoins))
-		sb.WriteString(fmt.Sprintf("  Total Clicks:   %d\n", p.TotalClicks))
-		sb.WriteString(fmt.Sprintf("  Time Played:    %.0fs\n", p.TotalPlaySeconds))
+	p := m.eng.State.Player
+	sb.WriteString(fmt.Sprintf("  Level:          %d\n", p.Level))
+	sb.WriteString(fmt.Sprintf("  XP:             %d (×%.2f)\n", p.XP, m.eng.XPMultiplier()))
+	sb.WriteString(fmt.Sprintf("  General Coins:  %.2f GC\n", p.GeneralCoins))
+	sb.WriteString(fmt.Sprintf("  Total Clicks:   %d\n", p.TotalClicks))
+	sb.WriteString(fmt.Sprintf("  Time Played:    %.0fs\n", p.TotalPlaySeconds))
+	st := m.eng.State.Streak
+	sb.WriteString(fmt.Sprintf("  Login Streak:   %d day(s) (best %d)\n", st.Days, st.Best))
+	// the title and statistics take 10 rows.
+	used := 10
+	for _, panel := range []string{m.rewardsView(), m.challengesView()} {
+		sb.WriteString(panel)
+		used += strings.Count(panel, "\n")
+	}
+	if m.history != nil {
+		// the heading takes 2 rows; newest first.
+		entries := m.history.Entries()
+		room := m.height - 2 - used - 2
+		if len(entries) > 0 && room > 0 {
+			sb.WriteString("\n  Recent notifications\n")
+			for i := len(entries) - 1; i >= 0 && len(entries)-i <= room; i-- {
+				sb.WriteString(fmt.Sprintf("  %s  %s\n", entries[i].At.Format("15:04:05"), entries[i].Text))
+			}
+		}
 	}
 	body := lipgloss.NewStyle().
 		Width(m.width).
@@ -69,13 +109,84 @@ func (m DashboardModel) View() string {
 		Foreground(fg).
 		Render(sb.String())
 
-	helpLine := lipgloss.NewStyle().Width(m.width).Background(bg).Foreground(dimFg).Render("  [Esc] Back to Overview   [A] Achievements")
+	helpLine := lipgloss.NewStyle().Width(m.width).Background(bg).Foreground(dimFg).Render("  [Esc] Back to Overview   [↑↓] Select challenge   [R] Reroll   [A] Achievements   [P] Perks   [M] Market   [S] Settings")
 	return body + "\n" + divider + "\n" + helpLine
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// UpcomingRewards is how many of the next level rewards the dashboard lists.
+const UpcomingRewards = 3
+
+// rewardsView renders the cosmetics unlocked by level and the next level
+// rewards with the XP still needed for each, or "" if there is neither.
+func (m DashboardModel) rewardsView() string {
+	p := m.eng.State.Player
+	upcoming := level.Upcoming(level.Rewards, p.Level, UpcomingRewards)
+	cosmetics := level.Cosmetics(level.Rewards, p.Level)
+	if len(upcoming) == 0 && len(cosmetics) == 0 {
+		return ""
+	}
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	var sb strings.Builder
+	sb.WriteString("\n  Level rewards\n")
+	if len(cosmetics) > 0 {
+		sb.WriteString("    Unlocked: " + strings.Join(cosmetics, ", ") + "\n")
+	}
+	for _, r := range upcoming {
+		togo := fmt.Sprintf("(%d XP to go)", player.XPForLevel(r.Level)-p.XP)
+		sb.WriteString(fmt.Sprintf("    Lv %-4d %s  %s\n", r.Level, level.Describe(r), dim.Render(togo)))
+	}
+	return sb.String()
+}
+
+// challengesView renders the challenges panel, or "" before the first
+// challenges are generated.
+func (m DashboardModel) challengesView() string {
+	cs := m.eng.State.Challenges
+	if len(cs.Daily) == 0 && cs.Session == nil {
+		return ""
+	}
+	accent := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor())).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	success := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+
+	var sb strings.Builder
+	line := func(c challenge.Challenge, selected bool) {
+		prefix, box := "    ", "[ ]"
+		if selected {
+			prefix = "  > "
+		}
+		if c.Completed {
+			box = success.Render("[✓]")
+		}
+		desc := c.Describe(m.worldName(c.World))
+		if selected {
+			desc = accent.Render(desc)
+		}
+		reward := fmt.Sprintf("+%d XP", c.RewardXP)
+		if c.RewardGC > 0 {
+			reward += fmt.Sprintf(" +%s GC", economy.FormatCoinsBare(c.RewardGC))
+		}
+		progress := economy.FormatCoinsBare(c.Progress) + "/" + economy.FormatCoinsBare(c.Target)
+		sb.WriteString(fmt.Sprintf("%s%s %s  %s  %s\n", prefix, box, desc, dim.Render(progress), dim.Render(reward)))
+	}
+
+	left := max(challenge.RerollsPerDay-cs.Rerolls, 0)
+	sb.WriteString(fmt.Sprintf("\n  Daily challenges  %s\n", dim.Render(fmt.Sprintf("(%d reroll(s) left today)", left))))
+	for i, c := range cs.Daily {
+		line(c, i == m.cursor)
+	}
+	if cs.Session != nil {
+		sb.WriteString("  This session\n")
+		line(*cs.Session, false)
+	}
+	return sb.String()
+}
+
+// worldName returns the display name of worldID, or worldID itself if it
+// is not a known world.
+func (m DashboardModel) worldName(worldID string) string {
+	if w, ok := m.eng.WorldReg.Get(worldID); ok {
+		return w.Name()
 	}
-	return b
+	return worldID
 }
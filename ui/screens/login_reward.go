@@ -0,0 +1,104 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/streak"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// LoginRewardModel is the welcome panel shown on launch, after the offline
+// report, when the day's login streak reward has not been claimed yet.
+type LoginRewardModel struct {
+	t        theme.Theme
+	eng      *engine.Engine
+	offer    streak.Offer
+	visible  bool
+	boxStyle lipgloss.Style
+}
+
+// NewLoginRewardModel creates a LoginRewardModel for the reward available at
+// now. It is visible only if there is one to claim.
+func NewLoginRewardModel(t theme.Theme, eng *engine.Engine, now time.Time) LoginRewardModel {
+	offer, ok := eng.LoginReward(now)
+	return LoginRewardModel{
+		t:       t,
+		eng:     eng,
+		offer:   offer,
+		visible: ok,
+		boxStyle: lipgloss.NewStyle().
+			Border(lipgloss.DoubleBorder()).
+			BorderForeground(lipgloss.Color(t.AccentColor())).
+			Padding(1, 2).
+			Width(60),
+	}
+}
+
+// IsVisible returns true if the panel should be shown.
+func (m LoginRewardModel) IsVisible() bool { return m.visible }
+
+func (m LoginRewardModel) Init() tea.Cmd { return nil }
+
+func (m LoginRewardModel) Update(msg tea.Msg) (LoginRewardModel, tea.Cmd) {
+	dismissed := func() tea.Msg { return messages.LoginRewardDismissedMsg{} }
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			m.visible = false
+			return m, dismissed
+		}
+	case messages.NavConfirmMsg:
+		m.eng.ClaimLoginReward(m.eng.Now())
+		m.visible = false
+		return m, dismissed
+	}
+	return m, nil
+}
+
+func (m LoginRewardModel) View() string {
+	if !m.visible {
+		return ""
+	}
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.WarningColor()))
+	coinSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.CoinColor()))
+	successSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+
+	o := m.offer
+	var sb strings.Builder
+	sb.WriteString("             DAILY LOGIN REWARD\n\n")
+	switch {
+	case o.Broken:
+		sb.WriteString(warnSt.Render("  Your streak was lost. A new one starts today.") + "\n\n")
+	case o.Grace:
+		sb.WriteString(warnSt.Render("  You missed a day; your streak was kept this time.") + "\n\n")
+	}
+	sb.WriteString(fmt.Sprintf("  Streak: day %d\n\n  ", o.Streak))
+
+	// one mark per reward tier; today's is highlighted.
+	today := min(o.Streak, len(streak.Rewards))
+	for i := range streak.Rewards {
+		switch {
+		case i+1 < today:
+			sb.WriteString(successSt.Render("●") + " ")
+		case i+1 == today:
+			sb.WriteString(coinSt.Render("◉") + " ")
+		default:
+			sb.WriteString(dimSt.Render("○") + " ")
+		}
+	}
+	sb.WriteString("\n\n")
+	sb.WriteString("  Today: " + coinSt.Render(fmt.Sprintf("+%d XP  +%s GC", o.Reward.XP, economy.FormatCoinsBare(o.Reward.GC))) + "\n")
+	if next := streak.RewardFor(o.Streak + 1); next != o.Reward {
+		sb.WriteString(dimSt.Render(fmt.Sprintf("  Tomorrow: +%d XP  +%s GC", next.XP, economy.FormatCoinsBare(next.GC))) + "\n")
+	}
+	sb.WriteString("\n  [Enter] Claim   [Esc] Later")
+	return m.boxStyle.Render(sb.String())
+}
@@ -0,0 +1,234 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/market"
+	"github.com/clicker-org/clicker/ui/components"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// marketShares are the shares of a balance a trade can be made for, picked
+// with left and right.
+var marketShares = []float64{0.10, 0.25, 0.50, 1.00}
+
+// marketChartHeight is the height, in lines, of the selected coin's chart.
+const marketChartHeight = 8
+
+// MarketModel is the exchange market screen: every world coin's price with
+// its recent trend, and a price history chart of the selected coin, which
+// can be sold for general coins or bought with them.
+type MarketModel struct {
+	t      theme.Theme
+	eng    *engine.Engine
+	width  int
+	height int
+	cursor int // index of the selected world in the world registry
+	share  int // index of the trade share in marketShares
+}
+
+// NewMarketModel creates a MarketModel.
+func NewMarketModel(t theme.Theme, eng *engine.Engine, width, height int) MarketModel {
+	return MarketModel{t: t, eng: eng, width: width, height: height}
+}
+
+func (m MarketModel) Init() tea.Cmd { return nil }
+
+func (m MarketModel) Update(msg tea.Msg) (MarketModel, tea.Cmd) {
+	ids := m.eng.WorldReg.IDs()
+	switch msg := msg.(type) {
+	case messages.NavUpMsg:
+		m.cursor = max(m.cursor-1, 0)
+	case messages.NavDownMsg:
+		m.cursor = max(min(m.cursor+1, len(ids)-1), 0)
+	case messages.NavLeftMsg:
+		m.share = max(m.share-1, 0)
+	case messages.NavRightMsg:
+		m.share = min(m.share+1, len(marketShares)-1)
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "s", "S":
+			if id, ok := m.selected(); ok {
+				m.eng.SellWorldCoins(id, m.sellAmount(id))
+			}
+		case "b", "B":
+			if id, ok := m.selected(); ok {
+				m.eng.BuyWorldCoins(id, m.buyAmount())
+			}
+		case "esc":
+			return m, func() tea.Msg { return messages.NavigateToOverviewMsg{} }
+		case "d", "D":
+			return m, func() tea.Msg { return messages.NavigateToDashboardMsg{} }
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+// selected returns the ID of the selected world.
+func (m MarketModel) selected() (string, bool) {
+	ids := m.eng.WorldReg.IDs()
+	if m.cursor >= len(ids) {
+		return "", false
+	}
+	return ids[m.cursor], true
+}
+
+// sellAmount returns the worldID coins a sale is for: the selected share of
+// the balance.
+func (m MarketModel) sellAmount(worldID string) float64 {
+	ws, ok := m.eng.State.Worlds[worldID]
+	if !ok {
+		return 0
+	}
+	return ws.Coins * marketShares[m.share]
+}
+
+// buyAmount returns the general coins a purchase spends: the selected share
+// of the player's.
+func (m MarketModel) buyAmount() float64 {
+	return m.eng.State.Player.GeneralCoins * marketShares[m.share]
+}
+
+// running reports whether worldID has a challenge run in progress, which
+// rules out buying its coins.
+func (m MarketModel) running(worldID string) bool {
+	_, _, ok := m.eng.State.Runs.Running(worldID)
+	return ok
+}
+
+func (m MarketModel) View() string {
+	bg := lipgloss.Color(m.t.Background())
+	fg := lipgloss.Color(m.t.PrimaryText())
+	borderFg := lipgloss.Color(m.t.BorderColor())
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor()))
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	primarySt := lipgloss.NewStyle().Foreground(fg)
+	successSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+	errorSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.ErrorColor()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.WarningColor()))
+	coinSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.CoinColor()))
+
+	dividerStr := strings.Repeat("─", max(m.width, 1))
+	divider := lipgloss.NewStyle().Width(m.width).Background(bg).Foreground(borderFg).Render(dividerStr)
+
+	// changeText renders a relative price change, green up and red down.
+	changeText := func(c float64) string {
+		s := fmt.Sprintf("%+.1f%%", c*100)
+		switch {
+		case c > 0:
+			return successSt.Render(s)
+		case c < 0:
+			return errorSt.Render(s)
+		}
+		return dimSt.Render(s)
+	}
+
+	cfg := market.Params
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString("  " + accentSt.Bold(true).Render("EXCHANGE MARKET") + "\n")
+	sb.WriteString("  " + coinSt.Render("General Coins: "+economy.FormatCoinsBare(m.eng.State.Player.GeneralCoins)+" GC") + "\n")
+	sb.WriteString(dimSt.Render(fmt.Sprintf("  Prices move every %.0fs of play and drift back to each world's exchange rate.", cfg.StepSeconds)) + "\n")
+	sb.WriteString(dimSt.Render(fmt.Sprintf("  Buying costs %.0f%% above the price; selling pays %.0f%% below it.", cfg.Spread/2*100, cfg.Spread/2*100)) + "\n\n")
+
+	ids := m.eng.WorldReg.IDs()
+	for i, id := range ids {
+		w, _ := m.eng.WorldReg.Get(id)
+		q := m.eng.State.Market.Quotes[id]
+		cursor := "  "
+		nameSt := primarySt
+		if i == m.cursor {
+			cursor = accentSt.Render("▸ ")
+			nameSt = accentSt
+		}
+		sb.WriteString(fmt.Sprintf("  %s%s %s  %s  %s  %s\n",
+			cursor,
+			nameSt.Render(fmt.Sprintf("%-12s", w.CoinName())),
+			dimSt.Render(fmt.Sprintf("%-3s", w.CoinSymbol())),
+			primarySt.Render(fmt.Sprintf("%.6f GC", m.eng.MarketPrice(id))),
+			changeText(q.Change()),
+			accentSt.Render(components.Sparkline(q.History, 24)),
+		))
+	}
+
+	if id, ok := m.selected(); ok {
+		w, _ := m.eng.WorldReg.Get(id)
+		ws := m.eng.State.Worlds[id]
+		q := m.eng.State.Market.Quotes[id]
+		price := m.eng.MarketPrice(id)
+
+		sb.WriteString("\n")
+		sb.WriteString("  " + primarySt.Bold(true).Render(w.CoinName()) +
+			dimSt.Render(fmt.Sprintf(" — exchange rate %.6f GC, %d steps of history", ws.ExchangeRate, len(q.History))) + "\n")
+		chartW := max(m.width-6, 10)
+		for _, line := range components.PriceChart(q.History, chartW, marketChartHeight) {
+			sb.WriteString("  " + dimSt.Render("│") + accentSt.Render(line) + "\n")
+		}
+		sb.WriteString("  " + dimSt.Render("└"+strings.Repeat("─", chartW)) + "\n")
+		sb.WriteString(fmt.Sprintf("  Buy %s   Sell %s",
+			primarySt.Render(fmt.Sprintf("%.6f GC", market.Ask(cfg, price))),
+			primarySt.Render(fmt.Sprintf("%.6f GC", market.Bid(cfg, price))),
+		))
+		if q.Shock != "" {
+			sb.WriteString(dimSt.Render(fmt.Sprintf("   Last shock: %s, %d steps ago", q.Shock, m.eng.State.Market.Step-q.ShockStep)))
+		}
+		sb.WriteString("\n")
+		sb.WriteString("  " + coinSt.Render(fmt.Sprintf("Balance: %s %s", economy.FormatCoinsBare(ws.Coins), w.CoinSymbol())) + "\n\n")
+
+		shares := make([]string, len(marketShares))
+		for i, s := range marketShares {
+			label := fmt.Sprintf("%.0f%%", s*100)
+			if i == m.share {
+				shares[i] = warnSt.Bold(true).Render("[" + label + "]")
+			} else {
+				shares[i] = dimSt.Render(" " + label + " ")
+			}
+		}
+		sb.WriteString("  " + primarySt.Render("Trade: ") + strings.Join(shares, " ") + "\n")
+
+		sell := m.sellAmount(id)
+		sellText := fmt.Sprintf("[S] Sell %s %s for %s GC", economy.FormatCoinsBare(sell), w.CoinSymbol(),
+			economy.FormatCoinsBare(m.eng.SellPreview(id, sell)))
+		if m.eng.CanSellWorldCoins(id, sell) {
+			sb.WriteString("  " + primarySt.Render(sellText) + "\n")
+		} else {
+			sb.WriteString("  " + dimSt.Render(sellText) + "\n")
+		}
+		buy := m.buyAmount()
+		buyText := fmt.Sprintf("[B] Buy %s %s for %s GC", economy.FormatCoinsBare(m.eng.BuyPreview(id, buy)), w.CoinSymbol(),
+			economy.FormatCoinsBare(buy))
+		switch {
+		case m.eng.CanBuyWorldCoins(id, buy):
+			sb.WriteString("  " + primarySt.Render(buyText) + "\n")
+		case m.running(id):
+			sb.WriteString("  " + dimSt.Render(buyText+" (not during a challenge run)") + "\n")
+		default:
+			sb.WriteString("  " + dimSt.Render(buyText) + "\n")
+		}
+	}
+
+	bodyH := max(m.height-2, 1)
+	body := lipgloss.NewStyle().
+		Width(m.width).
+		Height(bodyH).
+		Background(bg).
+		Foreground(fg).
+		Render(sb.String())
+
+	helpLine := lipgloss.NewStyle().
+		Width(m.width).
+		Background(bg).
+		Foreground(dimSt.GetForeground()).
+		Render("  [↑/↓] Select coin   [←/→] Trade share   [S] Sell   [B] Buy   [Esc] Back to Overview   [D] Dashboard")
+
+	return body + "\n" + divider + "\n" + helpLine
+}
@@ -0,0 +1,48 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/world"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme/themes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarket_SellAndBuyTheSelectedShare(t *testing.T) {
+	gs := gamestate.NewGameState()
+	for _, w := range world.DefaultRegistry.List() {
+		gs.Worlds[w.ID()] = world.NewWorldState(w.ID(), w.BaseExchangeRate())
+	}
+	eng := engine.New(gs, world.DefaultRegistry, achievement.NewAchievementRegistry())
+	for range 30 {
+		eng.Tick(10)
+	}
+	m := NewMarketModel(themes.SpaceTheme{}, eng, 120, 40)
+	id := eng.WorldReg.IDs()[0]
+	w, _ := eng.WorldReg.Get(id)
+	ws := eng.State.Worlds[id]
+	ws.Coins = 1000
+
+	view := m.View()
+	assert.Contains(t, view, "EXCHANGE MARKET")
+	assert.Contains(t, view, w.CoinName())
+	assert.Contains(t, view, "[10%]")
+
+	m, _ = m.Update(runeKeyMsg('s'))
+	assert.InDelta(t, 900, ws.Coins, 1e-9, "sells 10% by default")
+	gc := eng.State.Player.GeneralCoins
+	require.Positive(t, gc)
+
+	m, _ = m.Update(messages.NavRightMsg{})
+	m, _ = m.Update(messages.NavRightMsg{})
+	m, _ = m.Update(messages.NavRightMsg{})
+	assert.Contains(t, m.View(), "[100%]")
+	m, _ = m.Update(runeKeyMsg('b'))
+	assert.Zero(t, eng.State.Player.GeneralCoins, "spends all of it")
+	assert.Greater(t, ws.Coins, 900.0)
+}
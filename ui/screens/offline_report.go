@@ -3,9 +3,13 @@ package screens
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/level"
 	"github.com/clicker-org/clicker/internal/offline"
 	"github.com/clicker-org/clicker/internal/world"
 	"github.com/clicker-org/clicker/ui/messages"
@@ -14,26 +18,35 @@ import (
 
 // OfflineReportModel shows the offline income popup on game launch.
 type OfflineReportModel struct {
-	t        theme.Theme
-	result   offline.Result
-	visible  bool
-	boxStyle lipgloss.Style
-	worldReg *world.WorldRegistry
+	t         theme.Theme
+	result    offline.Result
+	visible   bool
+	boxStyle  lipgloss.Style
+	worldReg  *world.WorldRegistry
+	achievReg *achievement.AchievementRegistry
 }
 
 // NewOfflineReportModel creates an OfflineReportModel. The report is shown
-// only if the player was away for at least offline.MinReportDuration.
-func NewOfflineReportModel(t theme.Theme, result offline.Result, worldReg *world.WorldRegistry) OfflineReportModel {
+// if the player was away for at least offline.MinReportDuration, or if the
+// system clock looked wrong on launch.
+// achievReg is used to resolve achievement names and may be nil.
+func NewOfflineReportModel(
+	t theme.Theme,
+	result offline.Result,
+	worldReg *world.WorldRegistry,
+	achievReg *achievement.AchievementRegistry,
+) OfflineReportModel {
 	return OfflineReportModel{
-		t:        t,
-		result:   result,
-		visible:  result.Duration >= offline.MinReportDuration,
-		worldReg: worldReg,
+		t:         t,
+		result:    result,
+		visible:   result.Duration >= offline.MinReportDuration || result.ClockAnomaly != offline.ClockOK,
+		worldReg:  worldReg,
+		achievReg: achievReg,
 		boxStyle: lipgloss.NewStyle().
 			Border(lipgloss.DoubleBorder()).
 			BorderForeground(lipgloss.Color(t.AccentColor())).
 			Padding(1, 2).
-			Width(44),
+			Width(60),
 	}
 }
 
@@ -63,31 +76,143 @@ func (m OfflineReportModel) View() string {
 		return ""
 	}
 
-	hours := int(m.result.Duration.Hours())
-	mins := int(m.result.Duration.Minutes()) % 60
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.WarningColor()))
+	coinSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.CoinColor()))
+	successSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
 
 	var sb strings.Builder
-	sb.WriteString("         WELCOME BACK!\n\n")
-	sb.WriteString(fmt.Sprintf("  You were away for: %dh %dm\n\n", hours, mins))
+	sb.WriteString("               WELCOME BACK!\n\n")
+	sb.WriteString(fmt.Sprintf("  You were away for: %s\n\n", formatAway(m.result.Duration)))
 
-	if m.result.WorldID != "" {
-		sb.WriteString(fmt.Sprintf("  While offline in %s:\n", m.result.WorldID))
-		if m.result.WorldCoins > 0 {
-			coinName := m.result.WorldID
+	if notice := clockNotice(m.result); notice != "" {
+		sb.WriteString(warnSt.Render("  ⚠ "+notice) + "\n\n")
+	}
+
+	// ── Per-world earnings ─────────────────────────────────────────────
+	if len(m.result.Worlds) > 0 {
+		sb.WriteString("  While offline:\n")
+		for _, wr := range m.result.Worlds {
+			name, coinName := wr.WorldID, wr.WorldID
 			if m.worldReg != nil {
-				if w, ok := m.worldReg.Get(m.result.WorldID); ok {
-					coinName = w.CoinName()
+				if w, ok := m.worldReg.Get(wr.WorldID); ok {
+					name, coinName = w.Name(), w.CoinName()
 				}
 			}
-			sb.WriteString(fmt.Sprintf("  + %.0f %s\n\n", m.result.WorldCoins, coinName))
-		} else {
-			sb.WriteString("  No passive income yet. Buy upgrades!\n\n")
+			sb.WriteString(fmt.Sprintf("  %-8s %s\n", name,
+				coinSt.Render(fmt.Sprintf("+ %s %s", economy.FormatCoinsBare(wr.Coins), coinName))))
+			if wr.CapHit {
+				sb.WriteString("           " + warnSt.Render(fmt.Sprintf("cap hit after %s — %s wasted",
+					formatAway(wr.CappedAfter), formatAway(wr.Wasted))) + "\n")
+			}
 		}
+		sb.WriteString("\n")
+	} else if m.result.WorldID != "" {
+		sb.WriteString(fmt.Sprintf("  While offline in %s:\n", m.worldName(m.result.WorldID)))
+		sb.WriteString("  No passive income yet. Buy upgrades!\n\n")
 	}
+
 	if m.result.GeneralCoins > 0 {
-		sb.WriteString(fmt.Sprintf("  + %.2f GC (overview trickle)\n\n", m.result.GeneralCoins))
+		label := "overview trickle"
+		if m.result.GeneralCoinsCapped {
+			label += ", capped"
+		}
+		sb.WriteString("  " + coinSt.Render(fmt.Sprintf("+ %.2f GC", m.result.GeneralCoins)) +
+			dimSt.Render(" ("+label+")") + "\n\n")
 	}
+
+	// ── Unlocks caused by the offline earnings ────────────────────────
+	unlocks := m.unlockLines()
+	if len(unlocks) > 0 {
+		sb.WriteString("  Unlocked while away:\n")
+		for _, line := range unlocks {
+			sb.WriteString("  " + successSt.Render(line) + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
 	sb.WriteString("  [Enter] Continue")
 
 	return m.boxStyle.Render(sb.String())
 }
+
+// unlockLines returns one display line per achievement, milestone and
+// level-up unlocked by the offline earnings.
+func (m OfflineReportModel) unlockLines() []string {
+	var lines []string
+	for _, id := range m.result.Achievements {
+		name := id
+		if m.achievReg != nil {
+			if a, ok := m.achievReg.Get(id); ok {
+				name = a.Name
+			}
+		}
+		lines = append(lines, "★ Achievement: "+name)
+	}
+	for _, wr := range m.result.Worlds {
+		for _, id := range wr.Milestones {
+			lines = append(lines, fmt.Sprintf("◆ %s: %s", m.worldName(wr.WorldID), m.milestoneDescription(wr.WorldID, id)))
+		}
+	}
+	if m.result.LeveledUp() {
+		lines = append(lines, fmt.Sprintf("▲ Level up! %d → %d", m.result.PrevLevel, m.result.NewLevel))
+		for _, r := range level.Between(level.Rewards, m.result.PrevLevel, m.result.NewLevel) {
+			lines = append(lines, fmt.Sprintf("  Level %d: %s", r.Level, level.Describe(r)))
+		}
+	}
+	return lines
+}
+
+func (m OfflineReportModel) worldName(worldID string) string {
+	if m.worldReg != nil {
+		if w, ok := m.worldReg.Get(worldID); ok {
+			return w.Name()
+		}
+	}
+	return worldID
+}
+
+func (m OfflineReportModel) milestoneDescription(worldID, milestoneID string) string {
+	if m.worldReg != nil {
+		if w, ok := m.worldReg.Get(worldID); ok {
+			for _, ms := range w.Config().CompletionMilestones {
+				if ms.ID == milestoneID {
+					return ms.Description
+				}
+			}
+		}
+	}
+	return milestoneID
+}
+
+// clockNotice explains a detected clock anomaly and what was done about it.
+// Returns "" when the clock looked fine.
+func clockNotice(r offline.Result) string {
+	var what string
+	switch r.ClockAnomaly {
+	case offline.ClockOK:
+		return ""
+	case offline.ClockFutureSave:
+		what = "Your save is dated in the future."
+	case offline.ClockBackwardJump:
+		what = "Your system clock moved backward since you last played."
+	case offline.ClockForwardJump:
+		what = "You were away longer than any offline cap, or your system clock jumped forward."
+	}
+	switch r.ClockPolicy {
+	case offline.ClockPolicyIgnore:
+		return what + " No offline progress was granted."
+	case offline.ClockPolicyFlag:
+		return what + " Offline progress was granted as measured."
+	default:
+		if r.ClockAnomaly == offline.ClockForwardJump {
+			return what + " Nothing was earned past the cap."
+		}
+		return what + " Offline time was counted from your last trusted session."
+	}
+}
+
+// formatAway renders a duration as "Xh Ym".
+func formatAway(d time.Duration) string {
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/perk"
 	"github.com/clicker-org/clicker/internal/world"
 	"github.com/clicker-org/clicker/ui/components"
 	"github.com/clicker-org/clicker/ui/messages"
@@ -61,6 +62,12 @@ func (m OverviewModel) Update(msg tea.Msg) (OverviewModel, tea.Cmd) {
 			return m, func() tea.Msg { return messages.NavigateToDashboardMsg{} }
 		case "a", "A":
 			return m, func() tea.Msg { return messages.NavigateToAchievementsMsg{} }
+		case "s", "S":
+			return m, func() tea.Msg { return messages.NavigateToSettingsMsg{} }
+		case "p", "P":
+			return m, func() tea.Msg { return messages.NavigateToPerksMsg{} }
+		case "m", "M":
+			return m, func() tea.Msg { return messages.NavigateToMarketMsg{} }
 		}
 	case messages.NavConfirmMsg:
 		id := m.gmap.FocusedWorldID(worlds)
@@ -128,6 +135,9 @@ func (m OverviewModel) View() string {
 	if m.gs != nil {
 		p := m.gs.Player
 		statsLine = fmt.Sprintf("  General Coins: %.2f GC  |  LVL: %d  |  XP: %d", p.GeneralCoins, p.Level, p.XP)
+		if n := perk.Available(p.Level, p.Perks); n > 0 {
+			statsLine += fmt.Sprintf("  |  PERK PTS: %d", n)
+		}
 	}
 	styledStats := lipgloss.NewStyle().
 		Width(m.width).
@@ -135,7 +145,7 @@ func (m OverviewModel) View() string {
 		Foreground(lipgloss.Color(m.t.CoinColor())).
 		Render(statsLine)
 
-	helpLine := "  [Enter] Enter World   [D] Dashboard   [A] Achievements   [Q] Quit   [?] Help"
+	helpLine := "  [Enter] Enter World   [D] Dashboard   [A] Achievements   [P] Perks   [M] Market   [S] Settings   [Q] Quit   [?] Help"
 	styledHelp := lipgloss.NewStyle().
 		Width(m.width).
 		Background(bg).
@@ -0,0 +1,186 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/perk"
+	"github.com/clicker-org/clicker/ui/components"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// PerksModel is the account perks screen: the perk tree, where the selected
+// perk gains a rank on Enter, and a respec behind a confirm dialog.
+type PerksModel struct {
+	t      theme.Theme
+	eng    *engine.Engine
+	width  int
+	height int
+	cursor int // index of the selected perk in perk.Tree
+
+	confirmModal components.ConfirmModal
+	confirmOpen  bool
+}
+
+// NewPerksModel creates a PerksModel.
+func NewPerksModel(t theme.Theme, eng *engine.Engine, width, height int) PerksModel {
+	return PerksModel{t: t, eng: eng, width: width, height: height}
+}
+
+func (m PerksModel) Init() tea.Cmd { return nil }
+
+func (m PerksModel) Update(msg tea.Msg) (PerksModel, tea.Cmd) {
+	if m.confirmOpen {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if msg.String() == "esc" {
+				m.confirmOpen = false
+			}
+		case messages.NavLeftMsg, messages.NavRightMsg, messages.NavUpMsg, messages.NavDownMsg:
+			m.confirmModal, _ = m.confirmModal.Update(msg)
+		case messages.NavConfirmMsg:
+			if m.confirmModal.ConfirmFocused() {
+				m.eng.RespecPerks()
+			}
+			m.confirmOpen = false
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+		}
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case messages.NavUpMsg:
+		m.cursor = max(m.cursor-1, 0)
+	case messages.NavDownMsg:
+		m.cursor = max(min(m.cursor+1, len(perk.Tree)-1), 0)
+	case messages.NavConfirmMsg:
+		if m.cursor < len(perk.Tree) {
+			m.eng.BuyPerk(perk.Tree[m.cursor].ID)
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "r", "R":
+			if m.eng.CanRespecPerks() {
+				m.confirmModal = components.NewConfirmModal(m.t, "Respec")
+				m.confirmOpen = true
+			}
+		case "esc":
+			return m, func() tea.Msg { return messages.NavigateToOverviewMsg{} }
+		case "d", "D":
+			return m, func() tea.Msg { return messages.NavigateToDashboardMsg{} }
+		}
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+	return m, nil
+}
+
+func (m PerksModel) View() string {
+	bg := lipgloss.Color(m.t.Background())
+	fg := lipgloss.Color(m.t.PrimaryText())
+	borderFg := lipgloss.Color(m.t.BorderColor())
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor()))
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	primarySt := lipgloss.NewStyle().Foreground(fg)
+	successSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.WarningColor()))
+
+	dividerStr := strings.Repeat("─", max(m.width, 1))
+	divider := lipgloss.NewStyle().Width(m.width).Background(bg).Foreground(borderFg).Render(dividerStr)
+
+	p := m.eng.State.Player
+	points := m.eng.PerkPoints()
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString("  " + accentSt.Bold(true).Render("PERKS") + "\n")
+	sb.WriteString(fmt.Sprintf("  %s   %s\n",
+		primarySt.Render(fmt.Sprintf("Level %d", p.Level)),
+		accentSt.Bold(true).Render(fmt.Sprintf("%d perk points to spend", points)),
+	))
+	sb.WriteString(dimSt.Render("  One point per level. Perks apply in every world and survive every reset.") + "\n\n")
+
+	names := make(map[string]string, len(perk.Tree))
+	for _, pk := range perk.Tree {
+		names[pk.ID] = pk.Name
+	}
+	for i, pk := range perk.Tree {
+		rank := p.Perks[pk.ID]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = accentSt.Render("▸ ")
+		}
+		nameSt := primarySt
+		switch {
+		case rank >= pk.MaxRank:
+			nameSt = successSt
+		case !perk.Unlocked(pk, p.Perks):
+			nameSt = dimSt
+		}
+		pips := successSt.Render(strings.Repeat("■", rank)) + dimSt.Render(strings.Repeat("□", max(pk.MaxRank-rank, 0)))
+		line := fmt.Sprintf("  %s%s %s  %s", cursor, nameSt.Render(fmt.Sprintf("%-16s", pk.Name)), pips,
+			dimSt.Render(fmt.Sprintf("%d pt/rank", pk.Cost)))
+		if !perk.Unlocked(pk, p.Perks) {
+			needs := make([]string, len(pk.Requires))
+			for j, id := range pk.Requires {
+				needs[j] = names[id]
+			}
+			line += dimSt.Render("  needs " + strings.Join(needs, ", "))
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if m.cursor < len(perk.Tree) {
+		pk := perk.Tree[m.cursor]
+		sb.WriteString("\n")
+		sb.WriteString("  " + primarySt.Bold(true).Render(pk.Name) + dimSt.Render(" — "+pk.Description) + "\n")
+		switch {
+		case p.Perks[pk.ID] >= pk.MaxRank:
+			sb.WriteString("  " + successSt.Render("Max rank") + "\n")
+		case m.eng.CanBuyPerk(pk.ID):
+			sb.WriteString("  " + warnSt.Bold(true).Render("[Enter]") + primarySt.Render(fmt.Sprintf(" Buy a rank for %d pt", pk.Cost)) + "\n")
+		case !perk.Unlocked(pk, p.Perks):
+			sb.WriteString("  " + dimSt.Render("Needs a rank in the perks it requires first") + "\n")
+		default:
+			sb.WriteString("  " + dimSt.Render(fmt.Sprintf("[Enter] Buy a rank for %d pt (not enough points — level up)", pk.Cost)) + "\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	respec := fmt.Sprintf("[R] Respec: refund every point for %s GC", economy.FormatCoinsBare(m.eng.PerkRespecCost()))
+	if m.eng.CanRespecPerks() {
+		sb.WriteString("  " + primarySt.Render(respec) + "\n")
+	} else {
+		sb.WriteString("  " + dimSt.Render(respec) + "\n")
+	}
+
+	bodyH := max(m.height-2, 1)
+	body := lipgloss.NewStyle().
+		Width(m.width).
+		Height(bodyH).
+		Background(bg).
+		Foreground(fg).
+		Render(sb.String())
+	if m.confirmOpen {
+		body = m.confirmModal.View("CONFIRM RESPEC", fmt.Sprintf(
+			"Clear every perk for %s GC and get the points back?",
+			economy.FormatCoinsBare(m.eng.PerkRespecCost()),
+		), body, m.width, bodyH)
+	}
+
+	helpLine := lipgloss.NewStyle().
+		Width(m.width).
+		Background(bg).
+		Foreground(dimSt.GetForeground()).
+		Render("  [↑/↓] Select   [Enter] Buy rank   [R] Respec   [Esc] Back to Overview   [D] Dashboard")
+
+	return body + "\n" + divider + "\n" + helpLine
+}
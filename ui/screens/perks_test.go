@@ -0,0 +1,37 @@
+package screens
+
+import (
+	"testing"
+
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/perk"
+	"github.com/clicker-org/clicker/internal/world"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme/themes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerks_BuyAndRespec(t *testing.T) {
+	gs := gamestate.NewGameState()
+	gs.Player.Level = 3
+	gs.Player.GeneralCoins = 1000
+	eng := engine.New(gs, world.DefaultRegistry, achievement.NewAchievementRegistry())
+	m := NewPerksModel(themes.SpaceTheme{}, eng, 120, 40)
+	assert.Contains(t, m.View(), "2 perk points to spend")
+
+	m, _ = m.Update(messages.NavDownMsg{})
+	m, _ = m.Update(messages.NavConfirmMsg{})
+	assert.Equal(t, 1, eng.State.Player.Perks[perk.Tree[1].ID], "down selects the next perk")
+	assert.Equal(t, 1, eng.PerkPoints())
+
+	m, _ = m.Update(runeKeyMsg('r'))
+	require.True(t, m.confirmOpen)
+	assert.Contains(t, m.View(), "CONFIRM RESPEC")
+	m, _ = m.Update(messages.NavConfirmMsg{})
+	assert.False(t, m.confirmOpen)
+	assert.Empty(t, eng.State.Player.Perks)
+	assert.Equal(t, 2, eng.PerkPoints())
+}
@@ -0,0 +1,344 @@
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/ui/components"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// profileAction is the pending action while the picker is asking for a name.
+type profileAction int
+
+const (
+	profileActionNone profileAction = iota
+	profileActionCreate
+	profileActionRename
+	profileActionDuplicate
+)
+
+// ProfilesModel is the startup profile picker. It lists every save profile
+// with a summary read from its save file and lets the player create, rename,
+// duplicate and delete profiles before starting the game.
+//
+// While a name is being typed, Editing reports true and the host must pass
+// raw key messages through instead of translating them to Nav*Msg.
+type ProfilesModel struct {
+	t        theme.Theme
+	store    save.ProfileStore
+	profiles []save.ProfileSummary
+	cursor   int
+	width    int
+	height   int
+
+	action profileAction
+	input  string
+
+	confirmOpen bool
+	confirm     components.ConfirmModal
+
+	status string
+	err    bool
+
+	// now returns the current time for "last played" labels. Defaults to time.Now.
+	now func() time.Time
+}
+
+// NewProfilesModel creates a ProfilesModel listing the profiles in store,
+// with the cursor on selected if it exists.
+func NewProfilesModel(t theme.Theme, store save.ProfileStore, selected string, w, h int) ProfilesModel {
+	m := ProfilesModel{t: t, store: store, width: w, height: h, now: time.Now}
+	m.reload(selected)
+	return m
+}
+
+// SetSize updates the dimensions of the screen.
+func (m ProfilesModel) SetSize(w, h int) ProfilesModel {
+	m.width = w
+	m.height = h
+	return m
+}
+
+// Editing reports whether the picker is capturing text input.
+func (m ProfilesModel) Editing() bool { return m.action != profileActionNone }
+
+// Selected returns the name of the profile under the cursor, or "".
+func (m ProfilesModel) Selected() string {
+	if m.cursor < 0 || m.cursor >= len(m.profiles) {
+		return ""
+	}
+	return m.profiles[m.cursor].Name
+}
+
+// reload re-reads the profile list and places the cursor on name if present.
+func (m *ProfilesModel) reload(name string) {
+	profiles, err := m.store.List()
+	if err != nil {
+		m.setStatus(err.Error(), true)
+	}
+	m.profiles = profiles
+	m.cursor = 0
+	for i, p := range profiles {
+		if p.Name == name {
+			m.cursor = i
+		}
+	}
+}
+
+func (m *ProfilesModel) setStatus(s string, isErr bool) {
+	m.status = s
+	m.err = isErr
+}
+
+func (m ProfilesModel) Init() tea.Cmd { return nil }
+
+func (m ProfilesModel) Update(msg tea.Msg) (ProfilesModel, tea.Cmd) {
+	if m.Editing() {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.handleInput(key), nil
+		}
+		return m, nil
+	}
+	if m.confirmOpen {
+		return m.handleConfirm(msg)
+	}
+
+	switch msg := msg.(type) {
+	case messages.NavUpMsg:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case messages.NavDownMsg:
+		if m.cursor < len(m.profiles)-1 {
+			m.cursor++
+		}
+	case messages.NavConfirmMsg:
+		if name := m.Selected(); name != "" {
+			return m, func() tea.Msg { return messages.ProfileChosenMsg{Name: name} }
+		}
+		m.startInput(profileActionCreate, "")
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "n", "N":
+			m.startInput(profileActionCreate, "")
+		case "r", "R":
+			if name := m.Selected(); name != "" {
+				m.startInput(profileActionRename, name)
+			}
+		case "d", "D":
+			if name := m.Selected(); name != "" {
+				m.startInput(profileActionDuplicate, name+"-copy")
+			}
+		case "x", "X", "delete":
+			if m.Selected() != "" {
+				m.confirm = components.NewConfirmModal(m.t, "Delete")
+				m.confirmOpen = true
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *ProfilesModel) startInput(action profileAction, initial string) {
+	m.action = action
+	m.input = initial
+	m.setStatus("", false)
+}
+
+// handleInput edits the pending profile name. Only characters valid in a
+// profile name are accepted.
+func (m ProfilesModel) handleInput(key tea.KeyMsg) ProfilesModel {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.action = profileActionNone
+		return m
+	case tea.KeyEnter:
+		return m.applyInput()
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+		return m
+	case tea.KeyRunes:
+		for _, r := range key.Runes {
+			if len(m.input) < 32 && save.ValidateProfileName(string(r)) == nil {
+				m.input += string(r)
+			}
+		}
+	}
+	return m
+}
+
+func (m ProfilesModel) applyInput() ProfilesModel {
+	name := m.input
+	var err error
+	var done string
+	switch m.action {
+	case profileActionCreate:
+		err = m.store.Create(name)
+		done = fmt.Sprintf("Created profile %q", name)
+	case profileActionRename:
+		old := m.Selected()
+		if old == name {
+			m.action = profileActionNone
+			return m
+		}
+		err = m.store.Rename(old, name)
+		done = fmt.Sprintf("Renamed %q to %q", old, name)
+	case profileActionDuplicate:
+		src := m.Selected()
+		err = m.store.Duplicate(src, name)
+		done = fmt.Sprintf("Copied %q to %q", src, name)
+	}
+	if err != nil {
+		// Keep the input open so the player can fix the name.
+		m.setStatus(err.Error(), true)
+		return m
+	}
+	m.action = profileActionNone
+	m.reload(name)
+	m.setStatus(done, false)
+	return m
+}
+
+func (m ProfilesModel) handleConfirm(msg tea.Msg) (ProfilesModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			m.confirmOpen = false
+		}
+		return m, nil
+	case messages.NavConfirmMsg:
+		if !m.confirm.ConfirmFocused() {
+			m.confirmOpen = false
+			return m, nil
+		}
+		m.confirmOpen = false
+		name := m.Selected()
+		if err := m.store.Delete(name); err != nil {
+			m.setStatus(err.Error(), true)
+			return m, nil
+		}
+		m.reload("")
+		m.setStatus(fmt.Sprintf("Deleted profile %q", name), false)
+		return m, nil
+	case messages.NavUpMsg, messages.NavDownMsg, messages.NavLeftMsg, messages.NavRightMsg:
+		var cmd tea.Cmd
+		m.confirm, cmd = m.confirm.Update(msg)
+		return m, cmd
+	case components.ModalCloseMsg, components.ConfirmMsg:
+		m.confirmOpen = false
+	}
+	return m, nil
+}
+
+func (m ProfilesModel) View() string {
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	coinSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.CoinColor()))
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor())).Bold(true)
+	errSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.ErrorColor()))
+	okSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+
+	var sb strings.Builder
+	sb.WriteString(accentSt.Render("  Choose a profile") + "\n\n")
+
+	if len(m.profiles) == 0 {
+		sb.WriteString(dimSt.Render("  No profiles yet — press N to create one.") + "\n")
+	}
+	for i, p := range m.profiles {
+		prefix := "   "
+		nameSt := lipgloss.NewStyle()
+		if i == m.cursor {
+			prefix = " ▶ "
+			nameSt = accentSt
+		}
+		sb.WriteString(prefix + nameSt.Render(fmt.Sprintf("%-20s", p.Name)) + " " + m.summaryLine(p, coinSt, dimSt, errSt) + "\n")
+	}
+	sb.WriteString("\n")
+
+	if m.Editing() {
+		sb.WriteString(fmt.Sprintf("  %s %s\n", m.inputLabel(), accentSt.Render(m.input+"█")))
+		sb.WriteString(dimSt.Render("  [Enter] Save  [Esc] Cancel") + "\n")
+	} else {
+		sb.WriteString(dimSt.Render("  [Enter] Play  [N]ew  [R]ename  [D]uplicate  [X] Delete  [Q]uit") + "\n")
+	}
+	if m.status != "" {
+		st := okSt
+		if m.err {
+			st = errSt
+		}
+		sb.WriteString("\n  " + st.Render(m.status) + "\n")
+	}
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color(m.t.AccentColor())).
+		Padding(1, 2).
+		Width(72).
+		Render(sb.String())
+	view := lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box,
+		lipgloss.WithWhitespaceBackground(lipgloss.Color(m.t.Background())))
+
+	if m.confirmOpen {
+		return m.confirm.View("Delete profile?",
+			fmt.Sprintf("%q and its backups will be removed.", m.Selected()),
+			view, m.width, m.height)
+	}
+	return view
+}
+
+func (m ProfilesModel) inputLabel() string {
+	switch m.action {
+	case profileActionRename:
+		return "Rename to:"
+	case profileActionDuplicate:
+		return "Copy as:"
+	default:
+		return "New profile:"
+	}
+}
+
+// summaryLine renders the level, GC balance and last played time of a profile.
+func (m ProfilesModel) summaryLine(p save.ProfileSummary, coinSt, dimSt, errSt lipgloss.Style) string {
+	switch {
+	case errors.Is(p.Err, save.ErrUnknownKey):
+		return errSt.Render("from another install — clicker trust")
+	case p.Err != nil:
+		return errSt.Render("unreadable save")
+	case p.Empty:
+		return dimSt.Render("new — never played")
+	}
+	played := lastPlayed(p.LastPlayed, m.now())
+	if p.InUse {
+		played = "in use"
+	}
+	return fmt.Sprintf("LVL %-3d %s %s", p.Level,
+		coinSt.Render(fmt.Sprintf("%-10s", economy.FormatCoins(p.GeneralCoins, "GC"))),
+		dimSt.Render(played))
+}
+
+// lastPlayed formats a last-played timestamp relative to now.
+func lastPlayed(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+}
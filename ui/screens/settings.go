@@ -0,0 +1,260 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/save"
+	"github.com/clicker-org/clicker/ui/components"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// SettingsModel is the settings screen. It hosts save transfer: exporting the
+// running game as a text code (copied with OSC 52) and importing a code from
+// another install after previewing what it would overwrite.
+//
+// While an import code is being pasted, Editing reports true and the host must
+// pass raw key messages through instead of translating them to Nav*Msg.
+type SettingsModel struct {
+	t        theme.Theme
+	eng      *engine.Engine
+	settings save.Settings
+	width    int
+	height   int
+
+	// code is the most recent export code, shown so it can be copied by hand
+	// when the terminal does not support OSC 52.
+	code string
+
+	editing bool
+	input   string
+
+	// pending is the parsed save awaiting confirmation; nil when none.
+	pending     *save.SaveFile
+	changes     []save.Change
+	confirm     components.ConfirmModal
+	confirmOpen bool
+
+	status string
+	err    bool
+}
+
+// NewSettingsModel creates a SettingsModel. settings are the player's local
+// preferences; they are written into exports and kept on import.
+func NewSettingsModel(t theme.Theme, eng *engine.Engine, settings save.Settings, width, height int) SettingsModel {
+	return SettingsModel{t: t, eng: eng, settings: settings, width: width, height: height}
+}
+
+// Editing reports whether the screen is capturing text input.
+func (m SettingsModel) Editing() bool { return m.editing }
+
+func (m SettingsModel) Init() tea.Cmd { return nil }
+
+func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = size.Width
+		m.height = size.Height
+		return m, nil
+	}
+	if m.editing {
+		if key, ok := msg.(tea.KeyMsg); ok {
+			return m.handleInput(key), nil
+		}
+		return m, nil
+	}
+	if m.confirmOpen {
+		return m.handleConfirm(msg)
+	}
+	if m.pending != nil {
+		return m.handlePreview(msg)
+	}
+
+	switch msg := msg.(type) {
+	case components.ClipboardCopiedMsg:
+		if msg.Err != nil {
+			m.setStatus("Could not copy to clipboard — copy the code below by hand", true)
+		} else {
+			m.setStatus("Export code copied to clipboard (if your terminal supports OSC 52)", false)
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, func() tea.Msg { return messages.NavigateToOverviewMsg{} }
+		case "e", "E":
+			return m.export()
+		case "i", "I":
+			m.editing = true
+			m.input = ""
+			m.code = ""
+			m.setStatus("", false)
+		}
+	}
+	return m, nil
+}
+
+func (m *SettingsModel) setStatus(s string, isErr bool) {
+	m.status = s
+	m.err = isErr
+}
+
+// current returns a SaveFile snapshot of the running game.
+func (m SettingsModel) current() save.SaveFile {
+	return save.SaveFileFromGameState(m.eng.State, m.eng.Earned, m.settings)
+}
+
+func (m SettingsModel) export() (SettingsModel, tea.Cmd) {
+	code, err := save.Export(m.current())
+	if err != nil {
+		m.setStatus(err.Error(), true)
+		return m, nil
+	}
+	m.code = code
+	return m, components.CopyToClipboard(code)
+}
+
+// handleInput collects a pasted export code. Whitespace is dropped, since
+// ParseExport ignores it anyway.
+func (m SettingsModel) handleInput(key tea.KeyMsg) SettingsModel {
+	switch key.Type {
+	case tea.KeyEsc:
+		m.editing = false
+	case tea.KeyEnter:
+		m.editing = false
+		return m.parseInput()
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case tea.KeyRunes:
+		m.input += strings.Join(strings.Fields(string(key.Runes)), "")
+	}
+	return m
+}
+
+func (m SettingsModel) parseInput() SettingsModel {
+	sf, err := save.ParseExport(m.input)
+	m.input = ""
+	if err != nil {
+		m.setStatus(err.Error(), true)
+		return m
+	}
+	m.pending = &sf
+	m.changes = save.PreviewImport(m.current(), sf)
+	m.setStatus("", false)
+	return m
+}
+
+// handlePreview handles input while the import preview is shown: Enter asks
+// for confirmation, Esc discards the parsed save.
+func (m SettingsModel) handlePreview(msg tea.Msg) (SettingsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			return m.cancelImport(), nil
+		}
+	case messages.NavConfirmMsg:
+		m.confirm = components.NewConfirmModal(m.t, "Import")
+		m.confirmOpen = true
+	}
+	return m, nil
+}
+
+// handleConfirm gates input while the import confirmation is open. Enter is
+// handled here rather than via components.ConfirmMsg, which App reserves for
+// the quit dialog.
+func (m SettingsModel) handleConfirm(msg tea.Msg) (SettingsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "esc" {
+			return m.cancelImport(), nil
+		}
+	case messages.NavUpMsg, messages.NavDownMsg, messages.NavLeftMsg, messages.NavRightMsg:
+		m.confirm, _ = m.confirm.Update(msg)
+	case messages.NavConfirmMsg:
+		if !m.confirm.ConfirmFocused() {
+			return m.cancelImport(), nil
+		}
+		sf := *m.pending
+		m.pending, m.changes, m.confirmOpen = nil, nil, false
+		m.setStatus("Save imported", false)
+		return m, func() tea.Msg { return messages.ImportSaveMsg{Save: sf} }
+	}
+	return m, nil
+}
+
+func (m SettingsModel) cancelImport() SettingsModel {
+	m.pending, m.changes, m.confirmOpen = nil, nil, false
+	m.setStatus("Import cancelled", false)
+	return m
+}
+
+func (m SettingsModel) View() string {
+	bg := lipgloss.Color(m.t.Background())
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor())).Bold(true)
+	errSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.ErrorColor()))
+	okSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.WarningColor()))
+
+	var sb strings.Builder
+	sb.WriteString("\n  " + accentSt.Render("SETTINGS") + "\n\n")
+	sb.WriteString("  Transfer save\n")
+	sb.WriteString("    [E] Export — copy a code to paste into another install\n")
+	sb.WriteString("    [I] Import — paste a code from another install\n\n")
+
+	switch {
+	case m.editing:
+		sb.WriteString("  Paste export code, then press Enter:\n")
+		shown := m.input
+		if w := max(m.width-8, 10); len(shown) > w {
+			shown = "…" + shown[len(shown)-w+1:]
+		}
+		sb.WriteString("  " + accentSt.Render(shown+"█") + "\n")
+		sb.WriteString(dimSt.Render(fmt.Sprintf("  %d characters  [Enter] Preview  [Esc] Cancel", len(m.input))) + "\n")
+	case m.pending != nil:
+		sb.WriteString("  Import preview (current → imported):\n")
+		for _, c := range m.changes {
+			line := fmt.Sprintf("    %-22s %12s → %s", c.Field, c.From, c.To)
+			if c.Changed() {
+				line = warnSt.Render(line)
+			}
+			sb.WriteString(line + "\n")
+		}
+		sb.WriteString(dimSt.Render("  [Enter] Import   [Esc] Cancel") + "\n")
+	case m.code != "":
+		sb.WriteString("  Export code:\n")
+		width := max(m.width-4, 20)
+		for i := 0; i < len(m.code); i += width {
+			sb.WriteString("  " + dimSt.Render(m.code[i:min(i+width, len(m.code))]) + "\n")
+		}
+	}
+
+	if m.status != "" {
+		st := okSt
+		if m.err {
+			st = errSt
+		}
+		sb.WriteString("\n  " + st.Render(m.status) + "\n")
+	}
+
+	divider := lipgloss.NewStyle().Width(m.width).Background(bg).
+		Foreground(lipgloss.Color(m.t.BorderColor())).Render(strings.Repeat("─", m.width))
+	body := lipgloss.NewStyle().
+		Width(m.width).
+		Height(m.height - 2).
+		Background(bg).
+		Foreground(lipgloss.Color(m.t.PrimaryText())).
+		Render(sb.String())
+	helpLine := lipgloss.NewStyle().Width(m.width).Background(bg).Foreground(lipgloss.Color(m.t.DimText())).
+		Render("  [Esc] Back to Overview   [E] Export   [I] Import")
+	view := body + "\n" + divider + "\n" + helpLine
+
+	if m.confirmOpen {
+		return m.confirm.View("Import save?", "This replaces your current progress.", view, m.width, m.height)
+	}
+	return view
+}
@@ -2,13 +2,16 @@ package screens
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/clicker-org/clicker/internal/economy"
 	"github.com/clicker-org/clicker/internal/engine"
 	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/runs"
 	"github.com/clicker-org/clicker/ui/components"
 	"github.com/clicker-org/clicker/ui/components/background"
 	"github.com/clicker-org/clicker/ui/messages"
@@ -23,10 +26,11 @@ const (
 	ModalNone         ModalType = iota
 	ModalShop                   // focusedHeader index 1
 	ModalPrestige               // focusedHeader index 2
+	ModalAutoBuy                // focusedHeader index 3
 )
 
 // headerCount is the number of items in the top header bar.
-const headerCount = 3
+const headerCount = 4
 
 // worldConfirmType identifies which action a confirm dialog is asking about.
 type worldConfirmType int
@@ -35,10 +39,12 @@ const (
 	worldConfirmNone     worldConfirmType = iota
 	worldConfirmPrestige                  // confirm before prestige reset
 	worldConfirmExchange                  // confirm before exchange boost
+	worldConfirmRunStart                  // confirm before starting a challenge run
+	worldConfirmRunAbandon                // confirm before abandoning a challenge run
 )
 
 // WorldModel hosts the world screen. The click view is always the background;
-// Shop and Prestige open as modal overlays on top.
+// Shop, Prestige and the auto-buyer open as modal overlays on top.
 type WorldModel struct {
 	t       theme.Theme
 	eng     *engine.Engine
@@ -48,8 +54,9 @@ type WorldModel struct {
 	clickTab    tabs.ClickTabModel
 	shopTab     tabs.ShopTabModel
 	prestigeTab tabs.PrestigeTabModel
+	autoBuyTab  tabs.AutoBuyTabModel
 
-	// focusedHeader is the header item the arrow-key cursor sits on (0–2).
+	// focusedHeader is the header item the arrow-key cursor sits on (0–3).
 	// activeModal is the modal currently displayed; ModalNone means no overlay.
 	focusedHeader int
 	activeModal   ModalType
@@ -58,6 +65,7 @@ type WorldModel struct {
 	// confirmModal is the confirm dialog that overlays the prestige modal.
 	// It is only shown when confirmOpen is true.
 	confirmType  worldConfirmType
+	confirmRunID string // the run to start, for worldConfirmRunStart
 	confirmModal components.ConfirmModal
 	confirmOpen  bool
 
@@ -102,6 +110,7 @@ func NewWorldModel(
 		clickTab:     tabs.NewClickTab(eng, worldID, t, animReg, animKey, width, contentH),
 		shopTab:      tabs.NewShopTab(eng, worldID, t, width, contentH),
 		prestigeTab:  tabs.NewPrestigeTab(eng, worldID, t, width, contentH),
+		autoBuyTab:   tabs.NewAutoBuyTab(eng, worldID, t, width, contentH),
 		statusBar:    components.NewStatusBar(t, width, eng.WorldReg),
 		width:        width,
 		height:       height,
@@ -144,6 +153,10 @@ func (m WorldModel) handleConfirmInput(msg tea.Msg) (bool, WorldModel, tea.Cmd)
 				m.eng.ExecutePrestige(m.worldID)
 			case worldConfirmExchange:
 				m.eng.ExecuteExchangeBoost(m.worldID)
+			case worldConfirmRunStart:
+				m.eng.StartRun(m.worldID, m.confirmRunID)
+			case worldConfirmRunAbandon:
+				m.eng.AbandonRun(m.worldID)
 			}
 		}
 		m.confirmOpen = false
@@ -152,17 +165,73 @@ func (m WorldModel) handleConfirmInput(msg tea.Msg) (bool, WorldModel, tea.Cmd)
 	return false, m, nil
 }
 
+// prestigeAdvisorWait is how much longer the prestige advisor weighs playing
+// on before prestiging.
+const prestigeAdvisorWait = 30 * time.Minute
+
+// confirmDetails returns the lines shown under the question of the active
+// confirm: for a prestige, the advisor's recommendation and what the reset
+// keeps and loses.
+func (m WorldModel) confirmDetails() []string {
+	if m.confirmType != worldConfirmPrestige {
+		return nil
+	}
+	ws := m.eng.State.Worlds[m.worldID]
+	if ws == nil {
+		return nil
+	}
+	coinSymbol := m.worldID
+	if w, ok := m.eng.WorldReg.Get(m.worldID); ok {
+		coinSymbol = w.CoinSymbol()
+	}
+	a := m.eng.PrestigeAdvice(m.worldID, prestigeAdvisorWait)
+	wait := economy.FormatSeconds(a.Wait.Seconds())
+
+	var advice string
+	switch a.Recommendation {
+	case engine.AdviceWait:
+		advice = "Advice: wait " + wait + " — it earns more GC per hour"
+	case engine.AdvicePrestige:
+		advice = "Advice: prestige now — waiting earns less GC per hour"
+	default:
+		advice = "Advice: not ready to prestige yet"
+	}
+	rate := func(gcPerHour float64) string {
+		if gcPerHour <= 0 {
+			return ""
+		}
+		return "  (" + economy.FormatCoinsBare(gcPerHour) + " GC/h)"
+	}
+	regain := "Regain " + economy.FormatCPS(a.LostCPS) + "/s: about " + economy.FormatSeconds(a.RegainSeconds) + " after the reset"
+	if math.IsInf(a.RegainSeconds, 1) {
+		regain = "Regain " + economy.FormatCPS(a.LostCPS) + "/s: not with buy-ons alone"
+	}
+	kept := "Kept: lifetime coins, milestones, prestige tree and points, exchange rate, offline cap upgrades"
+	if a.StartBuyOns > 0 {
+		kept += fmt.Sprintf(", %d start buy-on(s)", a.StartBuyOns)
+	}
+	return []string{
+		advice,
+		fmt.Sprintf("Now:      +%s GC  ×%.2f  +%d PP%s", economy.FormatCoinsBare(a.Now.GeneralCoinsEarned), a.Now.PrestigeMultiplier, a.Now.PrestigePoints, rate(a.NowGCPerHour)),
+		fmt.Sprintf("In %-6s +%s GC  ×%.2f  +%d PP%s", wait+":", economy.FormatCoinsBare(a.Later.GeneralCoinsEarned), a.Later.PrestigeMultiplier, a.Later.PrestigePoints, rate(a.LaterGCPerHour)),
+		regain,
+		fmt.Sprintf("Lost: %s %s, %d buy-on(s), %d upgrade(s), %s/s CPS",
+			economy.FormatCoinsBare(a.LostCoins), coinSymbol, a.LostBuyOns, a.LostUpgrades, economy.FormatCPS(a.LostCPS)),
+		kept,
+	}
+}
+
 // confirmContent returns the title and question string for the active confirm.
 func (m WorldModel) confirmContent() (title, question string) {
-	ws := m.eng.State.Worlds[m.worldID]
 	switch m.confirmType {
 	case worldConfirmPrestige:
-		preview := economy.CalculatePrestigeReward(ws.TotalCoinsEarned, ws.PrestigeCount, ws.PrestigeMultiplier)
+		preview := m.eng.PrestigeRewardPreview(m.worldID)
 		return "CONFIRM PRESTIGE", fmt.Sprintf(
-			"Reset world. Earn: +%s GC  ×%.2f  +%d XP",
+			"Reset world. Earn: +%s GC  ×%.2f  +%d XP  +%d PP",
 			economy.FormatCoinsBare(preview.GeneralCoinsEarned),
 			preview.PrestigeMultiplier,
 			preview.XPGrant,
+			preview.PrestigePoints,
 		)
 	case worldConfirmExchange:
 		boost := m.eng.ExchangeBoostPreview(m.worldID)
@@ -176,6 +245,15 @@ func (m WorldModel) confirmContent() (title, question string) {
 			coinSymbol,
 			economy.FormatCoinsBare(boost.GeneralCoinsEarned),
 		)
+	case worldConfirmRunStart:
+		d, _ := runs.Get(m.confirmRunID)
+		return "START CHALLENGE RUN", fmt.Sprintf(
+			"%s: reset world, no prestige rewards. Bonus: +%.0f%% CPS",
+			d.Name, d.Bonus*100,
+		)
+	case worldConfirmRunAbandon:
+		d, _, _ := m.eng.State.Runs.Running(m.worldID)
+		return "ABANDON CHALLENGE RUN", fmt.Sprintf("Give up %s? The world is not restored", d.Name)
 	}
 	return "", ""
 }
@@ -199,6 +277,7 @@ func (m WorldModel) Update(msg tea.Msg) (WorldModel, tea.Cmd) {
 		m.clickTab = m.clickTab.Resize(msg.Width, contentH)
 		m.shopTab = m.shopTab.Resize(msg.Width, contentH)
 		m.prestigeTab = m.prestigeTab.Resize(msg.Width, contentH)
+		m.autoBuyTab = m.autoBuyTab.Resize(msg.Width, contentH)
 
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -224,9 +303,15 @@ func (m WorldModel) Update(msg tea.Msg) (WorldModel, tea.Cmd) {
 			m = m.toggleModalHotkey(ModalPrestige, 2)
 			return m, nil
 
-		case "e", "E":
+		case "b", "B":
+			m = m.toggleModalHotkey(ModalAutoBuy, 3)
+			return m, nil
+
+		case "e", "E", "r", "R", "t", "T":
 			if m.activeModal == ModalPrestige {
-				// Forward E to the prestige tab (may emit ExchangeBoostConfirmRequestedMsg).
+				// Forward E, R and T to the prestige tab (may emit
+				// ExchangeBoostConfirmRequestedMsg or a run confirm request,
+				// or toggle the prestige tree).
 				newModel, c := m.prestigeTab.Update(msg)
 				if pt, ok := newModel.(tabs.PrestigeTabModel); ok {
 					m.prestigeTab = pt
@@ -281,6 +366,19 @@ func (m WorldModel) Update(msg tea.Msg) (WorldModel, tea.Cmd) {
 		m.confirmOpen = true
 		return m, nil
 
+	case messages.RunStartConfirmRequestedMsg:
+		m.confirmType = worldConfirmRunStart
+		m.confirmRunID = msg.RunID
+		m.confirmModal = components.NewConfirmModal(m.t, "Start")
+		m.confirmOpen = true
+		return m, nil
+
+	case messages.RunAbandonConfirmRequestedMsg:
+		m.confirmType = worldConfirmRunAbandon
+		m.confirmModal = components.NewConfirmModal(m.t, "Abandon")
+		m.confirmOpen = true
+		return m, nil
+
 	// Arrow-key cursor: moves the header focus when no modal is open.
 	case messages.NavLeftMsg:
 		if m.activeModal == ModalNone {
@@ -309,22 +407,22 @@ func (m WorldModel) Update(msg tea.Msg) (WorldModel, tea.Cmd) {
 				if c != nil {
 					cmds = append(cmds, c)
 				}
+			} else if m.activeModal == ModalAutoBuy {
+				newModel, c := m.autoBuyTab.Update(msg)
+				if at, ok := newModel.(tabs.AutoBuyTabModel); ok {
+					m.autoBuyTab = at
+				}
+				if c != nil {
+					cmds = append(cmds, c)
+				}
 			} else if m.activeModal == ModalPrestige {
-				// Enter confirms prestige from the modal content; arrows still control [Esc] focus.
-				if _, isConfirm := msg.(messages.NavConfirmMsg); isConfirm {
-					newModel, c := m.prestigeTab.Update(msg)
-					if pt, ok := newModel.(tabs.PrestigeTabModel); ok {
-						m.prestigeTab = pt
-					}
-					if c != nil {
-						cmds = append(cmds, c)
-					}
-				} else {
-					newModal, c := m.modal.Update(msg)
-					m.modal = newModal
-					if c != nil {
-						cmds = append(cmds, c)
-					}
+				// Enter confirms prestige; up/down select a challenge run.
+				newModel, c := m.prestigeTab.Update(msg)
+				if pt, ok := newModel.(tabs.PrestigeTabModel); ok {
+					m.prestigeTab = pt
+				}
+				if c != nil {
+					cmds = append(cmds, c)
 				}
 			}
 			return m, tea.Batch(cmds...)
@@ -339,6 +437,9 @@ func (m WorldModel) Update(msg tea.Msg) (WorldModel, tea.Cmd) {
 			case 2:
 				m.activeModal = ModalPrestige
 				m.modal = components.NewTabModal(m.t)
+			case 3:
+				m.activeModal = ModalAutoBuy
+				m.modal = components.NewTabModal(m.t)
 			}
 			return m, nil
 		}
@@ -393,6 +494,7 @@ func (m WorldModel) View() string {
 		{"[C]lick", ModalNone},
 		{"[S]hop", ModalShop},
 		{"[P]restige", ModalPrestige},
+		{"Auto-[B]uyer", ModalAutoBuy},
 	}
 
 	headerParts := make([]string, len(items))
@@ -440,6 +542,8 @@ func (m WorldModel) View() string {
 			title, content = "SHOP", m.shopTab.View()
 		case ModalPrestige:
 			title, content = "PRESTIGE", m.prestigeTab.View()
+		case ModalAutoBuy:
+			title, content = "AUTO-BUYER", m.autoBuyTab.View()
 		}
 		// Overlay the modal on top of the live click-tab background.
 		modalView := m.modal.View(title, content, bgContent, m.width, contentHeight)
@@ -447,7 +551,7 @@ func (m WorldModel) View() string {
 		if m.confirmOpen {
 			// Overlay the confirm dialog on top of the prestige modal.
 			confirmTitle, confirmQuestion := m.confirmContent()
-			contentArea = m.confirmModal.View(confirmTitle, confirmQuestion, modalView, m.width, contentHeight)
+			contentArea = m.confirmModal.ViewWithDetails(confirmTitle, confirmQuestion, m.confirmDetails(), modalView, m.width, contentHeight)
 		} else {
 			contentArea = modalView
 		}
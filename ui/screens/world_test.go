@@ -7,6 +7,7 @@ import (
 	"github.com/clicker-org/clicker/internal/achievement"
 	"github.com/clicker-org/clicker/internal/engine"
 	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/runs"
 	"github.com/clicker-org/clicker/internal/world"
 	_ "github.com/clicker-org/clicker/internal/world/worlds"
 	"github.com/clicker-org/clicker/ui/messages"
@@ -70,6 +71,44 @@ func TestWorldPrestige_EnterRequestsConfirm(t *testing.T) {
 	assert.True(t, ok, "expected PrestigeConfirmRequestedMsg, got %T", msg)
 }
 
+func TestWorldPrestige_StartsSelectedRun(t *testing.T) {
+	m := newTestWorldModel(t)
+	m, _ = m.Update(runeKeyMsg('p'))
+	m, _ = m.Update(messages.NavDownMsg{})
+
+	m, cmd := m.Update(runeKeyMsg('r'))
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(messages.RunStartConfirmRequestedMsg)
+	require.True(t, ok, "expected RunStartConfirmRequestedMsg")
+	assert.Equal(t, runs.Definitions[1].ID, msg.RunID, "down selects the next run")
+
+	m, _ = m.Update(msg)
+	require.True(t, m.confirmOpen)
+	m, _ = m.Update(messages.NavConfirmMsg{})
+	assert.Equal(t, runs.Definitions[1].ID, m.eng.State.Runs.Active["terra"].ID)
+
+	_, cmd = m.Update(runeKeyMsg('r'))
+	require.NotNil(t, cmd)
+	_, ok = cmd().(messages.RunAbandonConfirmRequestedMsg)
+	assert.True(t, ok, "R abandons the run in progress")
+}
+
+func TestWorldPrestige_TreeBuysSelectedNode(t *testing.T) {
+	m := newTestWorldModel(t)
+	ws := m.eng.State.Worlds["terra"]
+	ws.PrestigePoints = 1
+	m, _ = m.Update(runeKeyMsg('p'))
+	m, _ = m.Update(runeKeyMsg('t'))
+	assert.Contains(t, m.View(), "Prestige Tree")
+
+	m, _ = m.Update(messages.NavConfirmMsg{})
+	assert.True(t, ws.PrestigeTree["head_start"], "the first row is selected")
+	assert.Zero(t, ws.PrestigePoints)
+
+	m, _ = m.Update(runeKeyMsg('t'))
+	assert.NotContains(t, m.View(), "Prestige Tree")
+}
+
 func TestWorldShop_NumberHotkeySelectsIndexedItem(t *testing.T) {
 	m := newTestWorldModel(t)
 	ws := m.eng.State.Worlds["terra"]
@@ -106,3 +145,18 @@ func newTestWorldModel(t *testing.T) WorldModel {
 func runeKeyMsg(r rune) tea.KeyMsg {
 	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
 }
+
+func TestWorldPrestige_ConfirmShowsAdviceAndBreakdown(t *testing.T) {
+	m := newTestWorldModel(t)
+	ws := m.eng.State.Worlds["terra"]
+	ws.TotalCoinsEarned = 1_000_000
+	ws.BuyOnCounts["auto_miner"] = 12
+	m, _ = m.Update(runeKeyMsg('p'))
+	m, _ = m.Update(messages.PrestigeConfirmRequestedMsg{})
+	require.True(t, m.confirmOpen)
+
+	view := m.View()
+	assert.Contains(t, view, "Advice: prestige now")
+	assert.Contains(t, view, "12 buy-on(s)")
+	assert.Contains(t, view, "Kept: lifetime coins")
+}
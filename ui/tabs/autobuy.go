@@ -0,0 +1,204 @@
+package tabs
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/economy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme"
+)
+
+// Rows of the auto-buyer tab; the priority list follows them.
+const (
+	autoBuyRowEnabled = iota
+	autoBuyRowPolicy
+	autoBuyRowReserve
+	autoBuyRowItems
+)
+
+// reserveSteps are the reserve shares Enter cycles through.
+var reserveSteps = []float64{0, 0.1, 0.2, 0.3, 0.5, 0.75, autobuy.MaxReserve}
+
+// policyHelp describes each policy in one line.
+var policyHelp = map[autobuy.Policy]string{
+	autobuy.PolicyCheapest: "Buys the cheapest item that adds CPS.",
+	autobuy.PolicyPayback:  "Buys the item that pays for itself soonest.",
+	autobuy.PolicyPriority: "Buys the list below in rounds, one of each top first, saving up for each in turn.",
+}
+
+// autoBuyItem is an entry of the priority list.
+type autoBuyItem struct {
+	id, name string
+}
+
+// AutoBuyTabModel is the Auto-[B]uyer tab: unlocking the auto-buyer and its
+// settings for one world.
+type AutoBuyTabModel struct {
+	eng     *engine.Engine
+	worldID string
+	t       theme.Theme
+	width   int
+	height  int
+	cursor  int
+}
+
+// NewAutoBuyTab constructs an AutoBuyTabModel for the given world.
+func NewAutoBuyTab(eng *engine.Engine, worldID string, t theme.Theme, width, height int) AutoBuyTabModel {
+	return AutoBuyTabModel{eng: eng, worldID: worldID, t: t, width: width, height: height}
+}
+
+// Resize returns a copy with updated dimensions.
+func (m AutoBuyTabModel) Resize(w, h int) AutoBuyTabModel {
+	m.width = w
+	m.height = h
+	return m
+}
+
+func (m AutoBuyTabModel) Init() tea.Cmd { return nil }
+
+// items returns every buy-on and upgrade of the world, for the priority list.
+func (m AutoBuyTabModel) items() []autoBuyItem {
+	reg, ok := m.eng.UpgradeReg[m.worldID]
+	if !ok {
+		return nil
+	}
+	var items []autoBuyItem
+	for _, b := range reg.ListBuyOns() {
+		items = append(items, autoBuyItem{b.ID(), b.Name()})
+	}
+	for _, u := range reg.ListUpgrades() {
+		items = append(items, autoBuyItem{u.ID, u.Name + " (upgrade)"})
+	}
+	return items
+}
+
+// rows returns how many rows the cursor moves over.
+func (m AutoBuyTabModel) rows() int {
+	if m.eng.State.AutoBuyer.World(m.worldID).Policy != autobuy.PolicyPriority {
+		return autoBuyRowItems
+	}
+	return autoBuyRowItems + len(m.items())
+}
+
+func (m AutoBuyTabModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if !m.eng.AutoBuyerUnlocked() {
+		if _, ok := msg.(messages.NavConfirmMsg); ok {
+			m.eng.UnlockAutoBuyer()
+		}
+		return m, nil
+	}
+
+	switch msg.(type) {
+	case messages.NavUpMsg:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case messages.NavDownMsg:
+		if m.cursor < m.rows()-1 {
+			m.cursor++
+		}
+	case messages.NavConfirmMsg:
+		m.activate()
+	}
+	return m, nil
+}
+
+// activate changes the setting under the cursor.
+func (m *AutoBuyTabModel) activate() {
+	s := m.eng.State.AutoBuyer.Clone()
+	ws := s.World(m.worldID)
+	switch m.cursor {
+	case autoBuyRowEnabled:
+		ws.Enabled = !ws.Enabled
+	case autoBuyRowPolicy:
+		i := slices.Index(autobuy.Policies, ws.Policy)
+		ws.Policy = autobuy.Policies[(i+1)%len(autobuy.Policies)]
+	case autoBuyRowReserve:
+		next := reserveSteps[0]
+		for _, step := range reserveSteps {
+			if step > s.Reserve+1e-9 {
+				next = step
+				break
+			}
+		}
+		s.Reserve = next
+	default:
+		items := m.items()
+		idx := m.cursor - autoBuyRowItems
+		if idx >= len(items) {
+			return
+		}
+		id := items[idx].id
+		if i := slices.Index(ws.Priority, id); i >= 0 {
+			ws.Priority = slices.Delete(ws.Priority, i, i+1)
+		} else {
+			ws.Priority = append(ws.Priority, id)
+		}
+	}
+	s.Worlds[m.worldID] = ws
+	m.eng.ConfigureAutoBuyer(s)
+	m.cursor = min(m.cursor, m.rows()-1)
+}
+
+func (m AutoBuyTabModel) View() string {
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	primary := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.PrimaryText()))
+	accent := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor())).Bold(true)
+
+	var sb strings.Builder
+	p := m.eng.State.Player
+	if !m.eng.AutoBuyerUnlocked() {
+		sb.WriteString(primary.Render("  The auto-buyer buys buy-ons and upgrades for you.") + "\n")
+		sb.WriteString(dim.Render(fmt.Sprintf("  It unlocks at level %d (you are level %d), or now for general coins.",
+			autobuy.UnlockLevel, p.Level)) + "\n\n")
+		line := fmt.Sprintf("  [ENTER] Unlock for %s GC", economy.FormatCoinsBare(autobuy.UnlockCost))
+		if p.GeneralCoins < autobuy.UnlockCost {
+			sb.WriteString(dim.Render(line + fmt.Sprintf("  (you have %s GC)", economy.FormatCoinsBare(p.GeneralCoins))))
+		} else {
+			sb.WriteString(accent.Render(line))
+		}
+		return sb.String()
+	}
+
+	s := m.eng.State.AutoBuyer
+	ws := s.World(m.worldID)
+	row := func(i int, label, value string) {
+		prefix, style := "    ", primary
+		if i == m.cursor {
+			prefix, style = "  > ", accent
+		}
+		sb.WriteString(style.Render(prefix+fmt.Sprintf("%-17s ", label)) + value + "\n")
+	}
+
+	name := m.worldID
+	if w, ok := m.eng.WorldReg.Get(m.worldID); ok {
+		name = w.Name()
+	}
+	state := "OFF"
+	if ws.Enabled {
+		state = "ON"
+	}
+	row(autoBuyRowEnabled, "Auto-buy in "+name+":", state)
+	row(autoBuyRowPolicy, "Policy:", string(ws.Policy))
+	row(autoBuyRowReserve, "Keep in reserve:", fmt.Sprintf("%.0f%% of the balance (all worlds)", s.Reserve*100))
+	sb.WriteString("\n" + dim.Render("  "+policyHelp[ws.Policy]) + "\n")
+
+	if ws.Policy == autobuy.PolicyPriority {
+		sb.WriteString(dim.Render("  [ENTER] adds an item to the end of the list or takes it off.") + "\n\n")
+		for i, it := range m.items() {
+			rank := "   "
+			if r := slices.Index(ws.Priority, it.id); r >= 0 {
+				rank = fmt.Sprintf("%2d.", r+1)
+			}
+			row(autoBuyRowItems+i, rank+" "+it.name, "")
+		}
+	}
+	sb.WriteString("\n" + dim.Render("  [ENTER] changes the selected setting. Purchases are listed on the dashboard."))
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package tabs
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clicker-org/clicker/internal/achievement"
+	"github.com/clicker-org/clicker/internal/autobuy"
+	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/gamestate"
+	"github.com/clicker-org/clicker/internal/world"
+	"github.com/clicker-org/clicker/ui/messages"
+	"github.com/clicker-org/clicker/ui/theme/themes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoBuyTab_UnlockAndConfigure(t *testing.T) {
+	tab := newTestAutoBuyTab(t)
+	tab.eng.State.Player.GeneralCoins = autobuy.UnlockCost
+
+	tab = updateAutoBuyTab(t, tab, messages.NavConfirmMsg{})
+	require.True(t, tab.eng.AutoBuyerUnlocked())
+	assert.Zero(t, tab.eng.State.Player.GeneralCoins)
+
+	tab = updateAutoBuyTab(t, tab, messages.NavConfirmMsg{})
+	assert.True(t, tab.eng.State.AutoBuyer.World("terra").Enabled)
+
+	// payback → priority, then put the second buy-on first in the list.
+	tab = updateAutoBuyTab(t, tab, messages.NavDownMsg{})
+	tab = updateAutoBuyTab(t, tab, messages.NavConfirmMsg{})
+	assert.Equal(t, autobuy.PolicyPriority, tab.eng.State.AutoBuyer.World("terra").Policy)
+	for i := 0; i < 3; i++ {
+		tab = updateAutoBuyTab(t, tab, messages.NavDownMsg{})
+	}
+	tab = updateAutoBuyTab(t, tab, messages.NavConfirmMsg{})
+	assert.Equal(t, []string{"drill_bot"}, tab.eng.State.AutoBuyer.World("terra").Priority)
+	assert.Contains(t, tab.View(), " 1. Drill Bot")
+
+	tab = updateAutoBuyTab(t, tab, messages.NavConfirmMsg{})
+	assert.Empty(t, tab.eng.State.AutoBuyer.World("terra").Priority, "Enter again takes it off")
+}
+
+func TestAutoBuyTab_ReserveCycles(t *testing.T) {
+	tab := newTestAutoBuyTab(t)
+	tab.eng.State.AutoBuyer.Unlocked = true
+	tab.cursor = autoBuyRowReserve
+
+	tab = updateAutoBuyTab(t, tab, messages.NavConfirmMsg{})
+	assert.Equal(t, 0.3, tab.eng.State.AutoBuyer.Reserve)
+	tab.eng.State.AutoBuyer.Reserve = autobuy.MaxReserve
+	tab = updateAutoBuyTab(t, tab, messages.NavConfirmMsg{})
+	assert.Equal(t, 0.0, tab.eng.State.AutoBuyer.Reserve, "wraps around")
+}
+
+func newTestAutoBuyTab(t *testing.T) AutoBuyTabModel {
+	t.Helper()
+	gs := gamestate.NewGameState()
+	for _, w := range world.DefaultRegistry.List() {
+		gs.Worlds[w.ID()] = world.NewWorldState(w.ID(), w.BaseExchangeRate())
+	}
+	eng := engine.New(gs, world.DefaultRegistry, achievement.NewAchievementRegistry())
+	return NewAutoBuyTab(eng, "terra", themes.SpaceTheme{}, 100, 30)
+}
+
+func updateAutoBuyTab(t *testing.T, m AutoBuyTabModel, msg tea.Msg) AutoBuyTabModel {
+	t.Helper()
+	updated, _ := m.Update(msg)
+	tab, ok := updated.(AutoBuyTabModel)
+	if !ok {
+		t.Fatalf("expected AutoBuyTabModel, got %T", updated)
+	}
+	return tab
+}
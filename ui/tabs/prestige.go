@@ -2,12 +2,16 @@ package tabs
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/clicker-org/clicker/internal/config"
 	"github.com/clicker-org/clicker/internal/economy"
 	"github.com/clicker-org/clicker/internal/engine"
+	"github.com/clicker-org/clicker/internal/prestigetree"
+	"github.com/clicker-org/clicker/internal/runs"
 	"github.com/clicker-org/clicker/ui/components"
 	"github.com/clicker-org/clicker/ui/messages"
 	"github.com/clicker-org/clicker/ui/theme"
@@ -26,6 +30,14 @@ type PrestigeTabModel struct {
 	height  int
 
 	progressBar components.ProgressBar
+
+	// runCursor is the challenge run selected in runs.Definitions.
+	runCursor int
+
+	// treeOpen shows the world's prestige tree instead of the overview;
+	// treeCursor is the row selected in it.
+	treeOpen   bool
+	treeCursor int
 }
 
 // modalInnerWidth mirrors the width math used by components.TabModal so
@@ -75,6 +87,14 @@ func (m PrestigeTabModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var barCmd tea.Cmd
 	m.progressBar, barCmd = m.progressBar.Update(msg)
 
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && (keyMsg.String() == "t" || keyMsg.String() == "T") {
+		m.treeOpen = !m.treeOpen
+		return m, barCmd
+	}
+	if m.treeOpen {
+		return m.updateTree(msg), barCmd
+	}
+
 	if _, ok := msg.(messages.NavConfirmMsg); ok {
 		if m.eng.CanPrestige(m.worldID) {
 			return m, func() tea.Msg { return messages.PrestigeConfirmRequestedMsg{} }
@@ -82,23 +102,68 @@ func (m PrestigeTabModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, barCmd
 	}
 
+	switch msg.(type) {
+	case messages.NavUpMsg:
+		if m.runCursor > 0 {
+			m.runCursor--
+		}
+		return m, barCmd
+	case messages.NavDownMsg:
+		if m.runCursor < len(runs.Definitions)-1 {
+			m.runCursor++
+		}
+		return m, barCmd
+	}
+
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch keyMsg.String() {
 		case "e", "E":
 			if m.eng.CanExchangeBoost(m.worldID) {
 				return m, func() tea.Msg { return messages.ExchangeBoostConfirmRequestedMsg{} }
 			}
+		case "r", "R":
+			if _, running := m.eng.State.Runs.Active[m.worldID]; running {
+				return m, func() tea.Msg { return messages.RunAbandonConfirmRequestedMsg{} }
+			}
+			id := runs.Definitions[m.runCursor].ID
+			if m.eng.CanStartRun(m.worldID, id) {
+				return m, func() tea.Msg { return messages.RunStartConfirmRequestedMsg{RunID: id} }
+			}
 		}
 	}
 
 	return m, barCmd
 }
 
+// updateTree handles navigation in the prestige tree: up/down select a
+// node and Enter buys it.
+func (m PrestigeTabModel) updateTree(msg tea.Msg) PrestigeTabModel {
+	rows := prestigetree.Layout(m.eng.PrestigeTree(m.worldID))
+	switch msg.(type) {
+	case messages.NavUpMsg:
+		if m.treeCursor > 0 {
+			m.treeCursor--
+		}
+	case messages.NavDownMsg:
+		if m.treeCursor < len(rows)-1 {
+			m.treeCursor++
+		}
+	case messages.NavConfirmMsg:
+		if m.treeCursor < len(rows) {
+			m.eng.BuyPrestigeNode(m.worldID, rows[m.treeCursor].Node.ID)
+		}
+	}
+	return m
+}
+
 func (m PrestigeTabModel) View() string {
 	ws := m.eng.State.Worlds[m.worldID]
 	if ws == nil {
 		return "  No world data."
 	}
+	if m.treeOpen {
+		return m.treeView(ws.PrestigePoints, ws.PrestigeTree)
+	}
 
 	w, hasWorld := m.eng.WorldReg.Get(m.worldID)
 
@@ -137,45 +202,62 @@ func (m PrestigeTabModel) View() string {
 		primarySt.Render(fmt.Sprintf("Multiplier: %s",
 			accentSt.Bold(true).Render(fmt.Sprintf("%.2f×", ws.PrestigeMultiplier)))),
 	))
+	sb.WriteString(fmt.Sprintf("  %s   %s\n",
+		primarySt.Render(fmt.Sprintf("Prestige Points: %s",
+			accentSt.Bold(true).Render(fmt.Sprintf("%d PP", ws.PrestigePoints)))),
+		successSt.Bold(true).Render("[T]")+primarySt.Render(" Upgrade tree"),
+	))
 	sb.WriteString("\n")
 
 	// ── Progress bar ───────────────────────────────────────────────────
-	current, threshold := m.eng.PrestigeProgress(m.worldID)
-	pct := 0.0
-	if threshold > 0 {
-		pct = min(current/threshold, 1.0)
-	}
+	progress := m.eng.PrestigeProgress(m.worldID)
+	pct := progress.Fraction()
 
 	canPrestige := m.eng.CanPrestige(m.worldID)
 	var progressLabel string
-	if canPrestige {
+	switch {
+	case canPrestige:
 		progressLabel = successSt.Bold(true).Render("READY TO PRESTIGE!")
-	} else {
-		progressLabel = dimSt.Render(fmt.Sprintf("%s / %s %s",
-			economy.FormatCoinsBare(current),
-			economy.FormatCoinsBare(threshold),
-			coinSymbol,
-		))
+	case len(progress.Conditions) == 1:
+		progressLabel = dimSt.Render(conditionText(progress.Conditions[0], coinSymbol))
+	case progress.AnyOf:
+		progressLabel = dimSt.Render("Any one of:")
+	default:
+		progressLabel = dimSt.Render("All of:")
 	}
 	// progressBarPrefix + m.progressBar.View(pct) fits exactly within innerW.
 	sb.WriteString(progressBarPrefix + m.progressBar.View(pct) + "\n")
 	sb.WriteString(strings.Repeat(" ", len(progressBarPrefix)) + progressLabel + "\n")
+	if len(progress.Conditions) > 1 {
+		for _, c := range progress.Conditions {
+			mark := dimSt.Render("·")
+			if c.Met() {
+				mark = successSt.Render("✓")
+			}
+			sb.WriteString(fmt.Sprintf("%s%s %s\n", strings.Repeat(" ", len(progressBarPrefix)+2), mark, dimSt.Render(conditionText(c, coinSymbol))))
+		}
+	}
 	sb.WriteString("\n")
 
 	// ── Prestige reward preview ────────────────────────────────────────
-	preview := economy.CalculatePrestigeReward(ws.TotalCoinsEarned, ws.PrestigeCount, ws.PrestigeMultiplier)
+	preview := m.eng.PrestigeRewardPreview(m.worldID)
 	sb.WriteString(dimSt.Render("  Next prestige reward:") + "\n")
-	sb.WriteString(fmt.Sprintf("    %s   %s   %s\n",
+	sb.WriteString(fmt.Sprintf("    %s   %s   %s   %s\n",
 		coinSt.Render(fmt.Sprintf("+%s GC", economy.FormatCoinsBare(preview.GeneralCoinsEarned))),
 		accentSt.Render(fmt.Sprintf("×%.2f multiplier", preview.PrestigeMultiplier)),
 		successSt.Render(fmt.Sprintf("+%d XP", preview.XPGrant)),
+		accentSt.Render(fmt.Sprintf("+%d PP", preview.PrestigePoints)),
 	))
 	sb.WriteString("\n")
 
 	// Prestige action hint.
-	if canPrestige {
+	_, running := m.eng.State.Runs.Active[m.worldID]
+	switch {
+	case canPrestige:
 		sb.WriteString("  " + warnSt.Bold(true).Render("[Enter]") + primarySt.Render(" Prestige (resets world)") + "\n")
-	} else {
+	case running:
+		sb.WriteString("  " + dimSt.Render("[Enter] Prestige (not during a challenge run)") + "\n")
+	default:
 		sb.WriteString("  " + dimSt.Render("[Enter] Prestige (not yet available)") + "\n")
 	}
 
@@ -201,11 +283,22 @@ func (m PrestigeTabModel) View() string {
 		sb.WriteString("\n")
 		sb.WriteString("  " + successSt.Bold(true).Render("[E]") + primarySt.Render(" Exchange Boost (no reset)") + "\n")
 	} else {
-		sb.WriteString(dimSt.Render("  No coins to exchange.") + "\n")
+		if ws.BoostCooldown > 0 && ws.BoostableCoins() > 0 {
+			sb.WriteString(dimSt.Render(fmt.Sprintf("  Next boost in %.0fs.", math.Ceil(ws.BoostCooldown))) + "\n")
+		} else {
+			sb.WriteString(dimSt.Render("  No coins to exchange.") + "\n")
+		}
 		sb.WriteString("\n")
 		sb.WriteString("  " + dimSt.Render("[E] Exchange Boost (no reset)") + "\n")
 	}
 
+	sb.WriteString("\n")
+	sb.WriteString(divider + "\n")
+	sb.WriteString("\n")
+
+	// ── Section 3: Challenge runs ──────────────────────────────────────
+	sb.WriteString(m.runsView(coinSymbol))
+
 	sb.WriteString("\n")
 	sb.WriteString("  " + dimSt.Render(fmt.Sprintf("General Coins: %s",
 		lipgloss.NewStyle().Foreground(coin).Bold(true).Render(
@@ -215,3 +308,172 @@ func (m PrestigeTabModel) View() string {
 
 	return sb.String()
 }
+
+// conditionText describes the progress towards a prestige condition, with
+// world coins shown in coinSymbol.
+func conditionText(c engine.PrestigeCondition, coinSymbol string) string {
+	switch c.Metric {
+	case config.PrestigeMetricCoinsEarned:
+		return fmt.Sprintf("%s / %s %s earned", economy.FormatCoinsBare(c.Current), economy.FormatCoinsBare(c.Target), coinSymbol)
+	case config.PrestigeMetricBuyOnsOwned:
+		return fmt.Sprintf("%.0f / %.0f buy-ons owned", c.Current, c.Target)
+	case config.PrestigeMetricCompletionPercent:
+		return fmt.Sprintf("%.0f%% / %.0f%% complete", c.Current, c.Target)
+	}
+	return fmt.Sprintf("%s: %.0f / %.0f", c.Metric, c.Current, c.Target)
+}
+
+// runsView renders the challenge run selector: every run with its bonus and
+// the world's record, the selected run's rules, and the run in progress.
+func (m PrestigeTabModel) runsView(coinSymbol string) string {
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor()))
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	primarySt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.PrimaryText()))
+	successSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.WarningColor()))
+
+	rs := m.eng.State.Runs
+	var sb strings.Builder
+	sb.WriteString(primarySt.Bold(true).Render("  Challenge Runs") +
+		dimSt.Render(fmt.Sprintf("   bonus ×%.2f CPS", rs.Multiplier(m.worldID))) + "\n")
+	sb.WriteString("\n")
+
+	active, running := rs.Active[m.worldID]
+	for i, d := range runs.Definitions {
+		cursor := "  "
+		if i == m.runCursor {
+			cursor = accentSt.Render("▸ ")
+		}
+		rec := rs.Record(m.worldID, d.ID)
+		var status string
+		switch {
+		case running && active.ID == d.ID:
+			status = warnSt.Render("in progress")
+		case rec.Completed:
+			status = successSt.Render("✓ best " + economy.FormatSeconds(rec.BestSeconds))
+		case rec.Attempts > 0:
+			status = dimSt.Render(fmt.Sprintf("%d attempt(s)", rec.Attempts))
+		default:
+			status = dimSt.Render("not attempted")
+		}
+		sb.WriteString(fmt.Sprintf("  %s%s %s  %s\n",
+			cursor,
+			primarySt.Render(fmt.Sprintf("%-18s", d.Name)),
+			accentSt.Render(fmt.Sprintf("+%2.0f%% CPS", d.Bonus*100)),
+			status,
+		))
+	}
+	sb.WriteString(dimSt.Render("    "+runs.Definitions[m.runCursor].Description) + "\n")
+	sb.WriteString("\n")
+
+	if running {
+		d, _ := runs.Get(active.ID)
+		elapsed := economy.FormatSeconds(active.Elapsed)
+		if d.TimeLimit > 0 {
+			elapsed += " / " + economy.FormatSeconds(d.TimeLimit)
+		}
+		sb.WriteString(fmt.Sprintf("  %s %s   %s\n",
+			warnSt.Render(d.Name+":"),
+			primarySt.Render(fmt.Sprintf("%s / %s %s",
+				economy.FormatCoinsBare(active.Earned), economy.FormatCoinsBare(active.Goal), coinSymbol)),
+			dimSt.Render(elapsed),
+		))
+		sb.WriteString("  " + warnSt.Bold(true).Render("[R]") + primarySt.Render(" Abandon run") + "\n")
+	} else {
+		sb.WriteString("  " + warnSt.Bold(true).Render("[R]") + primarySt.Render(" Start selected run (resets world)") + "\n")
+	}
+	return sb.String()
+}
+
+// treeView renders the world's prestige tree: every node under its first
+// prerequisite with its cost and status, and the selected node's details.
+func (m PrestigeTabModel) treeView(points int, owned map[string]bool) string {
+	accentSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.AccentColor()))
+	dimSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.DimText()))
+	primarySt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.PrimaryText()))
+	successSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor()))
+	warnSt := lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.WarningColor()))
+
+	var sb strings.Builder
+	sb.WriteString(primarySt.Bold(true).Render("  Prestige Tree") +
+		dimSt.Render("   Points: ") + accentSt.Bold(true).Render(fmt.Sprintf("%d PP", points)) + "\n")
+	sb.WriteString(dimSt.Render("  Kept across this world's prestiges.") + "\n")
+	sb.WriteString("\n")
+
+	rows := prestigetree.Layout(m.eng.PrestigeTree(m.worldID))
+	if len(rows) == 0 {
+		sb.WriteString(dimSt.Render("  This world has no prestige tree.") + "\n")
+	}
+	names := make(map[string]string, len(rows))
+	for _, r := range rows {
+		names[r.Node.ID] = r.Node.Name
+	}
+	// lastAt[d] is set while the rows at depth d are their parent's last.
+	var lastAt []bool
+	for i, r := range rows {
+		lastAt = append(lastAt[:r.Depth], r.Last)
+		var prefix strings.Builder
+		for d := 1; d < r.Depth; d++ {
+			if lastAt[d] {
+				prefix.WriteString("   ")
+			} else {
+				prefix.WriteString("│  ")
+			}
+		}
+		if r.Depth > 0 {
+			if r.Last {
+				prefix.WriteString("└─ ")
+			} else {
+				prefix.WriteString("├─ ")
+			}
+		}
+
+		cursor := "  "
+		if i == m.treeCursor {
+			cursor = accentSt.Render("▸ ")
+		}
+		var mark string
+		var nameSt lipgloss.Style
+		switch prestigetree.StatusOf(r.Node, owned) {
+		case prestigetree.StatusOwned:
+			mark, nameSt = successSt.Render("●"), successSt
+		case prestigetree.StatusAvailable:
+			mark, nameSt = accentSt.Render("◆"), primarySt
+			if r.Node.Cost > points {
+				mark = warnSt.Render("◆")
+			}
+		default:
+			mark, nameSt = dimSt.Render("○"), dimSt
+		}
+		line := fmt.Sprintf("  %s%s%s %s %s", cursor, dimSt.Render(prefix.String()), mark,
+			nameSt.Render(r.Node.Name), dimSt.Render(fmt.Sprintf("%d PP", r.Node.Cost)))
+		if len(r.Also) > 0 {
+			also := make([]string, len(r.Also))
+			for j, id := range r.Also {
+				also[j] = names[id]
+			}
+			line += dimSt.Render(" + " + strings.Join(also, ", "))
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	if m.treeCursor < len(rows) {
+		n := rows[m.treeCursor].Node
+		sb.WriteString("\n")
+		sb.WriteString("  " + primarySt.Bold(true).Render(n.Name) + dimSt.Render(" — "+n.Description) + "\n")
+		switch {
+		case owned[n.ID]:
+			sb.WriteString("  " + successSt.Render("Owned") + "\n")
+		case m.eng.CanBuyPrestigeNode(m.worldID, n.ID):
+			sb.WriteString("  " + warnSt.Bold(true).Render("[Enter]") + primarySt.Render(fmt.Sprintf(" Buy for %d PP", n.Cost)) + "\n")
+		case prestigetree.StatusOf(n, owned) == prestigetree.StatusLocked:
+			sb.WriteString("  " + dimSt.Render("Requires the nodes above it first") + "\n")
+		default:
+			sb.WriteString("  " + dimSt.Render(fmt.Sprintf("[Enter] Buy for %d PP (not enough points)", n.Cost)) + "\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString("  " + dimSt.Render("● owned  ◆ available  ○ locked    [T] Back") + "\n")
+	return sb.String()
+}
@@ -14,10 +14,12 @@ import (
 )
 
 // linesPerCard is the number of terminal rows a single buy-on card occupies:
-// top border + 3 content rows + bottom border.
-const linesPerCard = 5
+// top border + 4 content rows + bottom border.
+const linesPerCard = 6
 
-// ShopTabModel is the [S]hop tab: a scrollable list of buy-on cards.
+// ShopTabModel is the [S]hop tab: a scrollable list of buy-on cards, each
+// with the engine's payback and time-to-afford hints and the best value
+// highlighted.
 type ShopTabModel struct {
 	eng     *engine.Engine
 	worldID string
@@ -134,8 +136,6 @@ func (m ShopTabModel) View() string {
 		return "  No items available."
 	}
 
-	playerLevel := m.eng.State.Player.Level
-
 	coinSymbol := ""
 	if w, ok := m.eng.WorldReg.Get(m.worldID); ok {
 		coinSymbol = w.CoinSymbol()
@@ -152,6 +152,7 @@ func (m ShopTabModel) View() string {
 		cardContentW = 30
 	}
 
+	hints := m.eng.ShopHints(m.worldID)
 	visCount := m.visibleCount()
 	end := m.scroll + visCount
 	if end > len(items) {
@@ -170,12 +171,11 @@ func (m ShopTabModel) View() string {
 	for i := m.scroll; i < end; i++ {
 		b := items[i]
 		count := ws.BuyOnCounts[b.ID()]
-		cost := upgrade.CostForNext(b, count)
-		locked := b.LevelRequirement() > playerLevel
+		hint := hints[i]
 		selected := i == m.cursor
-		canAfford := ws.Coins >= cost
+		canAfford := ws.Coins >= hint.Cost
 
-		sb.WriteString(m.renderCard(i, b, cost, count, coinSymbol, locked, selected, canAfford, cardContentW))
+		sb.WriteString(m.renderCard(i, b, hint, count, coinSymbol, selected, canAfford, cardContentW))
 		if i < end-1 {
 			sb.WriteString("\n")
 		}
@@ -191,29 +191,34 @@ func (m ShopTabModel) View() string {
 	return sb.String()
 }
 
-// renderCard builds the 5-line bordered card string for a single buy-on.
+// renderCard builds the 6-line bordered card string for a single buy-on.
 func (m ShopTabModel) renderCard(
 	idx int,
 	b upgrade.BuyOn,
-	cost float64,
+	hint engine.BuyOnHint,
 	count int,
 	coinSymbol string,
-	locked, selected, canAfford bool,
+	selected, canAfford bool,
 	contentW int,
 ) string {
+	// a buy-on barred by the challenge run in progress is shown as locked.
+	locked, cost := hint.Locked || hint.Barred, hint.Cost
 	dim := lipgloss.Color(m.t.DimText())
 	primary := lipgloss.Color(m.t.PrimaryText())
 	accent := lipgloss.Color(m.t.AccentColor())
 	coinC := lipgloss.Color(m.t.CoinColor())
 	errC := lipgloss.Color(m.t.ErrorColor())
 
-	// Border color: accent when selected, white for normal, dim for locked.
+	// Border color: accent when selected, success for the best value, white
+	// for normal, dim for locked.
 	var borderHex string
 	switch {
 	case locked:
 		borderHex = m.t.DimText()
 	case selected:
 		borderHex = m.t.AccentColor()
+	case hint.BestValue:
+		borderHex = m.t.SuccessColor()
 	default:
 		borderHex = "#ffffff"
 	}
@@ -245,7 +250,7 @@ func (m ShopTabModel) renderCard(
 	shortcutRender := lipgloss.NewStyle().Foreground(dim).Render(shortcut)
 
 	var row1Right string
-	if locked {
+	if hint.Locked {
 		lvlText := fmt.Sprintf("LVL: %d", b.LevelRequirement())
 		row1Right = lipgloss.NewStyle().Foreground(errC).Render(lvlText)
 	} else {
@@ -259,7 +264,7 @@ func (m ShopTabModel) renderCard(
 	descText := shopTruncStr(b.Description(), leftW-1)
 	descRender := lipgloss.NewStyle().Foreground(dim).Render(descText)
 
-	cpsText := "+" + economy.FormatCPS(b.BaseCPS()) + " CPS"
+	cpsText := "+" + economy.FormatCPS(hint.MarginalCPS) + " CPS"
 	var cpsC lipgloss.Color
 	if locked {
 		cpsC = dim
@@ -286,7 +291,11 @@ func (m ShopTabModel) renderCard(
 	var row3 string
 	switch {
 	case locked:
-		lockRender := lipgloss.NewStyle().Foreground(errC).Bold(true).Render("[LOCKED]")
+		label := "[LOCKED]"
+		if !hint.Locked {
+			label = "[BARRED]"
+		}
+		lockRender := lipgloss.NewStyle().Foreground(errC).Bold(true).Render(label)
 		row3 = shopPadVisual(" "+costRender, leftW) + shopPadVisual(lockRender, rightW)
 	case selected:
 		hintRender := lipgloss.NewStyle().Foreground(dim).Render("[ENTER]")
@@ -295,6 +304,22 @@ func (m ShopTabModel) renderCard(
 		row3 = shopPadVisual(" "+costRender, contentW)
 	}
 
+	// ── Row 4: Payback and time to afford (left) │ best value (right) ───
+	var hintText string
+	switch {
+	case locked:
+		hintText = "Payback " + economy.FormatSeconds(hint.PaybackSeconds)
+	case hint.AffordSeconds == 0:
+		hintText = "Payback " + economy.FormatSeconds(hint.PaybackSeconds) + " · affordable now"
+	default:
+		hintText = "Payback " + economy.FormatSeconds(hint.PaybackSeconds) + " · affordable in " + economy.FormatSeconds(hint.AffordSeconds)
+	}
+	hintRender := lipgloss.NewStyle().Foreground(dim).Render(shopTruncStr(hintText, leftW-1))
+	row4 := shopPadVisual(" "+hintRender, leftW)
+	if hint.BestValue {
+		row4 += shopPadVisual(lipgloss.NewStyle().Foreground(lipgloss.Color(m.t.SuccessColor())).Bold(true).Render("★ BEST"), rightW)
+	}
+
 	// ── Assemble bordered card ─────────────────────────────────────────
 	borderSt := lipgloss.NewStyle().Foreground(borderC)
 	top := borderSt.Render("┌" + strings.Repeat("─", contentW) + "┐")
@@ -312,6 +337,7 @@ func (m ShopTabModel) renderCard(
 		makeRow(row1),
 		makeRow(row2),
 		makeRow(row3),
+		makeRow(row4),
 		bot,
 	}, "\n")
 }